@@ -13,6 +13,7 @@ import (
 	"github.com/coldzerofear/device-mounter/pkg/client"
 	"github.com/coldzerofear/device-mounter/pkg/config"
 	"github.com/coldzerofear/device-mounter/pkg/filewatch"
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
 	"github.com/coldzerofear/device-mounter/pkg/server/apiserver"
 	"github.com/coldzerofear/device-mounter/pkg/tlsconfig"
 	"github.com/coldzerofear/device-mounter/pkg/versions"
@@ -22,8 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	"k8s.io/client-go/informers"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/klog/v2"
+	cache2 "sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 const (
@@ -33,6 +36,8 @@ const (
 
 	configDir      = "/config"
 	TlsProfileFile = "tls-profile-v1alpha1.yaml"
+
+	caName = "ca.crt"
 )
 
 var (
@@ -46,6 +51,10 @@ var (
 	MounterBindPort  = ":1200"
 	MounterNamespace = "kube-system"
 	MounterSelector  = ""
+	MetricsAddr      = ":8769"
+	AuthorizeTypes   = false
+	MounterTLSDir    = ""
+	MounterTLSName   = "device-mounter"
 )
 
 func initFlags(fs *flag.FlagSet) {
@@ -58,6 +67,10 @@ func initFlags(fs *flag.FlagSet) {
 	pflag.StringVar(&MounterBindPort, "mounter-bind-address", MounterBindPort, "Device Mounter TCP port bound to GRPC service")
 	pflag.StringVar(&MounterNamespace, "mounter-pod-namespace", MounterNamespace, "The namespace of the device mounter pod")
 	pflag.StringVar(&MounterSelector, "mounter-label-selector", MounterSelector, "Specify the label selector for the device mounter pod")
+	pflag.StringVar(&MounterTLSDir, "mounter-tls-cert-dir", MounterTLSDir, "The directory of the client certificate (tls.crt, tls.key and ca.crt) for mutual TLS with the device mounter, empty to disable.")
+	pflag.StringVar(&MounterTLSName, "mounter-tls-server-name", MounterTLSName, "The server name in the certificate of the device mounter.")
+	pflag.StringVar(&MetricsAddr, "metrics-bind-address", MetricsAddr, "The address the prometheus metrics endpoint binds to, empty to disable.")
+	pflag.BoolVar(&AuthorizeTypes, "authorize-device-types", AuthorizeTypes, "Authorize the device types of the mount and unmount requests with SubjectAccessReview.")
 	pflag.BoolVar(&debug, "debug", false, "Enable pprof endpoint")
 	pflag.BoolVar(&version, "version", false, "Print version information and quit.")
 	pflag.CommandLine.AddGoFlagSet(fs)
@@ -109,6 +122,18 @@ func main() {
 		klog.Exitln(err)
 	}
 
+	var mounterTLS *apiserver.MounterTLS
+	if len(MounterTLSDir) > 0 {
+		mounterTLSWatch := tlsconfig.NewMutualWatch(MounterTLSDir, certName, keyName, caName, nil)
+		mounterTLSWatch.Reload()
+		if err = mounterTLSWatch.AddToFilewatch(watch); err != nil {
+			klog.Exitln(err)
+		}
+		mounterTLS = &apiserver.MounterTLS{Watch: mounterTLSWatch, ServerName: MounterTLSName}
+	} else {
+		klog.Warningln("Mutual TLS with the device mounter is disabled")
+	}
+
 	watchDone := make(chan struct{})
 	defer close(watchDone)
 	go func() {
@@ -128,11 +153,36 @@ func main() {
 		})
 		klog.Infoln("Using default label selectors to find device mounter", selector.String())
 	}
-	handlers, err := apiserver.NewService(kubeClient, authConfigReader, MounterBindPort, MounterNamespace, selector)
+
+	klog.Infoln("Initialize the pod informers...")
+	// The target pods of all namespaces, only the fields used by the apiserver are cached.
+	podFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithTransform(apiserver.TransformTargetPod))
+	podInformer := podFactory.Core().V1().Pods().Informer()
+	mounterFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithNamespace(MounterNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}),
+		informers.WithTransform(cache2.TransformStripManagedFields()))
+	mounterInformer := mounterFactory.Core().V1().Pods().Informer()
+
+	handlers, err := apiserver.NewService(kubeClient, podInformer, mounterInformer, authConfigReader,
+		AuthorizeTypes, MounterBindPort, MounterNamespace, selector, mounterTLS)
 	if err != nil {
 		klog.Exitln(err)
 	}
+	informerDone := make(chan struct{})
+	defer close(informerDone)
+	podFactory.Start(informerDone)
+	mounterFactory.Start(informerDone)
+	podFactory.WaitForCacheSync(informerDone)
+	mounterFactory.WaitForCacheSync(informerDone)
 	webServer := apiServiceV1alpha1(handlers)
+	if err = apiserver.InstallOpenAPI(restful.DefaultContainer, webServer); err != nil {
+		klog.Exitf("Failed to build the OpenAPI document: %v", err)
+	}
+	webServer.Filter(apiserver.MetricsFilter)
 	if debug {
 		klog.V(3).Infoln("enable pprof debugging information")
 		routeFunction := func(handler func(http.ResponseWriter, *http.Request)) restful.RouteFunction {
@@ -145,6 +195,9 @@ func main() {
 		webServer.Route(webServer.GET("/pprof/goroutine").To(routeFunction(pprof.Handler("goroutine").ServeHTTP)))
 	}
 	restful.Add(webServer)
+
+	metrics.RegisterAPIServerMetrics()
+	metrics.StartServer(MetricsAddr)
 	restful.Filter(restful.OPTIONSFilter())
 
 	server := &http.Server{
@@ -175,24 +228,37 @@ func apiServiceV1alpha1(handlers apiserver.APIService) *restful.WebService {
 	ws := new(restful.WebService)
 
 	// TODO 挂载设备
-	ws.Route(ws.PUT("/apis/" + v1alpha1.GroupVersion.GroupVersion + "/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/mount").
+	ws.Route(ws.PUT("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/mount").
 		To(handlers.MountDevice).
 		Doc("Mount device to container").
-		Operation(v1alpha1.Version + "MountDevices").
+		Operation(v1alpha1.Version+"MountDevices").
 		Consumes(restful.MIME_JSON).
+		Reads(apiserver.RequestMountBody{}).
 		Param(ws.PathParameter("namespace", "The namespace of the target pod").Required(true)).
 		Param(ws.PathParameter("name", "The name of the target pod").Required(true)).
 		Param(ws.QueryParameter("device_type", "Mounted device resource types").Required(true)).
 		Param(ws.QueryParameter("container", "The name of the target container").Required(false)).
 		Param(ws.QueryParameter("wait_second", "Waiting for timeout period (seconds)").
-			Required(false).DataType("integer").DefaultValue("10")))
+			Required(false).DataType("integer").DefaultValue("10")).
+		Param(ws.QueryParameter("dryRun", "When present, only validate the request and plan the slave pods. Valid values are: All").
+			Required(false)).
+		Param(ws.QueryParameter("async", "Mount in the background and return the operation id").
+			Required(false).DefaultValue("false")).
+		Param(ws.QueryParameter("request_id", "Idempotency key, a repeated request with the same key returns the original result").
+			Required(false)).
+		Param(ws.HeaderParameter(apiserver.IdempotencyKeyHeader, "Alternative to the request_id query parameter").
+			Required(false)).
+		Writes(apiserver.DeviceResult{}).
+		Returns(http.StatusOK, "OK", apiserver.DeviceResult{}).
+		Returns(http.StatusAccepted, "Accepted", apiserver.DeviceResult{}))
 
 	// TODO 卸载设备
-	ws.Route(ws.PUT("/apis/" + v1alpha1.GroupVersion.GroupVersion + "/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/unmount").
+	unmount := ws.PUT("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/unmount").
 		To(handlers.UnMountDevice).
 		Doc("UnMount device to container").
-		Operation(v1alpha1.Version + "UnMountDevices").
+		Operation(v1alpha1.Version+"UnMountDevices").
 		//Consumes(restful.MIME_JSON).
+		Reads(apiserver.RequestUnMountBody{}, "Only unmount the devices of the resources").
 		Param(ws.PathParameter("namespace", "The namespace of the target pod").Required(true)).
 		Param(ws.PathParameter("name", "The name of the target pod").Required(true)).
 		Param(ws.QueryParameter("device_type", "Mounted device resource types").Required(true)).
@@ -200,7 +266,62 @@ func apiServiceV1alpha1(handlers apiserver.APIService) *restful.WebService {
 		Param(ws.QueryParameter("wait_second", "Waiting for timeout period (seconds)").
 			Required(false).DataType("integer").DefaultValue("10")).
 		Param(ws.QueryParameter("force", "Do you want to force device uninstallation").
-			Required(false).DefaultValue("false")))
+			Required(false).DefaultValue("false")).
+		Param(ws.QueryParameter("async", "Unmount in the background and return the operation id").
+			Required(false).DefaultValue("false")).
+		Param(ws.QueryParameter("request_id", "Idempotency key, a repeated request with the same key returns the original result").
+			Required(false)).
+		Param(ws.HeaderParameter(apiserver.IdempotencyKeyHeader, "Alternative to the request_id query parameter").
+			Required(false)).
+		Writes(apiserver.DeviceResult{}).
+		Returns(http.StatusOK, "OK", apiserver.DeviceResult{}).
+		Returns(http.StatusAccepted, "Accepted", apiserver.DeviceResult{})
+	// The request body is optional, all devices of the type are unmounted without it.
+	unmount.ParameterNamed("body").Required(false)
+	ws.Route(unmount)
+
+	// TODO 查询已挂载设备
+	ws.Route(ws.GET("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/devices").
+		To(handlers.ListMountedDevices).
+		Doc("List devices mounted to container").
+		Operation(v1alpha1.Version+"ListMountedDevices").
+		Produces(restful.MIME_JSON).
+		Param(ws.PathParameter("namespace", "The namespace of the target pod").Required(true)).
+		Param(ws.PathParameter("name", "The name of the target pod").Required(true)).
+		Param(ws.QueryParameter("device_type", "Mounted device resource types").Required(false)).
+		Param(ws.QueryParameter("container", "The name of the target container").Required(false)).
+		Param(ws.QueryParameter("wait_second", "Waiting for timeout period (seconds)").
+			Required(false).DataType("integer").DefaultValue("10")).
+		Writes(apiserver.MountedDeviceList{}).
+		Returns(http.StatusOK, "OK", apiserver.MountedDeviceList{}))
+
+	// TODO 查询异步操作
+	ws.Route(ws.GET("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/operations/{id}").
+		To(handlers.GetOperation).
+		Doc("Get the asynchronous mount or unmount operation").
+		Operation(v1alpha1.Version+"GetOperation").
+		Produces(restful.MIME_JSON).
+		Param(ws.PathParameter("namespace", "The namespace of the target pod").Required(true)).
+		Param(ws.PathParameter("name", "The name of the target pod").Required(true)).
+		Param(ws.PathParameter("id", "The id of the operation").Required(true)).
+		Param(ws.QueryParameter("wait_second", "Waiting for timeout period (seconds)").
+			Required(false).DataType("integer").DefaultValue("10")).
+		Writes(apiserver.OperationResult{}).
+		Returns(http.StatusOK, "OK", apiserver.OperationResult{}))
+
+	// TODO 取消异步操作
+	ws.Route(ws.DELETE("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/operations/{id}").
+		To(handlers.CancelOperation).
+		Doc("Cancel the running asynchronous operation, the completed steps are rolled back").
+		Operation(v1alpha1.Version+"CancelOperation").
+		Produces(restful.MIME_JSON).
+		Param(ws.PathParameter("namespace", "The namespace of the target pod").Required(true)).
+		Param(ws.PathParameter("name", "The name of the target pod").Required(true)).
+		Param(ws.PathParameter("id", "The id of the operation").Required(true)).
+		Param(ws.QueryParameter("wait_second", "Waiting for timeout period (seconds)").
+			Required(false).DataType("integer").DefaultValue("10")).
+		Writes(apiserver.OperationResult{}).
+		Returns(http.StatusOK, "OK", apiserver.OperationResult{}))
 
 	// This endpoint is called by the API Server to get available resources.
 	// 由k8s api-server调用得知当前server提供的服务
@@ -222,6 +343,14 @@ func apiServiceV1alpha1(handlers apiserver.APIService) *restful.WebService {
 						Name:       "pods/unmount",
 						Namespaced: true,
 					},
+					{
+						Name:       "pods/devices",
+						Namespaced: true,
+					},
+					{
+						Name:       "pods/operations",
+						Namespaced: true,
+					},
 				},
 			}
 			response.WriteAsJson(list)
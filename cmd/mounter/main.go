@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"net"
@@ -18,20 +19,29 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
 	"github.com/coldzerofear/device-mounter/pkg/client"
 	"github.com/coldzerofear/device-mounter/pkg/config"
 	"github.com/coldzerofear/device-mounter/pkg/controller"
+	"github.com/coldzerofear/device-mounter/pkg/filewatch"
 	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
+	"github.com/coldzerofear/device-mounter/pkg/plugin"
 	"github.com/coldzerofear/device-mounter/pkg/server/mounter"
+	"github.com/coldzerofear/device-mounter/pkg/tlsconfig"
+	"github.com/coldzerofear/device-mounter/pkg/util"
 	"github.com/coldzerofear/device-mounter/pkg/versions"
 	"github.com/coldzerofear/device-mounter/pkg/watchdog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	// init device mounter
 	_ "github.com/coldzerofear/device-mounter/pkg/devices"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -45,6 +55,7 @@ import (
 
 var (
 	version      bool
+	EnableCRD    = true
 	TCPBindPort  = ":1200"
 	SocketPath   = "/var/run/device-mounter"
 	KubeConfig   = ""
@@ -53,6 +64,14 @@ var (
 	KubeBurst    = 30
 	NodeName     = os.Getenv("NODE_NAME")
 	CGroupDriver = os.Getenv("CGROUP_DRIVER")
+	MetricsAddr  = ":1201"
+	OrphanPolicy = string(controller.OrphanPolicyRemount)
+	OrphanPeriod = time.Minute
+	TLSCertDir   = ""
+	TLSAllowList = []string{"device-mounter-apiserver"}
+	SocketPolicy = mounter.PeerPolicy{AllowHost: true}
+	LockPolicy   = string(mounter.ContainerLockPolicyWait)
+	PluginDir    = ""
 )
 
 func initFlags(fs *flag.FlagSet) {
@@ -64,9 +83,23 @@ func initFlags(fs *flag.FlagSet) {
 	pflag.StringVar(&NodeName, "node-name", NodeName, "If non-empty, will use this string as identification instead of the actual node name.")
 	pflag.StringVar(&CGroupDriver, "cgroup-driver", CGroupDriver, "Specify the cgroup driver used. (supported values: \"cgroupfs\" | \"system\")")
 	pflag.StringVar(&TCPBindPort, "tcp-bind-address", TCPBindPort, "TCP port bound to GRPC service.")
+	pflag.StringVar(&TLSCertDir, "tls-cert-dir", TLSCertDir, "The directory of the server certificate (tls.crt, tls.key and ca.crt) for mutual TLS of the TCP service, empty to disable.")
+	pflag.StringSliceVar(&TLSAllowList, "tls-allowed-clients", TLSAllowList, "The common names or DNS names of the client certificates allowed to call the TCP service, empty to allow all clients issued by the CA.")
 	pflag.StringVar(&SocketPath, "socket-path", SocketPath, "Specify the directory where the socket file is located.")
+	pflag.StringVar(&PluginDir, "plugin-dir", PluginDir, "The directory of the registration socket and the sockets of the device mounter plugins, default to <socket-path>/plugins.")
+	pflag.StringSliceVar(&SocketPolicy.Namespaces, "socket-allowed-namespaces", SocketPolicy.Namespaces, "The namespaces of the pods allowed to call the unix socket service, empty to allow all pods if service accounts are not set either.")
+	pflag.StringSliceVar(&SocketPolicy.ServiceAccounts, "socket-allowed-service-accounts", SocketPolicy.ServiceAccounts, "The service accounts (namespace:name) of the pods allowed to call the unix socket service.")
+	pflag.BoolVar(&SocketPolicy.AllowHost, "socket-allow-host", SocketPolicy.AllowHost, "Allow the processes outside of pods to call the unix socket service.")
 	pflag.StringVar(&config.DeviceSlaveContainerImageTag, "device-slave-image-tag", config.DeviceSlaveContainerImageTag, "Specify the image tag for the slave container.")
 	pflag.StringVar((*string)(&config.DeviceSlaveImagePullPolicy), "device-slave-pull-policy", string(config.DeviceSlaveImagePullPolicy), "Specify the image pull policy for the slave container.")
+	pflag.StringVar(&config.GenericDeviceConfigFile, "generic-device-config", config.GenericDeviceConfigFile, "The config file declaring the device plugin resources mounted by the GENERIC device mounter, empty to disable it.")
+	pflag.StringSliceVar(&config.HostDeviceAllowedPaths, "host-device-allowed-paths", config.HostDeviceAllowedPaths, "The device paths (glob patterns) allowed to be mounted by the HOST_DEVICE device mounter, empty to disable it.")
+	pflag.StringSliceVar(&config.HostDeviceAllowedNamespaces, "host-device-allowed-namespaces", config.HostDeviceAllowedNamespaces, "The namespaces of the pods allowed to mount the host devices, \"*\" to allow all namespaces.")
+	pflag.StringVar(&MetricsAddr, "metrics-bind-address", MetricsAddr, "The address the prometheus metrics endpoint binds to, empty to disable.")
+	pflag.StringVar(&OrphanPolicy, "orphan-slave-pod-policy", OrphanPolicy, "How to handle the slave pods whose owner container has been restarted. (supported values: \"Ignore\" | \"Delete\" | \"Remount\")")
+	pflag.DurationVar(&OrphanPeriod, "orphan-slave-pod-check-period", OrphanPeriod, "The period of checking the orphaned slave pods.")
+	pflag.StringVar(&LockPolicy, "container-lock-policy", LockPolicy, "What to do when another mount or unmount operation is running on the same container. (supported values: \"Wait\" | \"Reject\")")
+	pflag.BoolVar(&EnableCRD, "enable-device-mount-controller", EnableCRD, "Enable the controller of the DeviceMount custom resource.")
 	pflag.BoolVar(&version, "version", false, "Print version information and quit.")
 	pflag.CommandLine.AddGoFlagSet(fs)
 	pflag.Parse()
@@ -139,10 +172,23 @@ func main() {
 	ctx, cancelFunc := context.WithCancel(context.TODO())
 	go podController.Start(ctx, 1)
 
+	klog.Infoln("Initialize the operation journal...")
+	opJournal, err := journal.NewJournal(filepath.Join(SocketPath, "journal"))
+	if err != nil {
+		klog.Exit(err.Error())
+	}
+
 	nodeLister := listerv1.NewNodeLister(nodeInformer.GetIndexer())
 	podLister := listerv1.NewPodLister(podInformer.GetIndexer())
+	// Validate the mount requests against the resources not requested by the pods on the node.
+	calculator := client.NewPodResourcesCapacityCalculator(proxyClient, util.NewCapacityCalculator(podLister))
+	lockPolicy, err := mounter.ParseContainerLockPolicy(LockPolicy)
+	if err != nil {
+		klog.Exit(err.Error())
+	}
+	recorder := newEventRecorder(kubeClient)
 	serverImpl := mounter.NewDeviceMounterServer(NodeName, kubeClient,
-		podLister, nodeLister, newEventRecorder(kubeClient))
+		podLister, nodeLister, calculator, recorder, opJournal, lockPolicy)
 
 	klog.Infoln("Registering Device Mounter...")
 	if err := framework.RegisrtyDeviceMounter(); err != nil {
@@ -151,20 +197,105 @@ func main() {
 	deviceTypes := framework.GetDeviceMounterTypes()
 	klog.Infoln("Successfully registered mounts include", deviceTypes)
 
+	// The unix socket service and the plugin registration socket restrict the local callers alike.
+	var unixOptions []grpc.ServerOption
+	if SocketPolicy.Enabled() {
+		if SocketPolicy.ServiceAccounts, err = mounter.ParseServiceAccounts(SocketPolicy.ServiceAccounts); err != nil {
+			klog.Exit(err.Error())
+		}
+		klog.Infoln("Restrict the callers of the unix service", "namespaces", SocketPolicy.Namespaces,
+			"serviceAccounts", SocketPolicy.ServiceAccounts, "allowHost", SocketPolicy.AllowHost)
+		unixOptions = append(unixOptions, grpc.Creds(mounter.NewPeerCredentials()),
+			grpc.UnaryInterceptor(mounter.NewPeerAuthInterceptor(podLister, &SocketPolicy)),
+			grpc.StreamInterceptor(mounter.NewPeerAuthStreamInterceptor(podLister, &SocketPolicy)))
+	} else {
+		klog.Warningln("The unix service accepts all local callers, set --socket-allowed-namespaces, " +
+			"--socket-allowed-service-accounts or --socket-allow-host=false to restrict them")
+	}
+
+	klog.Infoln("Initialize the plugin manager...")
+	if len(PluginDir) == 0 {
+		PluginDir = filepath.Join(SocketPath, "plugins")
+	}
+	pluginManager := plugin.NewManager(PluginDir, unixOptions...)
+	// The unfinished operations of the plugin device types are recovered once the plugins register.
+	pluginManager.AddRegisterHandler(func(deviceType string) {
+		if err := serverImpl.RecoverDeferredJournal(ctx, deviceType); err != nil {
+			klog.Errorf("Recover operation journal of %s failed: %v", deviceType, err)
+		}
+	})
+	if err := pluginManager.Start(); err != nil {
+		klog.Exit(err.Error())
+	}
+
+	// Roll back or replay the operations interrupted by the last exit before accepting new requests.
+	klog.Infoln("Recovering unfinished operations...")
+	if err := serverImpl.RecoverJournal(ctx); err != nil {
+		klog.Errorf("Recover operation journal failed: %v", err)
+	}
+
+	orphanPolicy, err := controller.ParseOrphanPolicy(OrphanPolicy)
+	if err != nil {
+		klog.Exit(err.Error())
+	}
+	orphanController := controller.NewOrphanPodController("OrphanPodController", kubeClient,
+		podLister, serverImpl, recorder, orphanPolicy, OrphanPeriod)
+	// Re-apply the devices as soon as the owner container is restarted.
+	if _, err = podInformer.AddEventHandler(orphanController); err != nil {
+		klog.Exit("AddEventHandler failed")
+	}
+	go orphanController.Start(ctx, 1)
+
+	if EnableCRD {
+		startDeviceMountController(ctx, kubeClient, podInformer, serverImpl, recorder)
+	}
+
+	klog.Infoln("Initialize the metrics server...")
+	metrics.RegisterMounterMetrics(mounter.NewMountedDevicesCollector(serverImpl))
+	if _, err = podInformer.AddEventHandler(serverImpl.SlavePodEventHandler()); err != nil {
+		klog.Exit("AddEventHandler failed")
+	}
+	go serverImpl.RefreshMountedDevices(ctx)
+	metricsServer := metrics.StartServer(MetricsAddr)
+
 	klog.Infoln("Watchdog Starting...")
 	nodeLabeller := watchdog.NewNodeLabeller(NodeName, nodeLister, kubeClient)
 	go nodeLabeller.Start(ctx.Done())
 
 	klog.Infoln("Service Starting...")
 
+	var tcpOptions []grpc.ServerOption
+	if len(TLSCertDir) > 0 {
+		klog.Infoln("Initialize the mutual TLS of the tcp service...")
+		tlsWatch := tlsconfig.NewMutualWatch(TLSCertDir, "tls.crt", "tls.key", "ca.crt", TLSAllowList)
+		tlsWatch.Reload()
+		fileWatch := filewatch.New()
+		if err = tlsWatch.AddToFilewatch(fileWatch); err != nil {
+			klog.Exit(err.Error())
+		}
+		go func() {
+			if err := fileWatch.Run(ctx.Done()); err != nil {
+				klog.Errorf("Error running file watch: %s", err)
+			}
+		}()
+		tcpOptions = append(tcpOptions, grpc.Creds(credentials.NewTLS(&tls.Config{
+			// Load the latest certificates on every handshake.
+			GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+				return tlsWatch.GetServerConfig()
+			},
+		})))
+	} else {
+		klog.Warningln("Mutual TLS of the tcp service is disabled")
+	}
+
 	stopCh1 := make(chan struct{}, 1)
-	s1, err := StartTcpService(serverImpl, stopCh1)
+	s1, err := StartTcpService(serverImpl, stopCh1, tcpOptions...)
 	if err != nil {
 		klog.Exit(err.Error())
 	}
 
 	stopCh2 := make(chan struct{}, 1)
-	s2, err := StartUnixService(serverImpl, stopCh2)
+	s2, err := StartUnixService(serverImpl, stopCh2, unixOptions...)
 	if err != nil {
 		klog.Exit(err.Error())
 	}
@@ -192,12 +323,51 @@ func main() {
 		klog.Infoln("Shutting down grpc unix service...")
 		s2.GracefulStop()
 	}
+	if metricsServer != nil {
+		_ = metricsServer.Close()
+	}
+	pluginManager.Stop()
 	cancelFunc()
 	nodeLabeller.WaitForStop()
 	klog.Infoln("Service stopped, please restart the service")
 	os.Exit(exitCode)
 }
 
+func startDeviceMountController(ctx context.Context, kubeClient *kubernetes.Clientset,
+	podInformer cache.SharedIndexInformer, server api.DeviceMountServiceServer, recorder record.EventRecorder) {
+	groupVersion := v1alpha1.SchemeGroupVersion.String()
+	if _, err := kubeClient.Discovery().ServerResourcesForGroupVersion(groupVersion); err != nil {
+		klog.Warningf("Resources of %s not found, skip starting DeviceMount controller: %v", groupVersion, err)
+		return
+	}
+	klog.Infoln("Initialize the DeviceMount controller...")
+	dynamicClient, err := client.GetDynamicClient(
+		client.WithQPS(float32(KubeQPS), KubeBurst),
+		client.WithDefaultUserAgent())
+	if err != nil {
+		klog.Fatalf("Create k8s dynamicClient failed: %v", err)
+	}
+	informer := dynamicinformer.NewFilteredDynamicInformer(dynamicClient, v1alpha1.DeviceMountResource,
+		metav1.NamespaceAll, 5*time.Minute, cache.Indexers{
+			cache.NamespaceIndex:           cache.MetaNamespaceIndexFunc,
+			controller.DeviceMountPodIndex: controller.DeviceMountPodIndexFunc,
+		}, nil).Informer()
+	_ = informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		klog.Errorf("devicemount informer watch error: %v", err)
+	})
+	dmController := controller.NewDeviceMountController("DeviceMountController", NodeName,
+		dynamicClient, informer, listerv1.NewPodLister(podInformer.GetIndexer()), server, recorder)
+	if _, err = informer.AddEventHandler(dmController); err != nil {
+		klog.Exit("AddEventHandler failed")
+	}
+	if _, err = podInformer.AddEventHandler(dmController.PodEventHandler()); err != nil {
+		klog.Exit("AddEventHandler failed")
+	}
+	go informer.Run(ctx.Done())
+	cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
+	go dmController.Start(ctx, 1)
+}
+
 func newPodInformer(factory informers.SharedInformerFactory, nodeName string) cache.SharedIndexInformer {
 	return factory.InformerFor(&v1.Pod{}, func(k kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
 		return cache.NewSharedIndexInformer(
@@ -215,13 +385,13 @@ func newPodInformer(factory informers.SharedInformerFactory, nodeName string) ca
 	})
 }
 
-func StartTcpService(server api.DeviceMountServiceServer, stopCh chan<- struct{}) (*grpc.Server, error) {
+func StartTcpService(server api.DeviceMountServiceServer, stopCh chan<- struct{}, opts ...grpc.ServerOption) (*grpc.Server, error) {
 	listen, err := net.Listen("tcp", TCPBindPort)
 	if err != nil {
 		klog.Errorf("Failed to listen: %v", err)
 		return nil, err
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(opts...)
 	api.RegisterDeviceMountServiceServer(s, server)
 	klog.Infoln("Serving tcp server...")
 	go func() {
@@ -234,7 +404,7 @@ func StartTcpService(server api.DeviceMountServiceServer, stopCh chan<- struct{}
 	return s, nil
 }
 
-func StartUnixService(server api.DeviceMountServiceServer, stopCh chan<- struct{}) (*grpc.Server, error) {
+func StartUnixService(server api.DeviceMountServiceServer, stopCh chan<- struct{}, opts ...grpc.ServerOption) (*grpc.Server, error) {
 	socketFile := filepath.Join(SocketPath, "device-mounter.sock")
 	_ = os.Remove(socketFile)
 	addr, err := net.ResolveUnixAddr("unix", socketFile)
@@ -247,7 +417,7 @@ func StartUnixService(server api.DeviceMountServiceServer, stopCh chan<- struct{
 		klog.Errorf("Failed to listen: %v", err)
 		return nil, err
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(opts...)
 	api.RegisterDeviceMountServiceServer(s, server)
 	klog.Infoln("Serving unix server...")
 	go func() {
@@ -19,8 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	DeviceMountService_MountDevice_FullMethodName   = "/device_mount.DeviceMountService/MountDevice"
-	DeviceMountService_UnMountDevice_FullMethodName = "/device_mount.DeviceMountService/UnMountDevice"
+	DeviceMountService_MountDevice_FullMethodName        = "/device_mount.DeviceMountService/MountDevice"
+	DeviceMountService_UnMountDevice_FullMethodName      = "/device_mount.DeviceMountService/UnMountDevice"
+	DeviceMountService_ListMountedDevices_FullMethodName = "/device_mount.DeviceMountService/ListMountedDevices"
+	DeviceMountService_GetOperation_FullMethodName       = "/device_mount.DeviceMountService/GetOperation"
+	DeviceMountService_CancelOperation_FullMethodName    = "/device_mount.DeviceMountService/CancelOperation"
 )
 
 // DeviceMountServiceClient is the client API for DeviceMountService service.
@@ -29,6 +32,9 @@ const (
 type DeviceMountServiceClient interface {
 	MountDevice(ctx context.Context, in *MountDeviceRequest, opts ...grpc.CallOption) (*DeviceResponse, error)
 	UnMountDevice(ctx context.Context, in *UnMountDeviceRequest, opts ...grpc.CallOption) (*DeviceResponse, error)
+	ListMountedDevices(ctx context.Context, in *ListMountedDevicesRequest, opts ...grpc.CallOption) (*ListMountedDevicesResponse, error)
+	GetOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	CancelOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*OperationResponse, error)
 }
 
 type deviceMountServiceClient struct {
@@ -57,12 +63,42 @@ func (c *deviceMountServiceClient) UnMountDevice(ctx context.Context, in *UnMoun
 	return out, nil
 }
 
+func (c *deviceMountServiceClient) ListMountedDevices(ctx context.Context, in *ListMountedDevicesRequest, opts ...grpc.CallOption) (*ListMountedDevicesResponse, error) {
+	out := new(ListMountedDevicesResponse)
+	err := c.cc.Invoke(ctx, DeviceMountService_ListMountedDevices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMountServiceClient) GetOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, DeviceMountService_GetOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMountServiceClient) CancelOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, DeviceMountService_CancelOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeviceMountServiceServer is the server API for DeviceMountService service.
 // All implementations must embed UnimplementedDeviceMountServiceServer
 // for forward compatibility
 type DeviceMountServiceServer interface {
 	MountDevice(context.Context, *MountDeviceRequest) (*DeviceResponse, error)
 	UnMountDevice(context.Context, *UnMountDeviceRequest) (*DeviceResponse, error)
+	ListMountedDevices(context.Context, *ListMountedDevicesRequest) (*ListMountedDevicesResponse, error)
+	GetOperation(context.Context, *OperationRequest) (*OperationResponse, error)
+	CancelOperation(context.Context, *OperationRequest) (*OperationResponse, error)
 	mustEmbedUnimplementedDeviceMountServiceServer()
 }
 
@@ -76,6 +112,15 @@ func (UnimplementedDeviceMountServiceServer) MountDevice(context.Context, *Mount
 func (UnimplementedDeviceMountServiceServer) UnMountDevice(context.Context, *UnMountDeviceRequest) (*DeviceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnMountDevice not implemented")
 }
+func (UnimplementedDeviceMountServiceServer) ListMountedDevices(context.Context, *ListMountedDevicesRequest) (*ListMountedDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMountedDevices not implemented")
+}
+func (UnimplementedDeviceMountServiceServer) GetOperation(context.Context, *OperationRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (UnimplementedDeviceMountServiceServer) CancelOperation(context.Context, *OperationRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
 func (UnimplementedDeviceMountServiceServer) mustEmbedUnimplementedDeviceMountServiceServer() {}
 
 // UnsafeDeviceMountServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +170,60 @@ func _DeviceMountService_UnMountDevice_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeviceMountService_ListMountedDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMountedDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMountServiceServer).ListMountedDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMountService_ListMountedDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMountServiceServer).ListMountedDevices(ctx, req.(*ListMountedDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMountService_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMountServiceServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMountService_GetOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMountServiceServer).GetOperation(ctx, req.(*OperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMountService_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMountServiceServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMountService_CancelOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMountServiceServer).CancelOperation(ctx, req.(*OperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DeviceMountService_ServiceDesc is the grpc.ServiceDesc for DeviceMountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,6 +239,18 @@ var DeviceMountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnMountDevice",
 			Handler:    _DeviceMountService_UnMountDevice_Handler,
 		},
+		{
+			MethodName: "ListMountedDevices",
+			Handler:    _DeviceMountService_ListMountedDevices_Handler,
+		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _DeviceMountService_GetOperation_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _DeviceMountService_CancelOperation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/api/api.proto",
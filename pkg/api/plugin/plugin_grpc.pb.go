@@ -0,0 +1,495 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.1
+// source: pkg/api/plugin/plugin.proto
+
+package plugin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Registration_Register_FullMethodName = "/device_mount.plugin.Registration/Register"
+)
+
+// RegistrationClient is the client API for Registration service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RegistrationClient interface {
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type registrationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRegistrationClient(cc grpc.ClientConnInterface) RegistrationClient {
+	return &registrationClient{cc}
+}
+
+func (c *registrationClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Registration_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegistrationServer is the server API for Registration service.
+// All implementations must embed UnimplementedRegistrationServer
+// for forward compatibility
+type RegistrationServer interface {
+	Register(context.Context, *RegisterRequest) (*Empty, error)
+	mustEmbedUnimplementedRegistrationServer()
+}
+
+// UnimplementedRegistrationServer must be embedded to have forward compatible implementations.
+type UnimplementedRegistrationServer struct {
+}
+
+func (UnimplementedRegistrationServer) Register(context.Context, *RegisterRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedRegistrationServer) mustEmbedUnimplementedRegistrationServer() {}
+
+// UnsafeRegistrationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RegistrationServer will
+// result in compilation errors.
+type UnsafeRegistrationServer interface {
+	mustEmbedUnimplementedRegistrationServer()
+}
+
+func RegisterRegistrationServer(s grpc.ServiceRegistrar, srv RegistrationServer) {
+	s.RegisterService(&Registration_ServiceDesc, srv)
+}
+
+func _Registration_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistrationServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Registration_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistrationServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Registration_ServiceDesc is the grpc.ServiceDesc for Registration service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Registration_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "device_mount.plugin.Registration",
+	HandlerType: (*RegistrationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Registration_Register_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/api/plugin/plugin.proto",
+}
+
+const (
+	DeviceMounterPlugin_ValidateMountRequest_FullMethodName       = "/device_mount.plugin.DeviceMounterPlugin/ValidateMountRequest"
+	DeviceMounterPlugin_BuildSupportPodTemplates_FullMethodName   = "/device_mount.plugin.DeviceMounterPlugin/BuildSupportPodTemplates"
+	DeviceMounterPlugin_VerifySupportPodStatus_FullMethodName     = "/device_mount.plugin.DeviceMounterPlugin/VerifySupportPodStatus"
+	DeviceMounterPlugin_GetDeviceInfosToMount_FullMethodName      = "/device_mount.plugin.DeviceMounterPlugin/GetDeviceInfosToMount"
+	DeviceMounterPlugin_ExecutePostMountActions_FullMethodName    = "/device_mount.plugin.DeviceMounterPlugin/ExecutePostMountActions"
+	DeviceMounterPlugin_GetDeviceInfosToUnmount_FullMethodName    = "/device_mount.plugin.DeviceMounterPlugin/GetDeviceInfosToUnmount"
+	DeviceMounterPlugin_GetDevicesActiveProcessIDs_FullMethodName = "/device_mount.plugin.DeviceMounterPlugin/GetDevicesActiveProcessIDs"
+	DeviceMounterPlugin_ExecutePostUnmountActions_FullMethodName  = "/device_mount.plugin.DeviceMounterPlugin/ExecutePostUnmountActions"
+	DeviceMounterPlugin_GetPodsToCleanup_FullMethodName           = "/device_mount.plugin.DeviceMounterPlugin/GetPodsToCleanup"
+)
+
+// DeviceMounterPluginClient is the client API for DeviceMounterPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DeviceMounterPluginClient interface {
+	ValidateMountRequest(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*Empty, error)
+	BuildSupportPodTemplates(ctx context.Context, in *BuildSupportPodTemplatesRequest, opts ...grpc.CallOption) (*PodList, error)
+	VerifySupportPodStatus(ctx context.Context, in *VerifySupportPodStatusRequest, opts ...grpc.CallOption) (*VerifySupportPodStatusResponse, error)
+	GetDeviceInfosToMount(ctx context.Context, in *DeviceInfosRequest, opts ...grpc.CallOption) (*DeviceInfosResponse, error)
+	ExecutePostMountActions(ctx context.Context, in *PostActionsRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetDeviceInfosToUnmount(ctx context.Context, in *DeviceInfosRequest, opts ...grpc.CallOption) (*DeviceInfosResponse, error)
+	GetDevicesActiveProcessIDs(ctx context.Context, in *ActiveProcessIDsRequest, opts ...grpc.CallOption) (*ActiveProcessIDsResponse, error)
+	ExecutePostUnmountActions(ctx context.Context, in *PostActionsRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetPodsToCleanup(ctx context.Context, in *PodsToCleanupRequest, opts ...grpc.CallOption) (*PodsToCleanupResponse, error)
+}
+
+type deviceMounterPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeviceMounterPluginClient(cc grpc.ClientConnInterface) DeviceMounterPluginClient {
+	return &deviceMounterPluginClient{cc}
+}
+
+func (c *deviceMounterPluginClient) ValidateMountRequest(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_ValidateMountRequest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) BuildSupportPodTemplates(ctx context.Context, in *BuildSupportPodTemplatesRequest, opts ...grpc.CallOption) (*PodList, error) {
+	out := new(PodList)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_BuildSupportPodTemplates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) VerifySupportPodStatus(ctx context.Context, in *VerifySupportPodStatusRequest, opts ...grpc.CallOption) (*VerifySupportPodStatusResponse, error) {
+	out := new(VerifySupportPodStatusResponse)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_VerifySupportPodStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) GetDeviceInfosToMount(ctx context.Context, in *DeviceInfosRequest, opts ...grpc.CallOption) (*DeviceInfosResponse, error) {
+	out := new(DeviceInfosResponse)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_GetDeviceInfosToMount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) ExecutePostMountActions(ctx context.Context, in *PostActionsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_ExecutePostMountActions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) GetDeviceInfosToUnmount(ctx context.Context, in *DeviceInfosRequest, opts ...grpc.CallOption) (*DeviceInfosResponse, error) {
+	out := new(DeviceInfosResponse)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_GetDeviceInfosToUnmount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) GetDevicesActiveProcessIDs(ctx context.Context, in *ActiveProcessIDsRequest, opts ...grpc.CallOption) (*ActiveProcessIDsResponse, error) {
+	out := new(ActiveProcessIDsResponse)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_GetDevicesActiveProcessIDs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) ExecutePostUnmountActions(ctx context.Context, in *PostActionsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_ExecutePostUnmountActions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceMounterPluginClient) GetPodsToCleanup(ctx context.Context, in *PodsToCleanupRequest, opts ...grpc.CallOption) (*PodsToCleanupResponse, error) {
+	out := new(PodsToCleanupResponse)
+	err := c.cc.Invoke(ctx, DeviceMounterPlugin_GetPodsToCleanup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeviceMounterPluginServer is the server API for DeviceMounterPlugin service.
+// All implementations must embed UnimplementedDeviceMounterPluginServer
+// for forward compatibility
+type DeviceMounterPluginServer interface {
+	ValidateMountRequest(context.Context, *ValidateRequest) (*Empty, error)
+	BuildSupportPodTemplates(context.Context, *BuildSupportPodTemplatesRequest) (*PodList, error)
+	VerifySupportPodStatus(context.Context, *VerifySupportPodStatusRequest) (*VerifySupportPodStatusResponse, error)
+	GetDeviceInfosToMount(context.Context, *DeviceInfosRequest) (*DeviceInfosResponse, error)
+	ExecutePostMountActions(context.Context, *PostActionsRequest) (*Empty, error)
+	GetDeviceInfosToUnmount(context.Context, *DeviceInfosRequest) (*DeviceInfosResponse, error)
+	GetDevicesActiveProcessIDs(context.Context, *ActiveProcessIDsRequest) (*ActiveProcessIDsResponse, error)
+	ExecutePostUnmountActions(context.Context, *PostActionsRequest) (*Empty, error)
+	GetPodsToCleanup(context.Context, *PodsToCleanupRequest) (*PodsToCleanupResponse, error)
+	mustEmbedUnimplementedDeviceMounterPluginServer()
+}
+
+// UnimplementedDeviceMounterPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedDeviceMounterPluginServer struct {
+}
+
+func (UnimplementedDeviceMounterPluginServer) ValidateMountRequest(context.Context, *ValidateRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateMountRequest not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) BuildSupportPodTemplates(context.Context, *BuildSupportPodTemplatesRequest) (*PodList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildSupportPodTemplates not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) VerifySupportPodStatus(context.Context, *VerifySupportPodStatusRequest) (*VerifySupportPodStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifySupportPodStatus not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) GetDeviceInfosToMount(context.Context, *DeviceInfosRequest) (*DeviceInfosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceInfosToMount not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) ExecutePostMountActions(context.Context, *PostActionsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecutePostMountActions not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) GetDeviceInfosToUnmount(context.Context, *DeviceInfosRequest) (*DeviceInfosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceInfosToUnmount not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) GetDevicesActiveProcessIDs(context.Context, *ActiveProcessIDsRequest) (*ActiveProcessIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDevicesActiveProcessIDs not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) ExecutePostUnmountActions(context.Context, *PostActionsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecutePostUnmountActions not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) GetPodsToCleanup(context.Context, *PodsToCleanupRequest) (*PodsToCleanupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPodsToCleanup not implemented")
+}
+func (UnimplementedDeviceMounterPluginServer) mustEmbedUnimplementedDeviceMounterPluginServer() {}
+
+// UnsafeDeviceMounterPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeviceMounterPluginServer will
+// result in compilation errors.
+type UnsafeDeviceMounterPluginServer interface {
+	mustEmbedUnimplementedDeviceMounterPluginServer()
+}
+
+func RegisterDeviceMounterPluginServer(s grpc.ServiceRegistrar, srv DeviceMounterPluginServer) {
+	s.RegisterService(&DeviceMounterPlugin_ServiceDesc, srv)
+}
+
+func _DeviceMounterPlugin_ValidateMountRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).ValidateMountRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_ValidateMountRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).ValidateMountRequest(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_BuildSupportPodTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildSupportPodTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).BuildSupportPodTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_BuildSupportPodTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).BuildSupportPodTemplates(ctx, req.(*BuildSupportPodTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_VerifySupportPodStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifySupportPodStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).VerifySupportPodStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_VerifySupportPodStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).VerifySupportPodStatus(ctx, req.(*VerifySupportPodStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_GetDeviceInfosToMount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceInfosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).GetDeviceInfosToMount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_GetDeviceInfosToMount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).GetDeviceInfosToMount(ctx, req.(*DeviceInfosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_ExecutePostMountActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).ExecutePostMountActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_ExecutePostMountActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).ExecutePostMountActions(ctx, req.(*PostActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_GetDeviceInfosToUnmount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceInfosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).GetDeviceInfosToUnmount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_GetDeviceInfosToUnmount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).GetDeviceInfosToUnmount(ctx, req.(*DeviceInfosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_GetDevicesActiveProcessIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActiveProcessIDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).GetDevicesActiveProcessIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_GetDevicesActiveProcessIDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).GetDevicesActiveProcessIDs(ctx, req.(*ActiveProcessIDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_ExecutePostUnmountActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).ExecutePostUnmountActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_ExecutePostUnmountActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).ExecutePostUnmountActions(ctx, req.(*PostActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceMounterPlugin_GetPodsToCleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PodsToCleanupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceMounterPluginServer).GetPodsToCleanup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceMounterPlugin_GetPodsToCleanup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceMounterPluginServer).GetPodsToCleanup(ctx, req.(*PodsToCleanupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DeviceMounterPlugin_ServiceDesc is the grpc.ServiceDesc for DeviceMounterPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeviceMounterPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "device_mount.plugin.DeviceMounterPlugin",
+	HandlerType: (*DeviceMounterPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateMountRequest",
+			Handler:    _DeviceMounterPlugin_ValidateMountRequest_Handler,
+		},
+		{
+			MethodName: "BuildSupportPodTemplates",
+			Handler:    _DeviceMounterPlugin_BuildSupportPodTemplates_Handler,
+		},
+		{
+			MethodName: "VerifySupportPodStatus",
+			Handler:    _DeviceMounterPlugin_VerifySupportPodStatus_Handler,
+		},
+		{
+			MethodName: "GetDeviceInfosToMount",
+			Handler:    _DeviceMounterPlugin_GetDeviceInfosToMount_Handler,
+		},
+		{
+			MethodName: "ExecutePostMountActions",
+			Handler:    _DeviceMounterPlugin_ExecutePostMountActions_Handler,
+		},
+		{
+			MethodName: "GetDeviceInfosToUnmount",
+			Handler:    _DeviceMounterPlugin_GetDeviceInfosToUnmount_Handler,
+		},
+		{
+			MethodName: "GetDevicesActiveProcessIDs",
+			Handler:    _DeviceMounterPlugin_GetDevicesActiveProcessIDs_Handler,
+		},
+		{
+			MethodName: "ExecutePostUnmountActions",
+			Handler:    _DeviceMounterPlugin_ExecutePostUnmountActions_Handler,
+		},
+		{
+			MethodName: "GetPodsToCleanup",
+			Handler:    _DeviceMounterPlugin_GetPodsToCleanup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/api/plugin/plugin.proto",
+}
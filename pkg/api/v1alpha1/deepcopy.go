@@ -0,0 +1,154 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *DeviceMount) DeepCopyInto(out *DeviceMount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy creates a new DeviceMount by copying the receiver.
+func (in *DeviceMount) DeepCopy() *DeviceMount {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *DeviceMount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *DeviceMountList) DeepCopyInto(out *DeviceMountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a new DeviceMountList by copying the receiver.
+func (in *DeviceMountList) DeepCopy() *DeviceMountList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceMountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *DeviceMountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *DeviceMountSpec) DeepCopyInto(out *DeviceMountSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy creates a new DeviceMountSpec by copying the receiver.
+func (in *DeviceMountSpec) DeepCopy() *DeviceMountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceMountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *DeviceMountStatus) DeepCopyInto(out *DeviceMountStatus) {
+	*out = *in
+	if in.MountedDevices != nil {
+		in, out := &in.MountedDevices, &out.MountedDevices
+		*out = make([]MountedDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.SlavePods != nil {
+		in, out := &in.SlavePods, &out.SlavePods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a new DeviceMountStatus by copying the receiver.
+func (in *DeviceMountStatus) DeepCopy() *DeviceMountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceMountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *MountedDevice) DeepCopyInto(out *MountedDevice) {
+	*out = *in
+}
+
+// DeepCopy creates a new MountedDevice by copying the receiver.
+func (in *MountedDevice) DeepCopy() *MountedDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(MountedDevice)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceMountSpec defines the desired device mounting of a pod container.
+type DeviceMountSpec struct {
+	// The name of the target pod, the pod must be in the same namespace as the DeviceMount.
+	PodName string `json:"podName"`
+	// The name of the target container, can be omitted when the pod has only one container.
+	Container string `json:"container,omitempty"`
+	// The type of device to be mounted, e.g. NVIDIA_GPU.
+	DeviceType string `json:"deviceType"`
+	// The node resources requested by the slave pods.
+	Resources v1.ResourceList `json:"resources"`
+	// Annotations added to the slave pods.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels added to the slave pods.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Json patch rules applied to the slave pods.
+	Patches []string `json:"patches,omitempty"`
+	// Kill the processes using the devices when unmounting.
+	ForceUnmount bool `json:"forceUnmount,omitempty"`
+}
+
+// DeviceMountPhase is the lifecycle phase of a DeviceMount.
+type DeviceMountPhase string
+
+const (
+	DeviceMountPending    DeviceMountPhase = "Pending"
+	DeviceMountMounting   DeviceMountPhase = "Mounting"
+	DeviceMountMounted    DeviceMountPhase = "Mounted"
+	DeviceMountFailed     DeviceMountPhase = "Failed"
+	DeviceMountUnmounting DeviceMountPhase = "Unmounting"
+)
+
+const (
+	// DeviceMountConditionMounted indicates whether the devices are mounted to the container.
+	DeviceMountConditionMounted = "Mounted"
+
+	DeviceMountFinalizer = Group + "/device-mount"
+)
+
+// MountedDevice describes a device file mounted into the target container.
+// +k8s:openapi-gen=true
+type MountedDevice struct {
+	DeviceID       string `json:"deviceID,omitempty"`
+	DeviceFilePath string `json:"deviceFilePath"`
+	Type           string `json:"type"`
+	Major          int64  `json:"major"`
+	Minor          int64  `json:"minor"`
+	Permissions    string `json:"permissions"`
+}
+
+// DeviceMountStatus defines the observed state of DeviceMount.
+type DeviceMountStatus struct {
+	Phase              DeviceMountPhase   `json:"phase,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	NodeName           string             `json:"nodeName,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	MountedDevices     []MountedDevice    `json:"mountedDevices,omitempty"`
+	SlavePods          []string           `json:"slavePods,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DeviceMount declares the devices to be mounted into a pod container.
+type DeviceMount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceMountSpec   `json:"spec,omitempty"`
+	Status DeviceMountStatus `json:"status,omitempty"`
+}
+
+// DeviceMountList contains a list of DeviceMount.
+type DeviceMountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceMount `json:"items"`
+}
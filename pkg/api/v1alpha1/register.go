@@ -0,0 +1,30 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CRDGroup The group of the custom resources.
+// The group version device-mounter.io/v1alpha1 is served by the aggregated apiserver,
+// the kube-aggregator does not allow a CRD to share it, so a subgroup is used.
+const CRDGroup = "crd." + Group
+
+var (
+	SchemeGroupVersion = schema.GroupVersion{Group: CRDGroup, Version: Version}
+
+	DeviceMountResource = SchemeGroupVersion.WithResource("devicemounts")
+
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&DeviceMount{},
+		&DeviceMountList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
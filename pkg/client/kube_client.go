@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/coldzerofear/device-mounter/pkg/versions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -56,6 +57,14 @@ func GetClientSet(opts ...Option) (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(config)
 }
 
+func GetDynamicClient(opts ...Option) (*dynamic.DynamicClient, error) {
+	config, err := GetKubeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
+
 func WithQPS(qps float32, burst int) Option {
 	return func(config *rest.Config) {
 		config.QPS = qps
@@ -19,7 +19,7 @@ import (
 	"k8s.io/kubectl/pkg/cmd/util"
 )
 
-func WriteToPod(ctx context.Context, kubeclient *kubernetes.Clientset,
+func WriteToPod(ctx context.Context, kubeclient kubernetes.Interface,
 	pod *v1.Pod, ctr *api.Container, content []byte, cmd []string) (string, string, error) {
 	req := kubeclient.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -63,7 +63,7 @@ func WriteToPod(ctx context.Context, kubeclient *kubernetes.Clientset,
 	return stdout.String(), stderr.String(), nil
 }
 
-func ExecCmdToPod(ctx context.Context, kubeclient *kubernetes.Clientset,
+func ExecCmdToPod(ctx context.Context, kubeclient kubernetes.Interface,
 	pod *v1.Pod, ctr *api.Container, cmd []string) (string, string, error) {
 	// 执行命令
 	req := kubeclient.CoreV1().RESTClient().Post().
@@ -107,7 +107,7 @@ func ExecCmdToPod(ctx context.Context, kubeclient *kubernetes.Clientset,
 	return stdout.String(), stderr.String(), nil
 }
 
-func CopyToPod(kubeclient *kubernetes.Clientset, pod *v1.Pod,
+func CopyToPod(kubeclient kubernetes.Interface, pod *v1.Pod,
 	ctr *api.Container, src, dst string) (string, string, error) {
 	kubeconfig, err := GetKubeConfig()
 	if err != nil {
@@ -1,16 +1,21 @@
 package client
 
 import (
+	"context"
 	"net"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
-	"k8s.io/kubelet/pkg/apis/podresources/v1alpha1"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
 )
 
 const (
@@ -22,9 +27,11 @@ const (
 type PodResourcesClientPorxy struct {
 	lock         sync.RWMutex // 用于保护conn的并发访问
 	conn         *grpc.ClientConn
-	podResources v1alpha1.PodResourcesListerClient
+	podResources podresourcesv1.PodResourcesListerClient
 	socketPath   string
 	stopCh       chan struct{}
+	// getUnsupported The kubelet does not support the Get method, list all pods instead.
+	getUnsupported atomic.Bool
 }
 
 var (
@@ -48,20 +55,70 @@ func newPodResourcesClientPorxy(socketPath string) (*PodResourcesClientPorxy, er
 	proxy := &PodResourcesClientPorxy{
 		socketPath:   socketPath,
 		conn:         conn,
-		podResources: v1alpha1.NewPodResourcesListerClient(conn),
+		podResources: podresourcesv1.NewPodResourcesListerClient(conn),
 		stopCh:       make(chan struct{}, 1),
 	}
 	go proxy.monitorConnection()
 	return proxy, nil
 }
 
-func (p *PodResourcesClientPorxy) GetClient() v1alpha1.PodResourcesListerClient {
+func (p *PodResourcesClientPorxy) GetClient() podresourcesv1.PodResourcesListerClient {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	return p.podResources
 }
 
+// ListPodResources List the resources allocated to all pods on the node.
+func (p *PodResourcesClientPorxy) ListPodResources(ctx context.Context) ([]*podresourcesv1.PodResources, error) {
+	resp, err := p.GetClient().List(ctx, &podresourcesv1.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPodResources(), nil
+}
+
+// GetPodResources Get the resources allocated to the pod, nil is returned when the pod is not found.
+// It falls back to List when the kubelet does not support Get (before v1.27 or with the feature gate disabled).
+func (p *PodResourcesClientPorxy) GetPodResources(ctx context.Context, name, namespace string) (*podresourcesv1.PodResources, error) {
+	if !p.getUnsupported.Load() {
+		resp, err := p.GetClient().Get(ctx, &podresourcesv1.GetPodResourcesRequest{
+			PodName:      name,
+			PodNamespace: namespace,
+		})
+		switch {
+		case err == nil:
+			return resp.GetPodResources(), nil
+		case isGetUnsupported(err):
+			klog.Warningf("PodResources Get is not supported by the kubelet, fall back to List: %v", err)
+			p.getUnsupported.Store(true)
+		case strings.Contains(err.Error(), "not found"):
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+	podResources, err := p.ListPodResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, resources := range podResources {
+		if resources.GetName() == name && resources.GetNamespace() == namespace {
+			return resources, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetAllocatableResources Get the devices that the kubelet can allocate to the pods.
+func (p *PodResourcesClientPorxy) GetAllocatableResources(ctx context.Context) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	return p.GetClient().GetAllocatableResources(ctx, &podresourcesv1.AllocatableResourcesRequest{})
+}
+
+func isGetUnsupported(err error) bool {
+	return status.Code(err) == codes.Unimplemented || strings.Contains(err.Error(), "Get method disabled")
+}
+
 func (p *PodResourcesClientPorxy) Close() error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -115,7 +172,7 @@ func (p *PodResourcesClientPorxy) recoverGRPCConnection() error {
 		return errors.Wrap(err, "failed to redial")
 	}
 	p.conn = newConn
-	p.podResources = v1alpha1.NewPodResourcesListerClient(p.conn)
+	p.podResources = podresourcesv1.NewPodResourcesListerClient(p.conn)
 	return nil
 }
 
@@ -130,6 +187,17 @@ func GetPodResourcesClinet() *PodResourcesClientPorxy {
 	return podResourcesClient
 }
 
+// NewPodResourcesClient Connect to the PodResources API served on the socket.
+func NewPodResourcesClient(socketPath string) (*PodResourcesClientPorxy, error) {
+	return newPodResourcesClientPorxy(socketPath)
+}
+
+// SetPodResourcesClient Replace the client returned by GetPodResourcesClinet, e.g. with the client of a simulated kubelet.
+func SetPodResourcesClient(proxy *PodResourcesClientPorxy) {
+	onceInitPodResClient.Do(func() {})
+	podResourcesClient = proxy
+}
+
 func Dial(unixSocketPath string, timeout time.Duration) (*grpc.ClientConn, error) {
 	if c, err := grpc.Dial(unixSocketPath,
 		grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(timeout),
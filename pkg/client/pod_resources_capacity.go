@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+type allocatableResourcesGetter interface {
+	GetAllocatableResources(ctx context.Context) (*podresourcesv1.AllocatableResourcesResponse, error)
+}
+
+type podResourcesCapacityCalculator struct {
+	getter     allocatableResourcesGetter
+	calculator util.CapacityCalculator
+}
+
+// NewPodResourcesCapacityCalculator Limit the free device resources computed by the calculator
+// to the devices that the kubelet can still allocate, which excludes the unhealthy devices.
+func NewPodResourcesCapacityCalculator(getter allocatableResourcesGetter, calculator util.CapacityCalculator) util.CapacityCalculator {
+	return &podResourcesCapacityCalculator{getter: getter, calculator: calculator}
+}
+
+func (c *podResourcesCapacityCalculator) FreeResources(node *v1.Node) (v1.ResourceList, error) {
+	free, err := c.calculator.FreeResources(node)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	resp, err := c.getter.GetAllocatableResources(ctx)
+	if err != nil {
+		// GetAllocatableResources is not available before kubelet v1.23.
+		klog.V(4).Infof("Failed to get allocatable resources from kubelet: %v", err)
+		return free, nil
+	}
+	devices := map[v1.ResourceName]int64{}
+	for _, device := range resp.GetDevices() {
+		devices[v1.ResourceName(device.GetResourceName())] += int64(len(device.GetDeviceIds()))
+	}
+	for name, count := range devices {
+		allocatable, ok := node.Status.Allocatable[name]
+		freeQuantity, ok2 := free[name]
+		if !ok || !ok2 {
+			continue
+		}
+		// The devices used by the pods are the same whether counted by node or by kubelet.
+		used := allocatable.Value() - freeQuantity.Value()
+		if kubeletFree := count - used; kubeletFree < freeQuantity.Value() {
+			free[name] = *resource.NewQuantity(kubeletFree, resource.DecimalSI)
+		}
+	}
+	return free, nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+type fakePodResourcesClient struct {
+	podresourcesv1.PodResourcesListerClient
+	getErr    error
+	getCalls  int
+	listCalls int
+	pods      []*podresourcesv1.PodResources
+}
+
+func (f *fakePodResourcesClient) List(_ context.Context, _ *podresourcesv1.ListPodResourcesRequest, _ ...grpc.CallOption) (*podresourcesv1.ListPodResourcesResponse, error) {
+	f.listCalls++
+	return &podresourcesv1.ListPodResourcesResponse{PodResources: f.pods}, nil
+}
+
+func (f *fakePodResourcesClient) Get(_ context.Context, req *podresourcesv1.GetPodResourcesRequest, _ ...grpc.CallOption) (*podresourcesv1.GetPodResourcesResponse, error) {
+	f.getCalls++
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	for _, pod := range f.pods {
+		if pod.Name == req.PodName && pod.Namespace == req.PodNamespace {
+			return &podresourcesv1.GetPodResourcesResponse{PodResources: pod}, nil
+		}
+	}
+	return nil, fmt.Errorf("pod %s not found in %s", req.PodName, req.PodNamespace)
+}
+
+func Test_GetPodResources(t *testing.T) {
+	pods := []*podresourcesv1.PodResources{{Name: "pod", Namespace: "default"}}
+	tests := []struct {
+		name          string
+		getErr        error
+		wantGetCalls  int
+		wantListCalls int
+	}{
+		{name: "get", wantGetCalls: 2},
+		{name: "get disabled", getErr: fmt.Errorf("PodResources API Get method disabled"), wantGetCalls: 1, wantListCalls: 2},
+		{name: "get unimplemented", getErr: status.Error(codes.Unimplemented, "unknown method Get"), wantGetCalls: 1, wantListCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakePodResourcesClient{getErr: tt.getErr, pods: pods}
+			proxy := &PodResourcesClientPorxy{podResources: fakeClient}
+
+			resources, err := proxy.GetPodResources(context.Background(), "pod", "default")
+			assert.NoError(t, err)
+			assert.Equal(t, "pod", resources.GetName())
+			resources, err = proxy.GetPodResources(context.Background(), "missing", "default")
+			assert.NoError(t, err)
+			assert.Nil(t, resources)
+
+			assert.Equal(t, tt.wantGetCalls, fakeClient.getCalls)
+			assert.Equal(t, tt.wantListCalls, fakeClient.listCalls)
+		})
+	}
+}
+
+type fakeCapacityCalculator v1.ResourceList
+
+func (f fakeCapacityCalculator) FreeResources(_ *v1.Node) (v1.ResourceList, error) {
+	return v1.ResourceList(f).DeepCopy(), nil
+}
+
+type fakeAllocatableGetter []*podresourcesv1.ContainerDevices
+
+func (f fakeAllocatableGetter) GetAllocatableResources(_ context.Context) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	return &podresourcesv1.AllocatableResourcesResponse{Devices: f}, nil
+}
+
+func Test_PodResourcesCapacityCalculator(t *testing.T) {
+	node := &v1.Node{Status: v1.NodeStatus{
+		Allocatable: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+	}}
+	// 6 gpus are used by the pods and one of the free gpus became unhealthy.
+	calculator := NewPodResourcesCapacityCalculator(fakeAllocatableGetter{{
+		ResourceName: "nvidia.com/gpu",
+		DeviceIds:    []string{"0", "1", "2", "3", "4", "5", "6"},
+	}}, fakeCapacityCalculator{"nvidia.com/gpu": resource.MustParse("2")})
+
+	free, err := calculator.FreeResources(node)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), free.Name("nvidia.com/gpu", resource.DecimalSI).Value())
+}
@@ -9,6 +9,9 @@ import (
 const (
 	DeviceTypeAnnotationKey  = v1alpha1.Group + "/device-type"
 	ContainerIdAnnotationKey = v1alpha1.Group + "/containerId"
+	RequestIdAnnotationKey   = v1alpha1.Group + "/request-id"
+	// The digest of the parameters of the mount request with the request id.
+	RequestDigestAnnotationKey = v1alpha1.Group + "/request-digest"
 
 	// 在原有基础上扩容，目前仅支持： volcano vgpu
 	ExpansionAnnotationKey = v1alpha1.Group + "/expansion"
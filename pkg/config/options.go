@@ -17,6 +17,12 @@ var (
 	DeviceSlaveContainerImageTag = "alpine:latest"
 	// device slave container image pull policy
 	DeviceSlaveImagePullPolicy = v1.PullIfNotPresent
+	// the config file of the generic device mounter, empty to disable it
+	GenericDeviceConfigFile = ""
+	// the device paths (glob patterns) that the host device mounter is allowed to mount, empty to disable it
+	HostDeviceAllowedPaths []string
+	// the namespaces of the pods that the host devices can be mounted into, "*" allows all namespaces
+	HostDeviceAllowedNamespaces []string
 
 	CurrentCGroupDriver CGroupDriver
 	initCGroupOnce      sync.Once
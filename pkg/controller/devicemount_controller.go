@@ -0,0 +1,436 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DeviceMountPodIndex Index DeviceMount objects by the namespaced name of the target pod.
+	DeviceMountPodIndex = "devicemount.spec.podName"
+
+	deviceMountTimeout = 2 * time.Minute
+	pendingRetryPeriod = 10 * time.Second
+	failedRetryPeriod  = time.Minute
+)
+
+// DeviceMountPodIndexFunc Index function of DeviceMountPodIndex.
+func DeviceMountPodIndexFunc(obj interface{}) ([]string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	podName, _, _ := unstructured.NestedString(u.Object, "spec", "podName")
+	if podName == "" {
+		return nil, nil
+	}
+	return []string{client.ObjectKey{Namespace: u.GetNamespace(), Name: podName}.String()}, nil
+}
+
+// deviceMountController Reconcile the DeviceMount objects whose target pod is on the current node,
+// and mount or unmount devices through the device mounter service of the node.
+type deviceMountController struct {
+	name      string
+	nodeName  string
+	client    dynamic.Interface
+	indexer   cache.Indexer
+	podLister listerv1.PodLister
+	mounter   api.DeviceMountServiceServer
+	recorder  record.EventRecorder
+	queue     workqueue.RateLimitingInterface
+}
+
+var _ cache.ResourceEventHandler = &deviceMountController{}
+
+func NewDeviceMountController(name, nodeName string, dynamicClient dynamic.Interface,
+	informer cache.SharedIndexInformer, podLister listerv1.PodLister,
+	mounter api.DeviceMountServiceServer, recorder record.EventRecorder) *deviceMountController {
+	return &deviceMountController{
+		name:      name,
+		nodeName:  nodeName,
+		client:    dynamicClient,
+		indexer:   informer.GetIndexer(),
+		podLister: podLister,
+		mounter:   mounter,
+		recorder:  recorder,
+		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+}
+
+func (c *deviceMountController) enqueue(obj interface{}) {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	c.queue.Add(client.ObjectKey{Namespace: object.GetNamespace(), Name: object.GetName()})
+}
+
+func (c *deviceMountController) OnAdd(obj interface{}, isInInitialList bool) {
+	c.enqueue(obj)
+}
+
+func (c *deviceMountController) OnUpdate(oldObj, newObj interface{}) {
+	c.enqueue(newObj)
+}
+
+func (c *deviceMountController) OnDelete(obj interface{}) {
+	// The object with finalizer will not be deleted before being processed, nothing to do here.
+}
+
+// PodEventHandler Trigger the reconciliation of the DeviceMount objects when the target pod changes.
+func (c *deviceMountController) PodEventHandler() cache.ResourceEventHandler {
+	enqueuePod := func(obj interface{}) {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			return
+		}
+		objs, err := c.indexer.ByIndex(DeviceMountPodIndex, client.ObjectKeyFromObject(pod).String())
+		if err != nil {
+			return
+		}
+		for _, o := range objs {
+			c.enqueue(o)
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueuePod,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok1 := oldObj.(*v1.Pod)
+			newPod, ok2 := newObj.(*v1.Pod)
+			if ok1 && ok2 && oldPod.ResourceVersion == newPod.ResourceVersion {
+				return
+			}
+			enqueuePod(newObj)
+		},
+	}
+}
+
+func (c *deviceMountController) Start(ctx context.Context, workerNum int) {
+	go func() {
+		<-ctx.Done()
+		klog.Infoln(c.name, "is stopping...")
+		c.queue.ShutDown()
+	}()
+	wg := &sync.WaitGroup{}
+	wg.Add(workerNum)
+	for i := 0; i < workerNum; i++ {
+		go func() {
+			defer wg.Done()
+			for processNextWorkItem(ctx, c.queue, c.reconcile) {
+			}
+		}()
+	}
+	wg.Wait()
+	klog.Infoln(c.name, "stopped")
+}
+
+func (c *deviceMountController) reconcile(ctx context.Context, req client.ObjectKey) (reconcile.Result, error) {
+	klog.V(4).Infof("reconcile DeviceMount %s", req.String())
+	obj, exists, err := c.indexer.GetByKey(req.String())
+	if err != nil || !exists {
+		return reconcile.Result{}, err
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+	dm := &v1alpha1.DeviceMount{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), dm); err != nil {
+		klog.V(3).ErrorS(err, "Convert DeviceMount failed", "deviceMount", req.String())
+		return reconcile.Result{}, nil
+	}
+
+	// The pod lister only contains the pods of the current node.
+	pod, err := c.podLister.Pods(dm.Namespace).Get(dm.Spec.PodName)
+	if errors.IsNotFound(err) {
+		pod = nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+	// Only the mounter of the node where the target pod is located handles the object.
+	if pod == nil && dm.Status.NodeName != c.nodeName {
+		return reconcile.Result{}, nil
+	}
+	if pod != nil && dm.Status.NodeName != "" && dm.Status.NodeName != c.nodeName {
+		return reconcile.Result{}, nil
+	}
+
+	if !dm.DeletionTimestamp.IsZero() {
+		return c.finalize(ctx, dm, pod)
+	}
+
+	if !controllerutil.ContainsFinalizer(dm, v1alpha1.DeviceMountFinalizer) {
+		controllerutil.AddFinalizer(dm, v1alpha1.DeviceMountFinalizer)
+		return reconcile.Result{}, c.update(ctx, dm)
+	}
+
+	if pod == nil || pod.UID == "" || !pod.DeletionTimestamp.IsZero() {
+		if dm.Status.Phase == v1alpha1.DeviceMountFailed {
+			return reconcile.Result{}, nil
+		}
+		c.setFailed(dm, "PodNotFound", fmt.Sprintf("target pod %s not found", dm.Spec.PodName))
+		return reconcile.Result{}, c.updateStatus(ctx, dm)
+	}
+
+	switch dm.Status.Phase {
+	case v1alpha1.DeviceMountMounted:
+		if dm.Generation != dm.Status.ObservedGeneration {
+			return reconcile.Result{}, c.rejectSpecChange(ctx, dm)
+		}
+		return reconcile.Result{}, nil
+	case v1alpha1.DeviceMountUnmounting:
+		return reconcile.Result{}, nil
+	case v1alpha1.DeviceMountFailed:
+		// Retry failed mounts periodically, or immediately after the spec is changed.
+		if dm.Generation == dm.Status.ObservedGeneration && time.Since(c.lastTransitionTime(dm)) < failedRetryPeriod {
+			return reconcile.Result{RequeueAfter: failedRetryPeriod - time.Since(c.lastTransitionTime(dm))}, nil
+		}
+	case v1alpha1.DeviceMountMounting:
+		// The last mount was interrupted, the repeated request returns
+		// the devices if the slave pods of the object have been created.
+		if c.hasRequestSlavePods(dm, pod) {
+			return c.mount(ctx, dm)
+		}
+	}
+
+	if pod.Status.Phase != v1.PodRunning {
+		if dm.Status.Phase != v1alpha1.DeviceMountPending {
+			dm.Status.Phase = v1alpha1.DeviceMountPending
+			dm.Status.Message = "Waiting for the target pod to be running"
+			if err = c.updateStatus(ctx, dm); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{RequeueAfter: pendingRetryPeriod}, nil
+	}
+
+	return c.mount(ctx, dm)
+}
+
+func (c *deviceMountController) mount(ctx context.Context, dm *v1alpha1.DeviceMount) (reconcile.Result, error) {
+	dm.Status.Phase = v1alpha1.DeviceMountMounting
+	dm.Status.NodeName = c.nodeName
+	dm.Status.Message = ""
+	dm.Status.ObservedGeneration = dm.Generation
+	if err := c.updateStatus(ctx, dm); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	resources := make(map[string]string, len(dm.Spec.Resources))
+	for name, quantity := range dm.Spec.Resources {
+		resources[string(name)] = quantity.String()
+	}
+	req := &api.MountDeviceRequest{
+		PodName:      dm.Spec.PodName,
+		PodNamespace: dm.Namespace,
+		Resources:    resources,
+		Annotations:  dm.Spec.Annotations,
+		Labels:       dm.Spec.Labels,
+		DeviceType:   dm.Spec.DeviceType,
+		Patches:      dm.Spec.Patches,
+		// Do not mount the devices again when the status of the last mount failed to be updated.
+		RequestId: string(dm.UID),
+	}
+	if dm.Spec.Container != "" {
+		req.Container = &api.Container{Name: dm.Spec.Container}
+	}
+	mountCtx, cancelFunc := context.WithTimeout(ctx, deviceMountTimeout)
+	defer cancelFunc()
+	resp, err := c.mounter.MountDevice(mountCtx, req)
+	if err != nil {
+		resp = &api.DeviceResponse{Result: api.ResultCode_Fail, Message: err.Error()}
+	}
+	if resp.Result != api.ResultCode_Success {
+		c.setFailed(dm, resp.Result.String(), resp.Message)
+		c.recorder.Event(dm, v1.EventTypeWarning, "MountDeviceFailed", resp.Message)
+		return reconcile.Result{RequeueAfter: failedRetryPeriod}, c.updateStatus(ctx, dm)
+	}
+
+	c.setMounted(dm, resp)
+	c.recorder.Event(dm, v1.EventTypeNormal, "MountDevice", resp.Message)
+	return reconcile.Result{}, c.updateStatus(ctx, dm)
+}
+
+// rejectSpecChange The spec of the mounted object is immutable, the changes that bypassed the validation
+// of the CRD (e.g. the cluster does not support validation rules) are reported and not applied.
+func (c *deviceMountController) rejectSpecChange(ctx context.Context, dm *v1alpha1.DeviceMount) error {
+	message := fmt.Sprintf("The changes of spec generation %d are not applied, "+
+		"delete and recreate the DeviceMount to change the mounted devices", dm.Generation)
+	if dm.Status.Message == message {
+		return nil
+	}
+	dm.Status.Message = message
+	c.recorder.Event(dm, v1.EventTypeWarning, "SpecImmutable", message)
+	return c.updateStatus(ctx, dm)
+}
+
+func (c *deviceMountController) finalize(ctx context.Context, dm *v1alpha1.DeviceMount, pod *v1.Pod) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(dm, v1alpha1.DeviceMountFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	mounted := dm.Status.Phase == v1alpha1.DeviceMountMounted ||
+		dm.Status.Phase == v1alpha1.DeviceMountMounting ||
+		dm.Status.Phase == v1alpha1.DeviceMountUnmounting
+	if pod != nil && pod.DeletionTimestamp.IsZero() && mounted {
+		if dm.Status.Phase != v1alpha1.DeviceMountUnmounting {
+			dm.Status.Phase = v1alpha1.DeviceMountUnmounting
+			dm.Status.Message = ""
+			if err := c.updateStatus(ctx, dm); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		req := &api.UnMountDeviceRequest{
+			PodName:      dm.Spec.PodName,
+			PodNamespace: dm.Namespace,
+			DeviceType:   dm.Spec.DeviceType,
+			Force:        dm.Spec.ForceUnmount,
+			// Only unmount the devices mounted by this object, see mount.
+			MountRequestId: string(dm.UID),
+		}
+		if dm.Spec.Container != "" {
+			req.Container = &api.Container{Name: dm.Spec.Container}
+		}
+		unmountCtx, cancelFunc := context.WithTimeout(ctx, deviceMountTimeout)
+		defer cancelFunc()
+		resp, err := c.mounter.UnMountDevice(unmountCtx, req)
+		if err != nil {
+			resp = &api.DeviceResponse{Result: api.ResultCode_Fail, Message: err.Error()}
+		}
+		// The devices have been removed when they are not found.
+		if resp.Result != api.ResultCode_Success && resp.Result != api.ResultCode_NotFound {
+			dm.Status.Message = resp.Message
+			c.recorder.Event(dm, v1.EventTypeWarning, "UnMountDeviceFailed", resp.Message)
+			return reconcile.Result{RequeueAfter: failedRetryPeriod}, c.updateStatus(ctx, dm)
+		}
+		c.recorder.Event(dm, v1.EventTypeNormal, "UnMountDevice", resp.Message)
+	}
+	controllerutil.RemoveFinalizer(dm, v1alpha1.DeviceMountFinalizer)
+	return reconcile.Result{}, c.update(ctx, dm)
+}
+
+// hasRequestSlavePods Whether the slave pods created by the mount request of the object exist,
+// the devices mounted by other objects or the REST API are not counted.
+func (c *deviceMountController) hasRequestSlavePods(dm *v1alpha1.DeviceMount, pod *v1.Pod) bool {
+	selector := labels.SelectorFromSet(labels.Set{config.OwnerUidLabelKey: string(pod.UID)})
+	slavePods, err := c.podLister.Pods(pod.Namespace).List(selector)
+	if err != nil {
+		return false
+	}
+	for _, slavePod := range slavePods {
+		if slavePod.Annotations[config.RequestIdAnnotationKey] == string(dm.UID) &&
+			strings.EqualFold(slavePod.Annotations[config.DeviceTypeAnnotationKey], dm.Spec.DeviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// setMounted Record the devices and the slave pods of the mount response, which only contains the devices mounted by the object.
+func (c *deviceMountController) setMounted(dm *v1alpha1.DeviceMount, resp *api.DeviceResponse) {
+	dm.Status.Phase = v1alpha1.DeviceMountMounted
+	dm.Status.NodeName = c.nodeName
+	dm.Status.Message = resp.GetMessage()
+	dm.Status.MountedDevices = nil
+	for _, device := range resp.GetDevices() {
+		dm.Status.MountedDevices = append(dm.Status.MountedDevices, v1alpha1.MountedDevice{
+			DeviceID:       device.GetDeviceId(),
+			DeviceFilePath: device.GetDeviceFilePath(),
+			Type:           device.GetType(),
+			Major:          device.GetMajor(),
+			Minor:          device.GetMinor(),
+			Permissions:    device.GetPermissions(),
+		})
+	}
+	dm.Status.SlavePods = resp.GetSlavePods()
+	meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.DeviceMountConditionMounted,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Mounted",
+		Message:            resp.GetMessage(),
+		ObservedGeneration: dm.Generation,
+	})
+}
+
+func (c *deviceMountController) setFailed(dm *v1alpha1.DeviceMount, reason, message string) {
+	dm.Status.Phase = v1alpha1.DeviceMountFailed
+	dm.Status.Message = message
+	// Always refresh the transition time, which is used to delay the next retry.
+	meta.RemoveStatusCondition(&dm.Status.Conditions, v1alpha1.DeviceMountConditionMounted)
+	meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.DeviceMountConditionMounted,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: dm.Generation,
+	})
+}
+
+func (c *deviceMountController) lastTransitionTime(dm *v1alpha1.DeviceMount) time.Time {
+	condition := meta.FindStatusCondition(dm.Status.Conditions, v1alpha1.DeviceMountConditionMounted)
+	if condition == nil {
+		return time.Time{}
+	}
+	return condition.LastTransitionTime.Time
+}
+
+func (c *deviceMountController) update(ctx context.Context, dm *v1alpha1.DeviceMount) error {
+	u, err := toUnstructured(dm)
+	if err != nil {
+		return err
+	}
+	result, err := c.client.Resource(v1alpha1.DeviceMountResource).Namespace(dm.Namespace).Update(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	dm.ResourceVersion = result.GetResourceVersion()
+	return nil
+}
+
+func (c *deviceMountController) updateStatus(ctx context.Context, dm *v1alpha1.DeviceMount) error {
+	u, err := toUnstructured(dm)
+	if err != nil {
+		return err
+	}
+	result, err := c.client.Resource(v1alpha1.DeviceMountResource).Namespace(dm.Namespace).UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	dm.ResourceVersion = result.GetResourceVersion()
+	return nil
+}
+
+func toUnstructured(dm *v1alpha1.DeviceMount) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dm)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("DeviceMount"))
+	return u, nil
+}
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeMounter struct {
+	api.UnimplementedDeviceMountServiceServer
+	mountCalls   int
+	unmountCalls int
+	lastUnmount  *api.UnMountDeviceRequest
+}
+
+func (m *fakeMounter) MountDevice(_ context.Context, req *api.MountDeviceRequest) (*api.DeviceResponse, error) {
+	m.mountCalls++
+	return &api.DeviceResponse{
+		Result:  api.ResultCode_Success,
+		Message: "Successfully mounted " + req.DeviceType + " devices",
+		Devices: []*api.MountedDevice{{
+			DeviceId: "GPU-0", DeviceFilePath: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0, Permissions: "rw",
+		}},
+		SlavePods: []string{"default/test-slave-pod"},
+	}, nil
+}
+
+func (m *fakeMounter) UnMountDevice(_ context.Context, req *api.UnMountDeviceRequest) (*api.DeviceResponse, error) {
+	m.unmountCalls++
+	m.lastUnmount = req
+	return &api.DeviceResponse{Result: api.ResultCode_Success, Message: "Successfully uninstalled " + req.DeviceType + " devices"}, nil
+}
+
+func Test_DeviceMountController(t *testing.T) {
+	dm := &v1alpha1.DeviceMount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "DeviceMount"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "dm-uid", Generation: 1},
+		Spec: v1alpha1.DeviceMountSpec{
+			PodName:    "test-pod",
+			Container:  "main",
+			DeviceType: "NVIDIA_GPU",
+			Resources:  v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+		},
+	}
+	u, err := toUnstructured(dm)
+	assert.NoError(t, err)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "uid"},
+		Spec:       v1.PodSpec{NodeName: "node1", Containers: []v1.Container{{Name: "main"}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{v1alpha1.DeviceMountResource: "DeviceMountList"}, u)
+	dmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{DeviceMountPodIndex: DeviceMountPodIndexFunc})
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, podIndexer.Add(pod))
+
+	mounter := &fakeMounter{}
+	c := &deviceMountController{
+		name:      "test",
+		nodeName:  "node1",
+		client:    dynamicClient,
+		indexer:   dmIndexer,
+		podLister: listerv1.NewPodLister(podIndexer),
+		mounter:   mounter,
+		recorder:  record.NewFakeRecorder(10),
+		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	key := client.ObjectKeyFromObject(dm)
+	ctx := context.Background()
+	// sync the informer cache from the fake client
+	syncCache := func() *v1alpha1.DeviceMount {
+		obj, err := dynamicClient.Resource(v1alpha1.DeviceMountResource).Namespace(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, dmIndexer.Update(obj))
+		current := &v1alpha1.DeviceMount{}
+		assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), current))
+		return current
+	}
+	syncCache()
+
+	objs, err := dmIndexer.ByIndex(DeviceMountPodIndex, "default/test-pod")
+	assert.NoError(t, err)
+	assert.Len(t, objs, 1)
+
+	// add finalizer
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	current := syncCache()
+	assert.Contains(t, current.Finalizers, v1alpha1.DeviceMountFinalizer)
+
+	// mount devices
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	current = syncCache()
+	assert.Equal(t, 1, mounter.mountCalls)
+	assert.Equal(t, v1alpha1.DeviceMountMounted, current.Status.Phase)
+	assert.Equal(t, "node1", current.Status.NodeName)
+	assert.Len(t, current.Status.MountedDevices, 1)
+	assert.Equal(t, "/dev/nvidia0", current.Status.MountedDevices[0].DeviceFilePath)
+	assert.Equal(t, []string{"default/test-slave-pod"}, current.Status.SlavePods)
+
+	// mounted object is not mounted again
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mounter.mountCalls)
+	assert.Equal(t, int64(1), current.Status.ObservedGeneration)
+
+	// the spec changes of the mounted object are reported and not applied
+	obj, _, _ := dmIndexer.GetByKey(key.String())
+	changed := obj.(*unstructured.Unstructured).DeepCopy()
+	changed.SetGeneration(2)
+	assert.NoError(t, dmIndexer.Update(changed))
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mounter.mountCalls)
+	current = syncCache()
+	assert.Equal(t, v1alpha1.DeviceMountMounted, current.Status.Phase)
+	assert.Equal(t, int64(1), current.Status.ObservedGeneration)
+	assert.Contains(t, current.Status.Message, "generation 2 are not applied")
+
+	// deleting the object unmounts the devices mounted by the object and removes the finalizer
+	obj, _, _ = dmIndexer.GetByKey(key.String())
+	deleting := obj.(*unstructured.Unstructured).DeepCopy()
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+	assert.NoError(t, dmIndexer.Update(deleting))
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mounter.unmountCalls)
+	assert.Equal(t, "dm-uid", mounter.lastUnmount.MountRequestId)
+	current = syncCache()
+	assert.NotContains(t, current.Finalizers, v1alpha1.DeviceMountFinalizer)
+}
+
+func Test_DeviceMountController_OtherNode(t *testing.T) {
+	dm := &v1alpha1.DeviceMount{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       v1alpha1.DeviceMountSpec{PodName: "test-pod", DeviceType: "NVIDIA_GPU"},
+	}
+	u, err := toUnstructured(dm)
+	assert.NoError(t, err)
+	dmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, dmIndexer.Add(u))
+	mounter := &fakeMounter{}
+	c := &deviceMountController{
+		nodeName:  "node1",
+		indexer:   dmIndexer,
+		podLister: listerv1.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		mounter:   mounter,
+	}
+	// The target pod is not on the current node, the object is skipped.
+	rs, err := c.reconcile(context.Background(), client.ObjectKeyFromObject(dm))
+	assert.NoError(t, err)
+	assert.True(t, rs.IsZero())
+	assert.Equal(t, 0, mounter.mountCalls)
+}
+
+func Test_DeviceMountController_RecoverMounting(t *testing.T) {
+	dm := &v1alpha1.DeviceMount{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "dm-uid", Generation: 1,
+			Finalizers: []string{v1alpha1.DeviceMountFinalizer}},
+		Spec:   v1alpha1.DeviceMountSpec{PodName: "test-pod", DeviceType: "NVIDIA_GPU"},
+		Status: v1alpha1.DeviceMountStatus{Phase: v1alpha1.DeviceMountMounting, NodeName: "node1", ObservedGeneration: 1},
+	}
+	u, err := toUnstructured(dm)
+	assert.NoError(t, err)
+	// The target pod is restarting, the mount is only replayed for the slave pods of the object.
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "uid"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+	slavePod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "test-slave-pod", Namespace: "default",
+		Labels: map[string]string{config.OwnerUidLabelKey: "uid"},
+		Annotations: map[string]string{
+			config.DeviceTypeAnnotationKey: "NVIDIA_GPU",
+			config.RequestIdAnnotationKey:  "other-request",
+		},
+	}}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{v1alpha1.DeviceMountResource: "DeviceMountList"}, u)
+	dmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, dmIndexer.Add(u))
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, podIndexer.Add(pod))
+	assert.NoError(t, podIndexer.Add(slavePod))
+
+	mounter := &fakeMounter{}
+	c := &deviceMountController{
+		nodeName:  "node1",
+		client:    dynamicClient,
+		indexer:   dmIndexer,
+		podLister: listerv1.NewPodLister(podIndexer),
+		mounter:   mounter,
+		recorder:  record.NewFakeRecorder(10),
+	}
+	key := client.ObjectKeyFromObject(dm)
+	ctx := context.Background()
+	getCurrent := func() *v1alpha1.DeviceMount {
+		obj, err := dynamicClient.Resource(v1alpha1.DeviceMountResource).Namespace(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, dmIndexer.Update(obj))
+		current := &v1alpha1.DeviceMount{}
+		assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), current))
+		return current
+	}
+
+	// The devices mounted by another request do not mark the object as mounted.
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, mounter.mountCalls)
+	assert.Equal(t, v1alpha1.DeviceMountPending, getCurrent().Status.Phase)
+
+	current := getCurrent()
+	current.Status.Phase = v1alpha1.DeviceMountMounting
+	u, err = toUnstructured(current)
+	assert.NoError(t, err)
+	_, err = dynamicClient.Resource(v1alpha1.DeviceMountResource).Namespace(key.Namespace).UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	getCurrent()
+	slavePod = slavePod.DeepCopy()
+	slavePod.Annotations[config.RequestIdAnnotationKey] = "dm-uid"
+	assert.NoError(t, podIndexer.Update(slavePod))
+
+	_, err = c.reconcile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mounter.mountCalls)
+	current = getCurrent()
+	assert.Equal(t, v1alpha1.DeviceMountMounted, current.Status.Phase)
+	assert.Equal(t, []string{"default/test-slave-pod"}, current.Status.SlavePods)
+	assert.Len(t, current.Status.MountedDevices, 1)
+}
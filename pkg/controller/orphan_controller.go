@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// OrphanPolicy What to do with the slave pods whose owner container has been restarted.
+type OrphanPolicy string
+
+const (
+	// OrphanPolicyIgnore Only report the orphaned slave pods with an event.
+	OrphanPolicyIgnore OrphanPolicy = "Ignore"
+	// OrphanPolicyDelete Garbage collect the orphaned slave pods to release the devices.
+	OrphanPolicyDelete OrphanPolicy = "Delete"
+	// OrphanPolicyRemount Re-mount the devices of the slave pods into the restarted container.
+	OrphanPolicyRemount OrphanPolicy = "Remount"
+)
+
+// ParseOrphanPolicy Verify the configured orphan policy.
+func ParseOrphanPolicy(policy string) (OrphanPolicy, error) {
+	switch p := OrphanPolicy(policy); p {
+	case OrphanPolicyIgnore, OrphanPolicyDelete, OrphanPolicyRemount:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported orphan policy %q, supported values: %s, %s, %s",
+			policy, OrphanPolicyIgnore, OrphanPolicyDelete, OrphanPolicyRemount)
+	}
+}
+
+// DeviceRemounter Re-apply the devices held by the slave pods to the restarted container of the owner pod.
+type DeviceRemounter interface {
+	RemountDevices(ctx context.Context, ownerPod *v1.Pod, containerName, deviceType string, slavePods []*v1.Pod) error
+}
+
+type orphanPodController struct {
+	name      string
+	client    kubernetes.Interface
+	podLister listerv1.PodLister
+	remounter DeviceRemounter
+	recorder  record.EventRecorder
+	policy    OrphanPolicy
+	interval  time.Duration
+	queue     workqueue.RateLimitingInterface
+}
+
+var _ cache.ResourceEventHandler = &orphanPodController{}
+
+func NewOrphanPodController(name string, kubeClient kubernetes.Interface, podLister listerv1.PodLister,
+	remounter DeviceRemounter, recorder record.EventRecorder, policy OrphanPolicy, interval time.Duration) *orphanPodController {
+	return &orphanPodController{
+		name:      name,
+		client:    kubeClient,
+		podLister: podLister,
+		remounter: remounter,
+		recorder:  recorder,
+		policy:    policy,
+		interval:  interval,
+		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+}
+
+func (c *orphanPodController) OnAdd(_ interface{}, _ bool) {}
+
+// OnUpdate Handle the owner pods immediately after their containers are restarted.
+func (c *orphanPodController) OnUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok || oldPod.ResourceVersion == newPod.ResourceVersion {
+		return
+	}
+	if !containerIDChanged(oldPod, newPod) || !c.hasSlavePods(newPod) {
+		return
+	}
+	klog.V(4).Infoln("Container id of pod has changed", "pod", newPod.Namespace+"/"+newPod.Name)
+	c.queue.Add(client.ObjectKeyFromObject(newPod))
+}
+
+func (c *orphanPodController) OnDelete(_ interface{}) {}
+
+// containerIDChanged Whether a container of the pod has been started with a new id.
+func containerIDChanged(oldPod, newPod *v1.Pod) bool {
+	allStatuses := func(pod *v1.Pod) []v1.ContainerStatus {
+		statuses := append([]v1.ContainerStatus{}, pod.Status.ContainerStatuses...)
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		return append(statuses, pod.Status.EphemeralContainerStatuses...)
+	}
+	oldIDs := make(map[string]string)
+	for _, status := range allStatuses(oldPod) {
+		oldIDs[status.Name] = status.ContainerID
+	}
+	for _, status := range allStatuses(newPod) {
+		if len(status.ContainerID) > 0 && status.ContainerID != oldIDs[status.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *orphanPodController) hasSlavePods(pod *v1.Pod) bool {
+	slavePods, err := c.podLister.Pods(pod.Namespace).List(labels.SelectorFromSet(labels.Set{
+		config.OwnerNameLabelKey: pod.Name,
+		config.OwnerUidLabelKey:  string(pod.UID),
+	}))
+	return err == nil && len(slavePods) > 0
+}
+
+// slaveOwner The owner container of a group of slave pods.
+type slaveOwner struct {
+	namespace  string
+	name       string
+	uid        string
+	container  string
+	deviceType string
+	// The id of the container when the devices were mounted.
+	containerID string
+}
+
+func (o slaveOwner) String() string {
+	return fmt.Sprintf("%s/%s/%s", o.namespace, o.name, o.container)
+}
+
+func newSlaveOwner(slavePod *v1.Pod) slaveOwner {
+	return slaveOwner{
+		namespace:   slavePod.Namespace,
+		name:        slavePod.Labels[config.OwnerNameLabelKey],
+		uid:         slavePod.Labels[config.OwnerUidLabelKey],
+		container:   slavePod.Labels[config.MountContainerLabelKey],
+		deviceType:  slavePod.Annotations[config.DeviceTypeAnnotationKey],
+		containerID: slavePod.Annotations[config.ContainerIdAnnotationKey],
+	}
+}
+
+// ListSlavePods List the slave pods managed by the device mounter in the lister.
+func ListSlavePods(podLister listerv1.PodLister) ([]*v1.Pod, error) {
+	requirement, err := labels.NewRequirement(config.CreatedByLabelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(labels.Set{
+		config.AppComponentLabelKey: config.CreateManagerBy,
+		config.AppManagedByLabelKey: config.CreateManagerBy,
+	}).Add(*requirement)
+	return podLister.List(selector)
+}
+
+// OwnerContainerID Get the current id of the owner container of the slave pod.
+func OwnerContainerID(podLister listerv1.PodLister, slavePod *v1.Pod) (string, bool) {
+	owner := newSlaveOwner(slavePod)
+	pod, err := podLister.Pods(owner.namespace).Get(owner.name)
+	if err != nil || string(pod.UID) != owner.uid {
+		return "", false
+	}
+	status, ok := util.GetContainerStatus(pod, owner.container)
+	if !ok || len(status.ContainerID) == 0 {
+		return "", false
+	}
+	return status.ContainerID, true
+}
+
+func (c *orphanPodController) Start(ctx context.Context, workerNum int) {
+	klog.Infoln(c.name, "started with policy", c.policy)
+	go func() {
+		<-ctx.Done()
+		klog.Infoln(c.name, "is stopping...")
+		c.queue.ShutDown()
+	}()
+	// Periodically check all slave pods in case an event was missed.
+	go wait.UntilWithContext(ctx, c.enqueueAll, c.interval)
+	wg := &sync.WaitGroup{}
+	wg.Add(workerNum)
+	for i := 0; i < workerNum; i++ {
+		go func() {
+			defer wg.Done()
+			for processNextWorkItem(ctx, c.queue, c.reconcile) {
+			}
+		}()
+	}
+	wg.Wait()
+	klog.Infoln(c.name, "stopped")
+}
+
+// enqueueAll Enqueue the owner pods of all slave pods on the node.
+func (c *orphanPodController) enqueueAll(_ context.Context) {
+	slavePods, err := ListSlavePods(c.podLister)
+	if err != nil {
+		klog.ErrorS(err, "List slave pods failed")
+		return
+	}
+	for _, slavePod := range slavePods {
+		c.queue.Add(client.ObjectKey{Namespace: slavePod.Namespace, Name: slavePod.Labels[config.OwnerNameLabelKey]})
+	}
+}
+
+// reconcile Compare the slave pods of the owner pod with the live status of the owner containers.
+func (c *orphanPodController) reconcile(ctx context.Context, req client.ObjectKey) (reconcile.Result, error) {
+	slavePods, err := c.podLister.Pods(req.Namespace).List(labels.SelectorFromSet(labels.Set{
+		config.OwnerNameLabelKey:    req.Name,
+		config.AppComponentLabelKey: config.CreateManagerBy,
+		config.AppManagedByLabelKey: config.CreateManagerBy,
+	}))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	groups := make(map[slaveOwner][]*v1.Pod)
+	for _, slavePod := range slavePods {
+		if !slavePod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		owner := newSlaveOwner(slavePod)
+		groups[owner] = append(groups[owner], slavePod.DeepCopy())
+	}
+	var (
+		errs    []error
+		requeue bool
+	)
+	for owner, pods := range groups {
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		waiting, err := c.reconcileOwner(ctx, owner, pods)
+		if err != nil {
+			klog.ErrorS(err, "Reconcile orphaned slave pods failed", "owner", owner.String())
+			errs = append(errs, err)
+		}
+		requeue = requeue || waiting
+	}
+	if requeue {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, utilerrors.NewAggregate(errs)
+	}
+	return reconcile.Result{}, utilerrors.NewAggregate(errs)
+}
+
+// reconcileOwner Handle the slave pods of one owner container,
+// returns true when waiting for the owner container to be running.
+func (c *orphanPodController) reconcileOwner(ctx context.Context, owner slaveOwner, slavePods []*v1.Pod) (bool, error) {
+	pod, err := c.podLister.Pods(owner.namespace).Get(owner.name)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	// The owner pod no longer exists, the devices can only be released.
+	if errors.IsNotFound(err) || string(pod.UID) != owner.uid {
+		klog.Infoln("Owner pod of slave pods is gone", "owner", owner.String())
+		if c.policy == OrphanPolicyIgnore {
+			return false, nil
+		}
+		return false, c.deleteSlavePods(ctx, slavePods)
+	}
+	if !pod.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+	status, ok := util.GetContainerStatus(pod, owner.container)
+	if !ok || status.State.Running == nil {
+		// The owner container has exited and will not be restarted.
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			klog.Infoln("Owner container of slave pods has terminated", "owner", owner.String())
+			if c.policy == OrphanPolicyIgnore {
+				return false, nil
+			}
+			return false, c.deleteSlavePods(ctx, slavePods)
+		}
+		// Waiting for the container to be restarted.
+		return status != nil && status.ContainerID != owner.containerID, nil
+	}
+	if status.ContainerID == owner.containerID {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Container %s has been restarted, the %s devices held by %d slave pods are no longer mounted",
+		owner.container, owner.deviceType, len(slavePods))
+	klog.Infoln(message, "owner", owner.String(), "policy", c.policy)
+	switch c.policy {
+	case OrphanPolicyDelete:
+		c.recorder.Event(pod, v1.EventTypeWarning, "OrphanedSlavePods", message+", releasing the devices")
+		return false, c.deleteSlavePods(ctx, slavePods)
+	case OrphanPolicyRemount:
+		if err = c.remounter.RemountDevices(ctx, pod, owner.container, owner.deviceType, slavePods); err != nil {
+			c.recorder.Event(pod, v1.EventTypeWarning, "RemountDeviceFailed", err.Error())
+			return false, err
+		}
+	default:
+		c.recorder.Event(pod, v1.EventTypeWarning, "OrphanedSlavePods", message)
+	}
+	return false, nil
+}
+
+func (c *orphanPodController) deleteSlavePods(ctx context.Context, slavePods []*v1.Pod) error {
+	var lastErr error
+	for _, slavePod := range slavePods {
+		klog.Infoln("Garbage collection orphaned slave pod", slavePod.Namespace+"/"+slavePod.Name)
+		options := metav1.DeleteOptions{
+			GracePeriodSeconds: new(int64),
+			Preconditions:      metav1.NewUIDPreconditions(string(slavePod.UID)),
+		}
+		err := c.client.CoreV1().Pods(slavePod.Namespace).Delete(ctx, slavePod.Name, options)
+		if err != nil && !errors.IsNotFound(err) {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeRemounter struct {
+	calls int
+}
+
+func (r *fakeRemounter) RemountDevices(_ context.Context, _ *v1.Pod, _, _ string, _ []*v1.Pod) error {
+	r.calls++
+	return nil
+}
+
+func newTestSlavePod(name, containerID string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       "slave-uid",
+			Labels: map[string]string{
+				config.CreatedByLabelKey:      "id",
+				config.OwnerNameLabelKey:      "owner",
+				config.OwnerUidLabelKey:       "owner-uid",
+				config.MountContainerLabelKey: "main",
+				config.AppComponentLabelKey:   config.CreateManagerBy,
+				config.AppManagedByLabelKey:   config.CreateManagerBy,
+			},
+			Annotations: map[string]string{
+				config.DeviceTypeAnnotationKey:  "NVIDIA_GPU",
+				config.ContainerIdAnnotationKey: containerID,
+			},
+		},
+	}
+}
+
+func Test_OrphanPodController(t *testing.T) {
+	owner := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:        "main",
+				ContainerID: "containerd://new",
+				State:       v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			}},
+		},
+	}
+	tests := []struct {
+		name         string
+		policy       OrphanPolicy
+		containerID  string
+		wantDeleted  bool
+		wantRemounts int
+	}{
+		{name: "container not restarted", policy: OrphanPolicyDelete, containerID: "containerd://new"},
+		{name: "ignore", policy: OrphanPolicyIgnore, containerID: "containerd://old"},
+		{name: "delete", policy: OrphanPolicyDelete, containerID: "containerd://old", wantDeleted: true},
+		{name: "remount", policy: OrphanPolicyRemount, containerID: "containerd://old", wantRemounts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slavePod := newTestSlavePod("slave", tt.containerID)
+			kubeClient := fake.NewSimpleClientset(owner, slavePod)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			assert.NoError(t, indexer.Add(owner))
+			assert.NoError(t, indexer.Add(slavePod))
+			remounter := &fakeRemounter{}
+			c := NewOrphanPodController("test", kubeClient, listerv1.NewPodLister(indexer),
+				remounter, record.NewFakeRecorder(10), tt.policy, 0)
+
+			_, err := c.reconcile(context.Background(), client.ObjectKeyFromObject(owner))
+			assert.NoError(t, err)
+
+			pods, err := kubeClient.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			if tt.wantDeleted {
+				assert.Len(t, pods.Items, 1)
+			} else {
+				assert.Len(t, pods.Items, 2)
+			}
+			assert.Equal(t, tt.wantRemounts, remounter.calls)
+		})
+	}
+}
+
+func Test_OrphanPodController_OnUpdate(t *testing.T) {
+	owner := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid", ResourceVersion: "1"},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "main", ContainerID: "containerd://old"}},
+		},
+	}
+	restarted := owner.DeepCopy()
+	restarted.ResourceVersion = "2"
+	restarted.Status.ContainerStatuses[0].ContainerID = "containerd://new"
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(restarted))
+	c := NewOrphanPodController("test", fake.NewSimpleClientset(), listerv1.NewPodLister(indexer),
+		&fakeRemounter{}, record.NewFakeRecorder(10), OrphanPolicyRemount, 0)
+
+	// The pod without slave pods is ignored.
+	c.OnUpdate(owner, restarted)
+	assert.Equal(t, 0, c.queue.Len())
+
+	assert.NoError(t, indexer.Add(newTestSlavePod("slave", "containerd://old")))
+	c.OnUpdate(owner, owner)
+	assert.Equal(t, 0, c.queue.Len())
+	c.OnUpdate(owner, restarted)
+	assert.Equal(t, 1, c.queue.Len())
+}
@@ -287,6 +287,12 @@ func (c *slavePodController) handleMetadataFix(ctx context.Context, req client.O
 	// 修复annotations
 	annos := make(map[string]string)
 	comparableKeys(metadata.Annotations, newPod.Annotations, annos, podAnnoKeys)
+	// The container id is updated after the devices are re-mounted into the restarted container.
+	if _, ok := annos[config.ContainerIdAnnotationKey]; ok {
+		if id, ok := OwnerContainerID(c.podLister, newPod); ok && id == newPod.Annotations[config.ContainerIdAnnotationKey] {
+			delete(annos, config.ContainerIdAnnotationKey)
+		}
+	}
 	util.CopyMap(annos, newPod.Annotations)
 
 	// 修复OwnerReferences
@@ -16,7 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
-	"k8s.io/kubelet/pkg/apis/podresources/v1alpha1"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
 )
 
 type NPUCollector struct {
@@ -24,31 +24,30 @@ type NPUCollector struct {
 	*devmanager.DeviceManager
 }
 
-func (c *NPUCollector) GetPodNPUResourcesFunc(matchFunc func(*v1alpha1.PodResources) (int, bool), f func(*v1alpha1.PodResources, int) error) error {
+// GetPodsNPUResourcesFunc Get the pod resources of each pod, the pods not found on kubelet are skipped.
+func (c *NPUCollector) GetPodsNPUResourcesFunc(pods []*v1.Pod, f func(*podresourcesv1.PodResources, int) error) error {
 	c.Lock()
 	defer c.Unlock()
-	resClient := client.GetPodResourcesClinet().GetClient()
+	resClient := client.GetPodResourcesClinet()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := resClient.List(ctx, &v1alpha1.ListPodResourcesRequest{})
-	if err != nil {
-		return err
-	}
-	for _, resources := range resp.GetPodResources() {
+	for i, pod := range pods {
+		resources, err := resClient.GetPodResources(ctx, pod.Name, pod.Namespace)
+		if err != nil {
+			return err
+		}
 		if resources == nil {
 			continue
 		}
-		if idx, ok := matchFunc(resources); ok {
-			if err = f(resources, idx); err != nil {
-				return err
-			}
+		if err = f(resources, i); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func GetRealDeviceForAnnotations(ctx context.Context, kubeClient *kubernetes.Clientset, pod *v1.Pod) []string {
+func GetRealDeviceForAnnotations(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod) []string {
 	kltDevStr, ok1 := pod.Annotations[common.ResourceNamePrefix+common.Pod2kl]
 	realDevStr, ok2 := pod.Annotations[common.ResourceNamePrefix+common.PodRealAlloc]
 	if !ok1 || !ok2 {
@@ -66,24 +65,15 @@ func GetRealDeviceForAnnotations(ctx context.Context, kubeClient *kubernetes.Cli
 	return nil
 }
 
-func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient *kubernetes.Clientset, slavePods []*v1.Pod, f func(devId int) (api.DeviceInfo, error)) ([]api.DeviceInfo, error) {
-	var containerDevices []*v1alpha1.ContainerDevices
-
-	matchFunc := func(res *v1alpha1.PodResources) (int, bool) {
-		for i, pod := range slavePods {
-			if res.Name == pod.Name && res.Namespace == pod.Namespace {
-				return i, true
-			}
-		}
-		return 0, false
-	}
-	loadFunc := func(resources *v1alpha1.PodResources, idx int) error {
+func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient kubernetes.Interface, slavePods []*v1.Pod, f func(devId int) (api.DeviceInfo, error)) ([]api.DeviceInfo, error) {
+	var containerDevices []*podresourcesv1.ContainerDevices
+	loadFunc := func(resources *podresourcesv1.PodResources, idx int) error {
 		pod := slavePods[idx]
 		klog.Infoln("Current matched npu slave pod", "name", pod.Name, "namespace", pod.Namespace)
 		// TODO 通过注解得知真实分配的设备
 		realDevs := GetRealDeviceForAnnotations(ctx, kubeClient, pod)
 		if len(realDevs) > 0 {
-			containerDevices = append(containerDevices, &v1alpha1.ContainerDevices{
+			containerDevices = append(containerDevices, &podresourcesv1.ContainerDevices{
 				ResourceName: common.ResourceNamePrefix,
 				DeviceIds:    realDevs,
 			})
@@ -96,7 +86,7 @@ func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient *k
 			if len(containerResources.GetDevices()) == 0 {
 				continue
 			}
-			var ctrDevices []*v1alpha1.ContainerDevices
+			var ctrDevices []*podresourcesv1.ContainerDevices
 
 			// TODO 从volcano获得分配的设备
 			for _, dev := range containerResources.GetDevices() {
@@ -122,7 +112,7 @@ func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient *k
 					if len(deviceInfos) > 1 {
 						realDevs = GetRealDeviceForAnnotations(ctx, kubeClient, pod)
 						if len(realDevs) > 0 {
-							containerDevices = append(containerDevices, &v1alpha1.ContainerDevices{
+							containerDevices = append(containerDevices, &podresourcesv1.ContainerDevices{
 								ResourceName: common.ResourceNamePrefix,
 								DeviceIds:    realDevs,
 							})
@@ -140,13 +130,13 @@ func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient *k
 						devType := convertDevType(c.GetDevType())
 						phyDevs = append(phyDevs, fmt.Sprintf("%s-%s", devType, id))
 					}
-					ctrDevices = []*v1alpha1.ContainerDevices{{
+					ctrDevices = []*podresourcesv1.ContainerDevices{{
 						ResourceName: dev.GetResourceName(),
 						DeviceIds:    phyDevs,
 					}}
 				} else {
 					// 静态设备分配
-					ctrDevices = []*v1alpha1.ContainerDevices{{
+					ctrDevices = []*podresourcesv1.ContainerDevices{{
 						ResourceName: dev.GetResourceName(),
 						DeviceIds:    strings.Split(annotation, common.CommaSepDev),
 					}}
@@ -167,7 +157,7 @@ func (c *NPUCollector) GetSlavePodsDeviceInfo(ctx context.Context, kubeClient *k
 		}
 		return nil
 	}
-	if err := c.GetPodNPUResourcesFunc(matchFunc, loadFunc); err != nil {
+	if err := c.GetPodsNPUResourcesFunc(slavePods, loadFunc); err != nil {
 		return nil, err
 	}
 	var visibleDevices []int
@@ -2,6 +2,7 @@ package npu
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strconv"
@@ -68,25 +69,25 @@ func (m *AscendNPUMounter) GetDeviceType() string {
 	return PluginName
 }
 
-func (m *AscendNPUMounter) ValidateMountRequest(_ context.Context, _ *kubernetes.Clientset,
-	node *v1.Node, ownerPod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity,
+func (m *AscendNPUMounter) ValidateMountRequest(_ context.Context, _ kubernetes.Interface,
+	calculator util.CapacityCalculator, node *v1.Node, ownerPod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity,
 	annotations, labels map[string]string) error {
 
 	condition1 := CheckRequest910Resources(resources)
 	condition2 := CheckRequestDynamicResources(resources, annotations)
 	if !condition1 && !condition2 {
 		msg := "Request for resources error: unsupported resource types"
-		return api.NewMounterError(api.ResultCode_Fail, msg)
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
 	}
-	if !util.CheckResourcesInNode(node, resources) {
-		return api.NewMounterError(api.ResultCode_Insufficient, "Insufficient node resources")
+	if err := util.CheckFreeResourcesInNode(calculator, node, resources); err != nil {
+		return api.NewMounterError(api.ResultCode_Insufficient, err.Error())
 	}
 	// 校验目标容器是否初始化过npu
 	names := ownerPod.Annotations[InitNPUAnnotations]
 	ctrNames := strings.Split(strings.TrimSpace(names), ",")
 	if slices.Contains(ctrNames, container.Name) {
 		msg := "The target container has initialized the NPU and cannot be mounted again"
-		return api.NewMounterError(api.ResultCode_Fail, msg)
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
 	}
 	return nil
 }
@@ -109,7 +110,7 @@ func (m *AscendNPUMounter) VerifySupportPodStatus(_ context.Context, slavePod *v
 	if slavePod.Status.Phase == v1.PodFailed {
 		err := fmt.Errorf("device slave container start failed")
 		if len(slavePod.Status.Message) > 0 {
-			err = fmt.Errorf(slavePod.Status.Message)
+			err = errors.New(slavePod.Status.Message)
 		}
 		return api.Fail, err
 	}
@@ -124,7 +125,7 @@ func (m *AscendNPUMounter) VerifySupportPodStatus(_ context.Context, slavePod *v
 	return api.Wait, nil
 }
 
-func (m *AscendNPUMounter) GetDeviceInfosToMount(ctx context.Context, kubeClient *kubernetes.Clientset,
+func (m *AscendNPUMounter) GetDeviceInfosToMount(ctx context.Context, kubeClient kubernetes.Interface,
 	ownerPod *v1.Pod, container *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 
 	getDevInfoFunc := func(devId int) (api.DeviceInfo, error) {
@@ -178,7 +179,7 @@ func (m *AscendNPUMounter) GetDeviceInfosToMount(ctx context.Context, kubeClient
 	return deviceInfos, nil
 }
 
-func (m *AscendNPUMounter) ExecutePostMountActions(ctx context.Context, kubeClient *kubernetes.Clientset,
+func (m *AscendNPUMounter) ExecutePostMountActions(ctx context.Context, kubeClient kubernetes.Interface,
 	_ util.Config, ownerPod *v1.Pod, container *api.Container, _ []*v1.Pod) error {
 
 	if !HasNPU(ownerPod, container) {
@@ -198,7 +199,7 @@ func (m *AscendNPUMounter) ExecutePostMountActions(ctx context.Context, kubeClie
 	return nil
 }
 
-func (m *AscendNPUMounter) GetDeviceInfosToUnmount(ctx context.Context, kubeClient *kubernetes.Clientset,
+func (m *AscendNPUMounter) GetDeviceInfosToUnmount(ctx context.Context, kubeClient kubernetes.Interface,
 	ownerPod *v1.Pod, container *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 
 	if HasNPU(ownerPod, container) {
@@ -277,7 +278,7 @@ func (m *AscendNPUMounter) GetDevicesActiveProcessIDs(_ context.Context,
 }
 
 // 卸载设备成功前的后续动作
-func (m *AscendNPUMounter) ExecutePostUnmountActions(ctx context.Context, kubeClient *kubernetes.Clientset, _ util.Config, ownerPod *v1.Pod, container *api.Container, _ []*v1.Pod) error {
+func (m *AscendNPUMounter) ExecutePostUnmountActions(ctx context.Context, kubeClient kubernetes.Interface, _ util.Config, ownerPod *v1.Pod, container *api.Container, _ []*v1.Pod) error {
 	if names := strings.TrimSpace(ownerPod.Annotations[InitNPUAnnotations]); len(names) > 0 {
 		oldNames := strings.Split(names, ",")
 		newNames := util.DeleteSliceFunc(oldNames, func(s string) bool {
@@ -293,7 +294,7 @@ func (m *AscendNPUMounter) ExecutePostUnmountActions(ctx context.Context, kubeCl
 	return nil
 }
 
-func (m *AscendNPUMounter) GetPodsToCleanup(_ context.Context, _ *kubernetes.Clientset,
+func (m *AscendNPUMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
 	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) []api.ObjectKey {
 
 	podKeys := make([]api.ObjectKey, len(slavePods))
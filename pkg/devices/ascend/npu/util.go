@@ -6,6 +6,7 @@ import (
 	"Ascend-device-plugin/pkg/common"
 	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+	"github.com/coldzerofear/device-mounter/pkg/util"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -59,7 +60,10 @@ func IsVirtDev(devId int) bool {
 }
 
 func HasNPU(pod *v1.Pod, container *api.Container) bool {
-	c := pod.Spec.Containers[container.Index]
+	c, ok := util.GetContainerSpec(pod, container)
+	if !ok {
+		return false
+	}
 	for name, _ := range c.Resources.Limits {
 		if strings.HasPrefix(string(name), common.ResourceNamePrefix) {
 			return true
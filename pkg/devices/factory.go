@@ -2,6 +2,8 @@ package devices
 
 import (
 	ascend_npu "github.com/coldzerofear/device-mounter/pkg/devices/ascend/npu"
+	"github.com/coldzerofear/device-mounter/pkg/devices/generic"
+	"github.com/coldzerofear/device-mounter/pkg/devices/hostdevice"
 	nvidia_gpu "github.com/coldzerofear/device-mounter/pkg/devices/nvidia/gpu"
 	volcano_vgpu "github.com/coldzerofear/device-mounter/pkg/devices/volcano/vgpu"
 	"github.com/coldzerofear/device-mounter/pkg/framework"
@@ -11,9 +13,15 @@ import (
 var _ framework.DeviceMounter = &nvidia_gpu.NvidiaGPUMounter{}
 var _ framework.DeviceMounter = &volcano_vgpu.VolcanoVGPUMounter{}
 var _ framework.DeviceMounter = &ascend_npu.AscendNPUMounter{}
+var _ framework.DeviceMounter = &generic.GenericMounter{}
+var _ framework.DirectDeviceMounter = &hostdevice.HostDeviceMounter{}
 
 func init() {
 	framework.AddDeviceMounterFuncs(nvidia_gpu.NewNvidiaGPUMounter)
 	framework.AddDeviceMounterFuncs(volcano_vgpu.NewVolcanoVGPUMounter)
 	framework.AddDeviceMounterFuncs(ascend_npu.NewAscendNPUMounter)
+	framework.AddDeviceMounterFuncs(generic.NewGenericMounter)
+	framework.AddDeviceMounterFuncs(hostdevice.NewHostDeviceMounter)
+	framework.ReserveDeviceTypes(nvidia_gpu.PluginName, volcano_vgpu.PluginName,
+		ascend_npu.PluginName, generic.PluginName, hostdevice.PluginName)
 }
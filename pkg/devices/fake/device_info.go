@@ -0,0 +1,183 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/client"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Step The steps of the fake mounter that errors can be injected into.
+type Step string
+
+const (
+	StepGetDeviceInfosToMount      Step = "GetDeviceInfosToMount"
+	StepExecutePostMountActions    Step = "ExecutePostMountActions"
+	StepGetDeviceInfosToUnmount    Step = "GetDeviceInfosToUnmount"
+	StepExecutePostUnmountActions  Step = "ExecutePostUnmountActions"
+	StepGetDevicesActiveProcessIDs Step = "GetDevicesActiveProcessIDs"
+)
+
+// FakeMounter Mount the fake devices allocated to the slave pods, used to test the mount and unmount flow
+// against the simulated node without any hardware. The errors of the steps can be injected to test the rollbacks.
+type FakeMounter struct {
+	mutex  sync.Mutex
+	errors map[Step]error
+}
+
+var _ framework.DeviceMounter = &FakeMounter{}
+
+func NewFakeMounter() *FakeMounter {
+	return &FakeMounter{errors: make(map[Step]error)}
+}
+
+// InjectError Make the step return the error, a nil error clears the injected error.
+func (m *FakeMounter) InjectError(step Step, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err == nil {
+		delete(m.errors, step)
+	} else {
+		m.errors[step] = err
+	}
+}
+
+func (m *FakeMounter) injectedError(step Step) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.errors[step]
+}
+
+func (m *FakeMounter) GetDeviceType() string {
+	return PluginName
+}
+
+func (m *FakeMounter) ValidateMountRequest(_ context.Context, _ kubernetes.Interface,
+	calculator util.CapacityCalculator, node *v1.Node, _ *v1.Pod, _ *api.Container, request map[v1.ResourceName]resource.Quantity,
+	_, _ map[string]string) error {
+
+	quantity, ok := request[ResourceName]
+	if len(request) != 1 || !ok || quantity.Value() <= 0 {
+		msg := fmt.Sprintf("Request for resources error: only %s is supported", ResourceName)
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
+	}
+	if err := util.CheckFreeResourcesInNode(calculator, node, request); err != nil {
+		return api.NewMounterError(api.ResultCode_Insufficient, err.Error())
+	}
+	return nil
+}
+
+func (m *FakeMounter) BuildSupportPodTemplates(_ context.Context, ownerPod *v1.Pod, _ *api.Container,
+	request map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string, _ []*v1.Pod) ([]*v1.Pod, error) {
+	return []*v1.Pod{util.NewDeviceSlavePod(ownerPod, request, annotations, labels)}, nil
+}
+
+func (m *FakeMounter) VerifySupportPodStatus(_ context.Context, slavePod *v1.Pod) (api.StatusCode, error) {
+	switch slavePod.Status.Phase {
+	case v1.PodRunning:
+		return api.Success, nil
+	case v1.PodFailed:
+		return api.Fail, fmt.Errorf("device slave container start failed: %s", slavePod.Status.Message)
+	}
+	for _, condition := range slavePod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Reason == v1.PodReasonUnschedulable {
+			return api.Unschedulable, api.NewMounterError(api.ResultCode_Insufficient, condition.Message)
+		}
+	}
+	return api.Wait, nil
+}
+
+// getSlavePodDeviceInfos Get the fake devices allocated to the slave pods.
+func (m *FakeMounter) getSlavePodDeviceInfos(ctx context.Context, slavePods []*v1.Pod, allow bool) ([]api.DeviceInfo, error) {
+	var deviceInfos []api.DeviceInfo
+	for _, slavePod := range slavePods {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		resources, err := client.GetPodResourcesClinet().GetPodResources(timeoutCtx, slavePod.Name, slavePod.Namespace)
+		cancel()
+		if err != nil {
+			return deviceInfos, err
+		}
+		for _, container := range resources.GetContainers() {
+			for _, dev := range container.GetDevices() {
+				if dev.GetResourceName() != ResourceName {
+					continue
+				}
+				for _, deviceID := range dev.GetDeviceIds() {
+					minor, err := strconv.Atoi(deviceID)
+					if err != nil {
+						return deviceInfos, fmt.Errorf("invalid fake device id %s", deviceID)
+					}
+					deviceInfos = append(deviceInfos, api.DeviceInfo{
+						DeviceID:       deviceID,
+						DeviceFilePath: GetDeviceFilePath(deviceID),
+						Rule: devices.Rule{
+							Type:        devices.CharDevice,
+							Major:       DeviceMajor,
+							Minor:       int64(minor),
+							Permissions: DEFAULT_CGROUP_PERMISSION,
+							Allow:       allow,
+						},
+					})
+				}
+			}
+		}
+	}
+	return deviceInfos, nil
+}
+
+func (m *FakeMounter) GetDeviceInfosToMount(ctx context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
+	if err := m.injectedError(StepGetDeviceInfosToMount); err != nil {
+		return nil, err
+	}
+	return m.getSlavePodDeviceInfos(ctx, slavePods, true)
+}
+
+func (m *FakeMounter) ExecutePostMountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return m.injectedError(StepExecutePostMountActions)
+}
+
+func (m *FakeMounter) GetDeviceInfosToUnmount(ctx context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
+	if err := m.injectedError(StepGetDeviceInfosToUnmount); err != nil {
+		return nil, err
+	}
+	return m.getSlavePodDeviceInfos(ctx, slavePods, false)
+}
+
+// GetDevicesActiveProcessIDs Find the container processes holding the fake device files open.
+func (m *FakeMounter) GetDevicesActiveProcessIDs(_ context.Context, containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error) {
+	if err := m.injectedError(StepGetDevicesActiveProcessIDs); err != nil {
+		return nil, err
+	}
+	deviceFiles := sets.NewString()
+	for _, deviceInfo := range deviceInfos {
+		deviceFiles.Insert(deviceInfo.DeviceFilePath)
+	}
+	return util.GetProcessesOpeningFiles(containerPids, deviceFiles), nil
+}
+
+func (m *FakeMounter) ExecutePostUnmountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return m.injectedError(StepExecutePostUnmountActions)
+}
+
+func (m *FakeMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) []api.ObjectKey {
+
+	podKeys := make([]api.ObjectKey, len(slavePods))
+	for i, slavePod := range slavePods {
+		podKeys[i] = api.ObjectKeyFromObject(slavePod)
+	}
+	return podKeys
+}
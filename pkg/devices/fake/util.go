@@ -0,0 +1,18 @@
+package fake
+
+const (
+	PluginName = "FAKE"
+
+	// ResourceName The extended resource of the fake devices, the device ids are the minor numbers.
+	ResourceName = "device-mounter.io/fake-device"
+
+	// DeviceMajor The major number of the fake devices, in the range reserved for local use.
+	DeviceMajor = 240
+
+	DEFAULT_CGROUP_PERMISSION = "rw"
+)
+
+// GetDeviceFilePath Get the device file path of the fake device.
+func GetDeviceFilePath(deviceID string) string {
+	return "/dev/fake" + deviceID
+}
@@ -0,0 +1,133 @@
+package generic
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ProcessDetection How to find the processes using the devices before they are unmounted.
+type ProcessDetection string
+
+const (
+	// ProcessDetectionNone The devices are always considered idle.
+	ProcessDetectionNone ProcessDetection = "None"
+	// ProcessDetectionOpenFiles Look for the device files in the open files of the container processes under /proc.
+	ProcessDetectionOpenFiles ProcessDetection = "OpenFiles"
+)
+
+func ParseProcessDetection(strategy string) (ProcessDetection, error) {
+	switch p := ProcessDetection(strategy); p {
+	case "":
+		return ProcessDetectionNone, nil
+	case ProcessDetectionNone, ProcessDetectionOpenFiles:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported process detection %q, supported values: %s, %s",
+			strategy, ProcessDetectionNone, ProcessDetectionOpenFiles)
+	}
+}
+
+// Config The configuration of the generic device mounter, usually mounted from a ConfigMap, e.g.
+//
+//	resources:
+//	- resourceName: example.com/fpga
+//	  deviceFilePath: /dev/fpga{{ .DeviceID }}
+//	  permissions: rw
+//	  processDetection: OpenFiles
+type Config struct {
+	Resources []ResourceConfig `yaml:"resources"`
+}
+
+type ResourceConfig struct {
+	// The resource name reported by the device plugin.
+	ResourceName string `yaml:"resourceName"`
+	// The go template of the device file path, rendered with the device id allocated by the device plugin.
+	DeviceFilePath string `yaml:"deviceFilePath"`
+	// The cgroup permissions of the device files, default to rw.
+	Permissions string `yaml:"permissions"`
+	// The process detection strategy, default to None.
+	ProcessDetection string `yaml:"processDetection"`
+
+	pathTemplate *template.Template
+	detection    ProcessDetection
+}
+
+// deviceFileData The data used to render the device file path.
+type deviceFileData struct {
+	DeviceID string
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generic device config: %v", err)
+	}
+	config := &Config{}
+	if err = yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal generic device config: %v", err)
+	}
+	if err = config.complete(); err != nil {
+		return nil, fmt.Errorf("invalid generic device config: %v", err)
+	}
+	return config, nil
+}
+
+// complete Validate the config and fill in the defaults.
+func (c *Config) complete() error {
+	if len(c.Resources) == 0 {
+		return fmt.Errorf("no resources configured")
+	}
+	names := make(map[string]struct{}, len(c.Resources))
+	for i := range c.Resources {
+		resource := &c.Resources[i]
+		if len(resource.ResourceName) == 0 {
+			return fmt.Errorf("resources[%d]: resourceName cannot be empty", i)
+		}
+		if _, ok := names[resource.ResourceName]; ok {
+			return fmt.Errorf("resources[%d]: duplicate resource %s", i, resource.ResourceName)
+		}
+		names[resource.ResourceName] = struct{}{}
+		if len(resource.DeviceFilePath) == 0 {
+			return fmt.Errorf("resources[%d]: deviceFilePath cannot be empty", i)
+		}
+		tmpl, err := template.New(resource.ResourceName).Option("missingkey=error").Parse(resource.DeviceFilePath)
+		if err != nil {
+			return fmt.Errorf("resources[%d]: invalid deviceFilePath: %v", i, err)
+		}
+		resource.pathTemplate = tmpl
+		if len(resource.Permissions) == 0 {
+			resource.Permissions = DEFAULT_CGROUP_PERMISSION
+		}
+		if strings.Trim(resource.Permissions, "rwm") != "" {
+			return fmt.Errorf("resources[%d]: invalid permissions %q", i, resource.Permissions)
+		}
+		if resource.detection, err = ParseProcessDetection(resource.ProcessDetection); err != nil {
+			return fmt.Errorf("resources[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) GetResource(name v1.ResourceName) (*ResourceConfig, bool) {
+	for i := range c.Resources {
+		if c.Resources[i].ResourceName == string(name) {
+			return &c.Resources[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetDeviceFilePath Render the device file path of the device id.
+func (r *ResourceConfig) GetDeviceFilePath(deviceID string) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := r.pathTemplate.Execute(buf, deviceFileData{DeviceID: deviceID}); err != nil {
+		return "", fmt.Errorf("failed to render device file path of %s: %v", deviceID, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,206 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/client"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// GenericMounter Mount the devices of any device plugin resource declared in the config,
+// the device ids allocated to the slave pods are mapped to the device files by the path templates.
+type GenericMounter struct {
+	*Config
+}
+
+func NewGenericMounter() (framework.DeviceMounter, error) {
+	klog.Infoln("Creating GenericMounter")
+	if len(config.GenericDeviceConfigFile) == 0 {
+		return nil, fmt.Errorf("The generic device config is not specified, skip GenericMounter")
+	}
+	cfg, err := LoadConfig(config.GenericDeviceConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	mounter := &GenericMounter{Config: cfg}
+	klog.Infof("Successfully created GenericMounter, Current resources: %v", mounter.resourceNames())
+	return mounter, nil
+}
+
+func (m *GenericMounter) GetDeviceType() string {
+	return PluginName
+}
+
+func (m *GenericMounter) resourceNames() []string {
+	names := make([]string, len(m.Resources))
+	for i, resourceConfig := range m.Resources {
+		names[i] = resourceConfig.ResourceName
+	}
+	return names
+}
+
+func (m *GenericMounter) ValidateMountRequest(_ context.Context, _ kubernetes.Interface,
+	calculator util.CapacityCalculator, node *v1.Node, _ *v1.Pod, _ *api.Container, request map[v1.ResourceName]resource.Quantity,
+	_, _ map[string]string) error {
+
+	if len(request) == 0 {
+		return api.NewMounterError(api.ResultCode_Invalid, "Request for resources error: no resources requested")
+	}
+	for name, quantity := range request {
+		if _, ok := m.GetResource(name); !ok || quantity.IsZero() {
+			msg := fmt.Sprintf("Request for resources error: unsupported resource %s", name)
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
+		}
+	}
+	if err := util.CheckFreeResourcesInNode(calculator, node, request); err != nil {
+		return api.NewMounterError(api.ResultCode_Insufficient, err.Error())
+	}
+	return nil
+}
+
+func (m *GenericMounter) BuildSupportPodTemplates(_ context.Context, ownerPod *v1.Pod, _ *api.Container,
+	request map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string, _ []*v1.Pod) ([]*v1.Pod, error) {
+
+	slavePod := util.NewDeviceSlavePod(ownerPod, request, annotations, labels)
+	slavePod.Spec.PriorityClassName = ownerPod.Spec.PriorityClassName
+	return []*v1.Pod{slavePod}, nil
+}
+
+func (m *GenericMounter) VerifySupportPodStatus(_ context.Context, slavePod *v1.Pod) (api.StatusCode, error) {
+	if slavePod.Status.Phase == v1.PodRunning {
+		return api.Success, nil
+	}
+	if slavePod.Status.Phase == v1.PodFailed {
+		err := fmt.Errorf("device slave container start failed")
+		if len(slavePod.Status.Message) > 0 {
+			err = errors.New(slavePod.Status.Message)
+		}
+		return api.Fail, err
+	}
+	if !(len(slavePod.Status.Conditions) > 0) {
+		return api.Wait, nil
+	}
+	if slavePod.Status.Conditions[0].Reason == v1.PodReasonUnschedulable ||
+		slavePod.Status.Conditions[0].Reason == v1.PodReasonSchedulerError {
+		err := api.NewMounterError(api.ResultCode_Insufficient, slavePod.Status.Conditions[0].Message)
+		return api.Unschedulable, err
+	}
+	return api.Wait, nil
+}
+
+// getSlavePodDeviceInfos Get the devices of the configured resources allocated to the slave pods.
+func (m *GenericMounter) getSlavePodDeviceInfos(ctx context.Context, slavePods []*v1.Pod, allow bool) ([]api.DeviceInfo, error) {
+	var deviceInfos []api.DeviceInfo
+	for _, slavePod := range slavePods {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		resources, err := client.GetPodResourcesClinet().GetPodResources(timeoutCtx, slavePod.Name, slavePod.Namespace)
+		cancel()
+		if err != nil {
+			return deviceInfos, err
+		}
+		for _, container := range resources.GetContainers() {
+			for _, dev := range container.GetDevices() {
+				resourceConfig, ok := m.GetResource(v1.ResourceName(dev.GetResourceName()))
+				if !ok {
+					continue
+				}
+				for _, deviceID := range dev.GetDeviceIds() {
+					deviceInfo, err := newDeviceInfo(resourceConfig, deviceID, allow)
+					if err != nil {
+						return deviceInfos, err
+					}
+					deviceInfos = append(deviceInfos, deviceInfo)
+				}
+			}
+		}
+	}
+	return deviceInfos, nil
+}
+
+func newDeviceInfo(resourceConfig *ResourceConfig, deviceID string, allow bool) (api.DeviceInfo, error) {
+	deviceFilePath, err := resourceConfig.GetDeviceFilePath(deviceID)
+	if err != nil {
+		return api.DeviceInfo{}, err
+	}
+	major, minor, devType, err := util.GetDeviceFileVersionV2(deviceFilePath)
+	if err != nil {
+		return api.DeviceInfo{}, err
+	}
+	return api.DeviceInfo{
+		DeviceID:       deviceID,
+		DeviceFilePath: deviceFilePath,
+		Rule: devices.Rule{
+			Type:        devType,
+			Major:       int64(major),
+			Minor:       int64(minor),
+			Permissions: devices.Permissions(resourceConfig.Permissions),
+			Allow:       allow,
+		},
+	}, nil
+}
+
+func (m *GenericMounter) GetDeviceInfosToMount(ctx context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
+	return m.getSlavePodDeviceInfos(ctx, slavePods, true)
+}
+
+func (m *GenericMounter) ExecutePostMountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return nil
+}
+
+func (m *GenericMounter) GetDeviceInfosToUnmount(ctx context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
+	return m.getSlavePodDeviceInfos(ctx, slavePods, false)
+}
+
+// GetDevicesActiveProcessIDs Find the container processes holding the device files open,
+// only the devices of the resources with the OpenFiles process detection are checked.
+func (m *GenericMounter) GetDevicesActiveProcessIDs(_ context.Context, containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error) {
+	deviceFiles := sets.NewString()
+	for _, deviceInfo := range deviceInfos {
+		if m.detectOpenFiles(deviceInfo) {
+			deviceFiles.Insert(deviceInfo.DeviceFilePath)
+		}
+	}
+	return util.GetProcessesOpeningFiles(containerPids, deviceFiles), nil
+}
+
+// detectOpenFiles Whether the device belongs to a resource with the OpenFiles process detection.
+func (m *GenericMounter) detectOpenFiles(deviceInfo api.DeviceInfo) bool {
+	for i := range m.Resources {
+		resourceConfig := &m.Resources[i]
+		if resourceConfig.detection != ProcessDetectionOpenFiles {
+			continue
+		}
+		if path, err := resourceConfig.GetDeviceFilePath(deviceInfo.DeviceID); err == nil && path == deviceInfo.DeviceFilePath {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *GenericMounter) ExecutePostUnmountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return nil
+}
+
+func (m *GenericMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) []api.ObjectKey {
+
+	podKeys := make([]api.ObjectKey, len(slavePods))
+	for i, slavePod := range slavePods {
+		podKeys[i] = api.ObjectKeyFromObject(slavePod)
+	}
+	return podKeys
+}
@@ -0,0 +1,134 @@
+package generic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const testConfig = `
+resources:
+- resourceName: example.com/fpga
+  deviceFilePath: /dev/fpga{{ .DeviceID }}
+  processDetection: OpenFiles
+- resourceName: example.com/fuse
+  deviceFilePath: /dev/fuse
+  permissions: rwm
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func Test_LoadConfig(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	if !assert.NoError(t, err) {
+		return
+	}
+	fpga, ok := config.GetResource("example.com/fpga")
+	if assert.True(t, ok) {
+		assert.Equal(t, DEFAULT_CGROUP_PERMISSION, fpga.Permissions)
+		assert.Equal(t, ProcessDetectionOpenFiles, fpga.detection)
+		path, err := fpga.GetDeviceFilePath("1")
+		assert.NoError(t, err)
+		assert.Equal(t, "/dev/fpga1", path)
+	}
+	fuse, ok := config.GetResource("example.com/fuse")
+	if assert.True(t, ok) {
+		assert.Equal(t, "rwm", fuse.Permissions)
+		assert.Equal(t, ProcessDetectionNone, fuse.detection)
+	}
+	_, ok = config.GetResource("example.com/unknown")
+	assert.False(t, ok)
+
+	invalidConfigs := map[string]string{
+		"empty":       `resources: []`,
+		"no name":     "resources:\n- deviceFilePath: /dev/fuse",
+		"no path":     "resources:\n- resourceName: example.com/fuse",
+		"duplicate":   "resources:\n- {resourceName: a/b, deviceFilePath: /dev/a}\n- {resourceName: a/b, deviceFilePath: /dev/b}",
+		"template":    "resources:\n- {resourceName: a/b, deviceFilePath: '/dev/{{ .DeviceID'}",
+		"permissions": "resources:\n- {resourceName: a/b, deviceFilePath: /dev/a, permissions: rx}",
+		"detection":   "resources:\n- {resourceName: a/b, deviceFilePath: /dev/a, processDetection: Unknown}",
+	}
+	for name, content := range invalidConfigs {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_ValidateMountRequest(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mounter := &GenericMounter{Config: config}
+	node := &v1.Node{Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+		"example.com/fpga": resource.MustParse("2"),
+	}}}
+
+	testCases := []struct {
+		name    string
+		request map[v1.ResourceName]resource.Quantity
+		code    api.ResultCode
+	}{
+		{name: "ok", request: map[v1.ResourceName]resource.Quantity{"example.com/fpga": resource.MustParse("2")}},
+		{name: "empty", request: nil, code: api.ResultCode_Invalid},
+		{name: "unsupported", request: map[v1.ResourceName]resource.Quantity{"example.com/gpu": resource.MustParse("1")}, code: api.ResultCode_Invalid},
+		{name: "insufficient", request: map[v1.ResourceName]resource.Quantity{"example.com/fpga": resource.MustParse("3")}, code: api.ResultCode_Insufficient},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := mounter.ValidateMountRequest(context.Background(), nil, nil, node, nil, nil, testCase.request, nil, nil)
+			if testCase.code == api.ResultCode_Success {
+				assert.NoError(t, err)
+			} else if assert.IsType(t, &api.MounterError{}, err) {
+				assert.Equal(t, testCase.code, err.(*api.MounterError).Code)
+			}
+		})
+	}
+}
+
+func Test_GetDevicesActiveProcessIDs(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mounter := &GenericMounter{Config: config}
+
+	oldProcRoot := util.ProcRoot
+	util.ProcRoot = t.TempDir()
+	defer func() { util.ProcRoot = oldProcRoot }()
+	openFiles := map[string][]string{
+		"100": {"/dev/null", "/dev/fpga0"},
+		"101": {"/dev/fpga1"},
+		"102": {"/dev/fuse"},
+	}
+	for pid, files := range openFiles {
+		fdDir := filepath.Join(util.ProcRoot, pid, "fd")
+		assert.NoError(t, os.MkdirAll(fdDir, 0755))
+		for i, file := range files {
+			assert.NoError(t, os.Symlink(file, filepath.Join(fdDir, strconv.Itoa(i))))
+		}
+	}
+
+	deviceInfos := []api.DeviceInfo{
+		{DeviceID: "0", DeviceFilePath: "/dev/fpga0"},
+		{DeviceID: "fuse", DeviceFilePath: "/dev/fuse"},
+	}
+	// The process 101 holds another fpga, the fuse device does not detect processes and 103 has exited.
+	pids, err := mounter.GetDevicesActiveProcessIDs(context.Background(), []int{100, 101, 102, 103}, deviceInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100}, pids)
+}
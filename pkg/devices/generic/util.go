@@ -0,0 +1,7 @@
+package generic
+
+const (
+	PluginName = "GENERIC"
+
+	DEFAULT_CGROUP_PERMISSION = "rw"
+)
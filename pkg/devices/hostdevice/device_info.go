@@ -0,0 +1,313 @@
+package hostdevice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// HostDeviceMounter Mount the device files of the host into the containers without slave pods,
+// e.g. /dev/kvm, /dev/fuse or /dev/net/tun. The device paths are the resource names of the request,
+// only the allowed paths can be mounted into the pods of the allowed namespaces.
+type HostDeviceMounter struct {
+	allowedPaths      []string
+	allowedNamespaces sets.String
+}
+
+func NewHostDeviceMounter() (framework.DeviceMounter, error) {
+	klog.Infoln("Creating HostDeviceMounter")
+	mounter, err := newHostDeviceMounter(config.HostDeviceAllowedPaths, config.HostDeviceAllowedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+	klog.Infof("Successfully created HostDeviceMounter, Allowed paths: %v, Allowed namespaces: %v",
+		mounter.allowedPaths, mounter.allowedNamespaces.List())
+	return mounter, nil
+}
+
+func newHostDeviceMounter(allowedPaths, allowedNamespaces []string) (*HostDeviceMounter, error) {
+	if len(allowedPaths) == 0 {
+		return nil, fmt.Errorf("No host device paths are allowed, skip HostDeviceMounter")
+	}
+	if len(allowedNamespaces) == 0 {
+		return nil, fmt.Errorf("No namespaces are allowed to mount host devices, skip HostDeviceMounter")
+	}
+	for _, pattern := range allowedPaths {
+		if _, err := filepath.Match(pattern, ""); err != nil || !filepath.IsAbs(pattern) {
+			return nil, fmt.Errorf("invalid allowed host device path %q", pattern)
+		}
+	}
+	return &HostDeviceMounter{
+		allowedPaths:      allowedPaths,
+		allowedNamespaces: sets.NewString(allowedNamespaces...),
+	}, nil
+}
+
+func (m *HostDeviceMounter) GetDeviceType() string {
+	return PluginName
+}
+
+func (m *HostDeviceMounter) isNamespaceAllowed(namespace string) bool {
+	return m.allowedNamespaces.Has(AllNamespaces) || m.allowedNamespaces.Has(namespace)
+}
+
+func (m *HostDeviceMounter) isPathAllowed(path string) bool {
+	for _, pattern := range m.allowedPaths {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *HostDeviceMounter) ValidateMountRequest(_ context.Context, _ kubernetes.Interface,
+	_ util.CapacityCalculator, _ *v1.Node, pod *v1.Pod, container *api.Container, request map[v1.ResourceName]resource.Quantity,
+	_, _ map[string]string) error {
+
+	if !m.isNamespaceAllowed(pod.Namespace) {
+		msg := fmt.Sprintf("Mounting host devices into the pods of namespace %s is not allowed", pod.Namespace)
+		return api.NewMounterError(api.ResultCode_PermissionDenied, msg)
+	}
+	mounted := sets.NewString(GetMountedDevicePaths(pod, container)...)
+	for name, quantity := range request {
+		path := string(name)
+		if !filepath.IsAbs(path) || filepath.Clean(path) != path || quantity.Value() != 1 {
+			msg := fmt.Sprintf("Request for resources error: expected a clean absolute device path with quantity 1, got %s: %s",
+				path, quantity.String())
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
+		}
+		if !m.isPathAllowed(path) {
+			msg := fmt.Sprintf("Mounting host device %s is not allowed", path)
+			return api.NewMounterError(api.ResultCode_PermissionDenied, msg)
+		}
+		if mounted.Has(path) {
+			msg := fmt.Sprintf("Host device %s has already been mounted into container %s", path, container.Name)
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
+		}
+		if _, _, _, err := util.GetDeviceFileVersionV2(path); err != nil {
+			return api.NewMounterError(api.ResultCode_NotFound, err.Error())
+		}
+	}
+	return nil
+}
+
+// BuildSupportPodTemplates The host devices are mounted without slave pods, it is not called.
+func (m *HostDeviceMounter) BuildSupportPodTemplates(_ context.Context, _ *v1.Pod, _ *api.Container,
+	_ map[v1.ResourceName]resource.Quantity, _, _ map[string]string, _ []*v1.Pod) ([]*v1.Pod, error) {
+	return nil, nil
+}
+
+func (m *HostDeviceMounter) VerifySupportPodStatus(_ context.Context, _ *v1.Pod) (api.StatusCode, error) {
+	return api.Success, nil
+}
+
+func newDeviceInfo(path string, allow bool) (api.DeviceInfo, error) {
+	major, minor, devType, err := util.GetDeviceFileVersionV2(path)
+	if err != nil {
+		return api.DeviceInfo{}, err
+	}
+	return api.DeviceInfo{
+		DeviceFilePath: path,
+		Rule: devices.Rule{
+			Type:        devType,
+			Major:       int64(major),
+			Minor:       int64(minor),
+			Permissions: DEFAULT_CGROUP_PERMISSION,
+			Allow:       allow,
+		},
+	}, nil
+}
+
+func (m *HostDeviceMounter) GetRequestedDeviceInfos(_ context.Context, _ *v1.Pod, _ *api.Container,
+	request map[v1.ResourceName]resource.Quantity) ([]api.DeviceInfo, error) {
+
+	paths := make([]string, 0, len(request))
+	for name := range request {
+		paths = append(paths, string(name))
+	}
+	sort.Strings(paths)
+	var deviceInfos []api.DeviceInfo
+	for _, path := range paths {
+		deviceInfo, err := newDeviceInfo(path, true)
+		if err != nil {
+			return deviceInfos, err
+		}
+		deviceInfos = append(deviceInfos, deviceInfo)
+	}
+	return deviceInfos, nil
+}
+
+// mountedDevices The host devices mounted into a container, recorded in the pod annotation as json.
+type mountedDevices struct {
+	// The devices are lost when the container restarts, the record of another container is stale.
+	ContainerID string   `json:"containerID"`
+	Paths       []string `json:"paths"`
+	// The paths mounted by the requests with request ids.
+	Requests map[string][]string `json:"requests,omitempty"`
+}
+
+// getMountedDevices Read the record of the running container from the annotation of the pod,
+// the stale or corrupted records are ignored.
+func getMountedDevices(pod *v1.Pod, container *api.Container) *mountedDevices {
+	record := &mountedDevices{}
+	if status, ok := util.GetContainerStatus(pod, container.Name); ok {
+		record.ContainerID = status.ContainerID
+	}
+	value := strings.TrimSpace(pod.Annotations[MountedDevicesAnnotationPrefix+container.Name])
+	if len(value) == 0 {
+		return record
+	}
+	recorded := &mountedDevices{}
+	if err := json.Unmarshal([]byte(value), recorded); err != nil {
+		klog.Warningf("Ignore corrupted host devices record of container %s/%s/%s: %v",
+			pod.Namespace, pod.Name, container.Name, err)
+		return record
+	}
+	if recorded.ContainerID != record.ContainerID {
+		klog.V(4).Infof("Ignore host devices record of container %s/%s/%s with stale id %s",
+			pod.Namespace, pod.Name, container.Name, recorded.ContainerID)
+		return record
+	}
+	return recorded
+}
+
+// RecordDevices Record the host devices mounted into the container in the annotation of the pod.
+func (m *HostDeviceMounter) RecordDevices(ctx context.Context, kubeClient kubernetes.Interface,
+	pod *v1.Pod, container *api.Container, requestID string, deviceInfos []api.DeviceInfo, mounted bool) error {
+
+	// The cached pod may not have observed the last record yet.
+	latestPod, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	record := getMountedDevices(latestPod, container)
+	paths := sets.NewString(record.Paths...)
+	changed := sets.NewString()
+	for _, deviceInfo := range deviceInfos {
+		changed.Insert(deviceInfo.DeviceFilePath)
+	}
+	if mounted {
+		paths = paths.Union(changed)
+		if len(requestID) > 0 {
+			if record.Requests == nil {
+				record.Requests = make(map[string][]string)
+			}
+			record.Requests[requestID] = changed.List()
+		}
+	} else {
+		paths = paths.Difference(changed)
+		for id, requestPaths := range record.Requests {
+			if remaining := sets.NewString(requestPaths...).Difference(changed); remaining.Len() > 0 {
+				record.Requests[id] = remaining.List()
+			} else {
+				delete(record.Requests, id)
+			}
+		}
+	}
+	var value any
+	if paths.Len() > 0 {
+		record.Paths = paths.List()
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	// A null value removes the annotation.
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{MountedDevicesAnnotationPrefix + container.Name: value},
+		},
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name,
+		types.MergePatchType, patchData, metav1.PatchOptions{})
+	return err
+}
+
+// GetRequestMountedDeviceInfos Get the host devices mounted by the request with the request id.
+func (m *HostDeviceMounter) GetRequestMountedDeviceInfos(ctx context.Context, kubeClient kubernetes.Interface,
+	pod *v1.Pod, container *api.Container, requestID string) ([]api.DeviceInfo, error) {
+
+	latestPod, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var deviceInfos []api.DeviceInfo
+	for _, path := range getMountedDevices(latestPod, container).Requests[requestID] {
+		deviceInfo, err := newDeviceInfo(path, true)
+		if err != nil {
+			return deviceInfos, err
+		}
+		deviceInfos = append(deviceInfos, deviceInfo)
+	}
+	return deviceInfos, nil
+}
+
+// GetMountedDevicePaths Get the host devices mounted into the running container from the annotation of the pod.
+func GetMountedDevicePaths(pod *v1.Pod, container *api.Container) []string {
+	return getMountedDevices(pod, container).Paths
+}
+
+// GetDeviceInfosToMount The host devices are mounted without slave pods, it is not called.
+func (m *HostDeviceMounter) GetDeviceInfosToMount(_ context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, _ []*v1.Pod) ([]api.DeviceInfo, error) {
+	return nil, nil
+}
+
+func (m *HostDeviceMounter) ExecutePostMountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return nil
+}
+
+// GetDeviceInfosToUnmount Get all host devices mounted into the container,
+// the unmount request with resources only unmounts the devices of the requested paths.
+func (m *HostDeviceMounter) GetDeviceInfosToUnmount(_ context.Context, _ kubernetes.Interface,
+	pod *v1.Pod, container *api.Container, _ []*v1.Pod) ([]api.DeviceInfo, error) {
+
+	var deviceInfos []api.DeviceInfo
+	for _, path := range GetMountedDevicePaths(pod, container) {
+		deviceInfo, err := newDeviceInfo(path, false)
+		if err != nil {
+			return deviceInfos, err
+		}
+		deviceInfos = append(deviceInfos, deviceInfo)
+	}
+	return deviceInfos, nil
+}
+
+// GetDevicesActiveProcessIDs Find the container processes holding the host device files open.
+func (m *HostDeviceMounter) GetDevicesActiveProcessIDs(_ context.Context, containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error) {
+	deviceFiles := sets.NewString()
+	for _, deviceInfo := range deviceInfos {
+		deviceFiles.Insert(deviceInfo.DeviceFilePath)
+	}
+	return util.GetProcessesOpeningFiles(containerPids, deviceFiles), nil
+}
+
+func (m *HostDeviceMounter) ExecutePostUnmountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+	return nil
+}
+
+func (m *HostDeviceMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
+	_ *v1.Pod, _ *api.Container, _ []*v1.Pod) []api.ObjectKey {
+	return nil
+}
@@ -0,0 +1,161 @@
+package hostdevice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_newHostDeviceMounter(t *testing.T) {
+	_, err := newHostDeviceMounter(nil, []string{"default"})
+	assert.Error(t, err)
+	_, err = newHostDeviceMounter([]string{"/dev/null"}, nil)
+	assert.Error(t, err)
+	_, err = newHostDeviceMounter([]string{"dev/null"}, []string{"default"})
+	assert.Error(t, err)
+	_, err = newHostDeviceMounter([]string{"/dev/[null"}, []string{"default"})
+	assert.Error(t, err)
+	_, err = newHostDeviceMounter([]string{"/dev/null", "/dev/tty*"}, []string{AllNamespaces})
+	assert.NoError(t, err)
+}
+
+func Test_ValidateMountRequest(t *testing.T) {
+	mounter, err := newHostDeviceMounter([]string{"/dev/null", "/dev/zero", "/dev/missing*"}, []string{"default"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pod",
+		Namespace:   "default",
+		Annotations: map[string]string{MountedDevicesAnnotationPrefix + "main": `{"containerID":"","paths":["/dev/zero"]}`},
+	}}
+	container := &api.Container{Name: "main"}
+
+	testCases := []struct {
+		name      string
+		namespace string
+		path      string
+		quantity  string
+		code      api.ResultCode
+	}{
+		{name: "ok", path: "/dev/null", quantity: "1"},
+		{name: "namespace not allowed", namespace: "kube-system", path: "/dev/null", quantity: "1", code: api.ResultCode_PermissionDenied},
+		{name: "path not allowed", path: "/dev/kvm", quantity: "1", code: api.ResultCode_PermissionDenied},
+		{name: "path not clean", path: "/dev/../dev/null", quantity: "1", code: api.ResultCode_Invalid},
+		{name: "quantity", path: "/dev/null", quantity: "2", code: api.ResultCode_Invalid},
+		{name: "already mounted", path: "/dev/zero", quantity: "1", code: api.ResultCode_Invalid},
+		{name: "not found", path: "/dev/missing0", quantity: "1", code: api.ResultCode_NotFound},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			targetPod := pod.DeepCopy()
+			if len(testCase.namespace) > 0 {
+				targetPod.Namespace = testCase.namespace
+			}
+			request := map[v1.ResourceName]resource.Quantity{
+				v1.ResourceName(testCase.path): resource.MustParse(testCase.quantity),
+			}
+			err := mounter.ValidateMountRequest(context.Background(), nil, nil, nil, targetPod, container, request, nil, nil)
+			if testCase.code == api.ResultCode_Success {
+				assert.NoError(t, err)
+			} else if assert.IsType(t, &api.MounterError{}, err) {
+				assert.Equal(t, testCase.code, err.(*api.MounterError).Code)
+			}
+		})
+	}
+}
+
+func Test_GetDeviceInfos(t *testing.T) {
+	mounter, err := newHostDeviceMounter([]string{"/dev/*"}, []string{AllNamespaces})
+	if !assert.NoError(t, err) {
+		return
+	}
+	// /dev/null is the char device 1:3 on linux.
+	nullDevice := api.DeviceInfo{
+		DeviceFilePath: "/dev/null",
+		Rule:           devices.Rule{Type: devices.CharDevice, Major: 1, Minor: 3, Permissions: DEFAULT_CGROUP_PERMISSION, Allow: true},
+	}
+	deviceInfos, err := mounter.GetRequestedDeviceInfos(context.Background(), nil, nil,
+		map[v1.ResourceName]resource.Quantity{"/dev/null": resource.MustParse("1")})
+	assert.NoError(t, err)
+	assert.Equal(t, []api.DeviceInfo{nullDevice}, deviceInfos)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{MountedDevicesAnnotationPrefix + "main": `{"containerID":"containerd://1","paths":["/dev/null"]}`},
+		},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "main", ContainerID: "containerd://1"}}},
+	}
+	deviceInfos, err = mounter.GetDeviceInfosToUnmount(context.Background(), nil, pod, &api.Container{Name: "main"}, nil)
+	assert.NoError(t, err)
+	nullDevice.Allow = false
+	assert.Equal(t, []api.DeviceInfo{nullDevice}, deviceInfos)
+
+	deviceInfos, err = mounter.GetDeviceInfosToUnmount(context.Background(), nil, pod, &api.Container{Name: "sidecar"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, deviceInfos)
+
+	// The devices recorded for the container before its restart are gone.
+	pod.Status.ContainerStatuses[0].ContainerID = "containerd://2"
+	deviceInfos, err = mounter.GetDeviceInfosToUnmount(context.Background(), nil, pod, &api.Container{Name: "main"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, deviceInfos)
+}
+
+func Test_RecordDevices(t *testing.T) {
+	mounter, err := newHostDeviceMounter([]string{"/dev/*"}, []string{AllNamespaces})
+	require.NoError(t, err)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "default",
+			// The record of the container before its restart.
+			Annotations: map[string]string{MountedDevicesAnnotationPrefix + "main": `{"containerID":"containerd://1","paths":["/dev/zero"]}`},
+		},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "main", ContainerID: "containerd://2"}}},
+	}
+	kubeClient := fake.NewSimpleClientset(pod)
+	ctx := context.Background()
+	container := &api.Container{Name: "main"}
+	getPod := func() *v1.Pod {
+		latestPod, err := kubeClient.CoreV1().Pods("default").Get(ctx, "pod", metav1.GetOptions{})
+		require.NoError(t, err)
+		return latestPod
+	}
+	newDeviceInfos := func(paths ...string) []api.DeviceInfo {
+		var deviceInfos []api.DeviceInfo
+		for _, path := range paths {
+			deviceInfo, err := newDeviceInfo(path, true)
+			require.NoError(t, err)
+			deviceInfos = append(deviceInfos, deviceInfo)
+		}
+		return deviceInfos
+	}
+
+	require.NoError(t, mounter.RecordDevices(ctx, kubeClient, pod, container, "req-1", newDeviceInfos("/dev/null"), true))
+	require.NoError(t, mounter.RecordDevices(ctx, kubeClient, pod, container, "", newDeviceInfos("/dev/zero"), true))
+	assert.Equal(t, []string{"/dev/null", "/dev/zero"}, GetMountedDevicePaths(getPod(), container))
+	assert.JSONEq(t, `{"containerID":"containerd://2","paths":["/dev/null","/dev/zero"],"requests":{"req-1":["/dev/null"]}}`,
+		getPod().Annotations[MountedDevicesAnnotationPrefix+"main"])
+
+	deviceInfos, err := mounter.GetRequestMountedDeviceInfos(ctx, kubeClient, pod, container, "req-1")
+	assert.NoError(t, err)
+	assert.Equal(t, newDeviceInfos("/dev/null"), deviceInfos)
+	deviceInfos, err = mounter.GetRequestMountedDeviceInfos(ctx, kubeClient, pod, container, "req-2")
+	assert.NoError(t, err)
+	assert.Empty(t, deviceInfos)
+
+	require.NoError(t, mounter.RecordDevices(ctx, kubeClient, pod, container, "", newDeviceInfos("/dev/null"), false))
+	assert.JSONEq(t, `{"containerID":"containerd://2","paths":["/dev/zero"]}`,
+		getPod().Annotations[MountedDevicesAnnotationPrefix+"main"])
+	require.NoError(t, mounter.RecordDevices(ctx, kubeClient, pod, container, "", newDeviceInfos("/dev/zero"), false))
+	assert.NotContains(t, getPod().Annotations, MountedDevicesAnnotationPrefix+"main")
+}
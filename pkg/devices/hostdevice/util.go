@@ -0,0 +1,18 @@
+package hostdevice
+
+import (
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+)
+
+const (
+	PluginName = "HOST_DEVICE"
+
+	DEFAULT_CGROUP_PERMISSION = "rw"
+
+	// MountedDevicesAnnotationPrefix The prefix of the pod annotation recording the host devices mounted to a container,
+	// the annotation key is suffixed with the container name.
+	MountedDevicesAnnotationPrefix = "host-device." + v1alpha1.Group + "/"
+
+	// AllNamespaces Allow the host devices to be mounted into the pods of all namespaces.
+	AllNamespaces = "*"
+)
@@ -0,0 +1,77 @@
+package fakenvml
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+)
+
+// GPU The fake gpu reported by the fake NVML library.
+type GPU struct {
+	UUID  string
+	Minor int
+	// The pids of the compute processes running on the gpu.
+	ComputeProcesses []uint32
+	// The pids of the graphics processes running on the gpu.
+	GraphicsProcesses []uint32
+}
+
+// New Create a fake NVML library reporting the gpus in order of their indexes, used to test the nvidia
+// device mounters without gpus. The functions of the returned mock can be replaced to inject errors, e.g. InitFunc.
+func New(gpus ...GPU) *mock.Interface {
+	devices := make([]*mock.Device, len(gpus))
+	for i := range gpus {
+		devices[i] = newDevice(gpus[i])
+	}
+	return &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ErrorStringFunc: func(r nvml.Return) string {
+			return r.Error()
+		},
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return len(devices), nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(index int) (nvml.Device, nvml.Return) {
+			if index < 0 || index >= len(devices) {
+				return nil, nvml.ERROR_INVALID_ARGUMENT
+			}
+			return devices[index], nvml.SUCCESS
+		},
+		DeviceGetHandleByUUIDFunc: func(uuid string) (nvml.Device, nvml.Return) {
+			for i, gpu := range gpus {
+				if gpu.UUID == uuid {
+					return devices[i], nvml.SUCCESS
+				}
+			}
+			return nil, nvml.ERROR_NOT_FOUND
+		},
+	}
+}
+
+func newDevice(gpu GPU) *mock.Device {
+	processInfos := func(pids []uint32) []nvml.ProcessInfo {
+		infos := make([]nvml.ProcessInfo, len(pids))
+		for i, pid := range pids {
+			infos[i] = nvml.ProcessInfo{Pid: pid}
+		}
+		return infos
+	}
+	return &mock.Device{
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return gpu.UUID, nvml.SUCCESS
+		},
+		GetMinorNumberFunc: func() (int, nvml.Return) {
+			return gpu.Minor, nvml.SUCCESS
+		},
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return processInfos(gpu.ComputeProcesses), nvml.SUCCESS
+		},
+		GetGraphicsRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return processInfos(gpu.GraphicsProcesses), nvml.SUCCESS
+		},
+	}
+}
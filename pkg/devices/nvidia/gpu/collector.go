@@ -11,16 +11,16 @@ import (
 	"github.com/coldzerofear/device-mounter/pkg/client"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/klog/v2"
-	"k8s.io/kubelet/pkg/apis/podresources/v1alpha1"
 )
 
 type GPUCollector struct {
 	sync.Mutex
 	GPUList []*NvidiaGPU
+	nvmllib nvml.Interface
 }
 
-func NewGPUCollector() (*GPUCollector, error) {
-	gpuCollector := &GPUCollector{}
+func NewGPUCollector(nvmllib nvml.Interface) (*GPUCollector, error) {
+	gpuCollector := &GPUCollector{nvmllib: nvmllib}
 	if err := gpuCollector.initGPUInfo(); err != nil {
 		klog.Errorf("Failed to init gpu info: %v", err)
 		return nil, err
@@ -36,31 +36,32 @@ func NewGPUCollector() (*GPUCollector, error) {
 
 func (gpuCollector *GPUCollector) initGPUInfo() error {
 	klog.V(4).Infoln("init gpu info")
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		return fmt.Errorf("nvml Init error: %s", nvml.ErrorString(rt))
+	nvmllib := gpuCollector.nvmllib
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		return fmt.Errorf("nvml Init error: %s", nvmllib.ErrorString(rt))
 	}
-	defer nvml.Shutdown()
+	defer nvmllib.Shutdown()
 
-	num, rt := nvml.DeviceGetCount()
+	num, rt := nvmllib.DeviceGetCount()
 	if rt != nvml.SUCCESS {
 
-		return fmt.Errorf("nvml DeviceGetCount error: %s", nvml.ErrorString(rt))
+		return fmt.Errorf("nvml DeviceGetCount error: %s", nvmllib.ErrorString(rt))
 	} else {
 		klog.V(4).Infoln("GPU Num: ", num)
 	}
 
 	for i := 0; i < num; i++ {
-		dev, rt := nvml.DeviceGetHandleByIndex(i)
+		dev, rt := nvmllib.DeviceGetHandleByIndex(i)
 		if rt != nvml.SUCCESS {
-			return fmt.Errorf("nvml DeviceGetHandleByIndex error: %s", nvml.ErrorString(rt))
+			return fmt.Errorf("nvml DeviceGetHandleByIndex error: %s", nvmllib.ErrorString(rt))
 		}
 		minorNum, rt := dev.GetMinorNumber()
 		if rt != nvml.SUCCESS {
-			return fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvml.ErrorString(rt))
+			return fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvmllib.ErrorString(rt))
 		}
 		uuid, rt := dev.GetUUID()
 		if rt != nvml.SUCCESS {
-			return fmt.Errorf("nvml DeviceGetUUID error: %s", nvml.ErrorString(rt))
+			return fmt.Errorf("nvml DeviceGetUUID error: %s", nvmllib.ErrorString(rt))
 		}
 		gpuDev := New(minorNum, uuid)
 		gpuCollector.GPUList = append(gpuCollector.GPUList, gpuDev)
@@ -69,29 +70,39 @@ func (gpuCollector *GPUCollector) initGPUInfo() error {
 }
 
 func (gpuCollector *GPUCollector) GetPodGPUResources(podName, podNamespace string) ([]*NvidiaGPU, error) {
-	if err := gpuCollector.UpdateGPUStatus(); err != nil {
-		klog.Errorln("Failed to update gpu status")
-		return nil, err
-	}
-	var gpuResources []*NvidiaGPU
-	for _, gpuDev := range gpuCollector.GPUList {
-		if gpuDev.PodName == podName && gpuDev.PodNamespace == podNamespace {
-			gpuResources = append(gpuResources, gpuDev)
-		}
-	}
-	return gpuResources, nil
+	return gpuCollector.getPodGPUResources(podName, podNamespace, func(string) bool { return true })
 }
 
 func (gpuCollector *GPUCollector) GetContainerGPUResources(podName, podNamespace, containerName string) ([]*NvidiaGPU, error) {
-	if err := gpuCollector.UpdateGPUStatus(); err != nil {
-		klog.Errorln("Failed to update gpu status")
+	return gpuCollector.getPodGPUResources(podName, podNamespace, func(name string) bool { return name == containerName })
+}
+
+// getPodGPUResources Get the gpus allocated to the containers of the pod from the pod resources of the single pod.
+func (gpuCollector *GPUCollector) getPodGPUResources(podName, podNamespace string, matchContainer func(string) bool) ([]*NvidiaGPU, error) {
+	gpuCollector.Lock()
+	defer gpuCollector.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resources, err := client.GetPodResourcesClinet().GetPodResources(ctx, podName, podNamespace)
+	if err != nil {
+		klog.Errorln("Failed to get pod resources", "pod", podNamespace+"/"+podName, err)
 		return nil, err
 	}
 	var gpuResources []*NvidiaGPU
-	for _, gpuDev := range gpuCollector.GPUList {
-		if gpuDev.PodName == podName && gpuDev.PodNamespace == podNamespace &&
-			gpuDev.ContainerName == containerName {
-			gpuResources = append(gpuResources, gpuDev)
+	for _, container := range resources.GetContainers() {
+		if !matchContainer(container.GetName()) {
+			continue
+		}
+		for _, dev := range container.GetDevices() {
+			if dev.GetResourceName() != ResourceName {
+				continue
+			}
+			for _, uuid := range dev.GetDeviceIds() {
+				if nvidiaGPU := gpuCollector.allocateGPU(uuid, podName, podNamespace, container.GetName()); nvidiaGPU != nil {
+					gpuResources = append(gpuResources, nvidiaGPU)
+				}
+			}
 		}
 	}
 	return gpuResources, nil
@@ -103,11 +114,10 @@ func (gpuCollector *GPUCollector) UpdateGPUStatus() error {
 
 	klog.V(4).Infoln("Updating GPU status")
 
-	resClient := client.GetPodResourcesClinet().GetClient()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := resClient.List(ctx, &v1alpha1.ListPodResourcesRequest{})
+	podResources, err := client.GetPodResourcesClinet().ListPodResources(ctx)
 	if err != nil {
 		return err
 	}
@@ -115,7 +125,7 @@ func (gpuCollector *GPUCollector) UpdateGPUStatus() error {
 	gpuCollector.resetGPUStatus()
 
 	// 搜索哪些pod分配到了哪些gpu设备
-	for _, pod := range resp.GetPodResources() {
+	for _, pod := range podResources {
 		for _, container := range pod.GetContainers() {
 			for _, dev := range container.GetDevices() {
 				if dev.GetResourceName() != ResourceName {
@@ -123,30 +133,7 @@ func (gpuCollector *GPUCollector) UpdateGPUStatus() error {
 				}
 
 				for _, uuid := range dev.GetDeviceIds() { // nvidia-device-plugin 上报的是gpu的uuid
-					if nvidiaGPU, err := gpuCollector.GetGPUByUUID(uuid); err != nil {
-						klog.V(4).Infoln(err.Error())
-						// TODO 发现新的设备
-						minor, err := SearchGPUMinorByUUID(uuid)
-						if err != nil {
-							klog.Errorf(err.Error())
-							continue
-						}
-						newGPU := New(minor, uuid)
-						newGPU.State = GPU_ALLOCATED_STATE
-						newGPU.PodName = pod.Name
-						newGPU.PodNamespace = pod.Namespace
-						newGPU.ContainerName = container.Name
-						gpuCollector.GPUList = append(gpuCollector.GPUList, newGPU)
-					} else {
-						// 更新 gpu 信息
-						nvidiaGPU.State = GPU_ALLOCATED_STATE
-						nvidiaGPU.PodName = pod.Name
-						nvidiaGPU.PodNamespace = pod.Namespace
-						nvidiaGPU.ContainerName = container.Name
-						klog.V(4).InfoS("GPU allocated", "ID", nvidiaGPU.UUID,
-							"Device", nvidiaGPU.DeviceFilePath, "PodName", pod.Name, "Namespace",
-							pod.Namespace, "ContainerName", container.Name)
-					}
+					gpuCollector.allocateGPU(uuid, pod.GetName(), pod.GetNamespace(), container.GetName())
 				}
 			}
 		}
@@ -155,19 +142,44 @@ func (gpuCollector *GPUCollector) UpdateGPUStatus() error {
 	return nil
 }
 
-func SearchGPUMinorByUUID(uuid string) (int, error) {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
+// allocateGPU Record the container that the gpu is allocated to, nil is returned if the gpu is not found.
+func (gpuCollector *GPUCollector) allocateGPU(uuid, podName, podNamespace, containerName string) *NvidiaGPU {
+	nvidiaGPU, err := gpuCollector.GetGPUByUUID(uuid)
+	if err != nil {
+		klog.V(4).Infoln(err.Error())
+		// TODO 发现新的设备
+		minor, err := SearchGPUMinorByUUID(gpuCollector.nvmllib, uuid)
+		if err != nil {
+			klog.Errorf(err.Error())
+			return nil
+		}
+		nvidiaGPU = New(minor, uuid)
+		gpuCollector.GPUList = append(gpuCollector.GPUList, nvidiaGPU)
+	}
+	// 更新 gpu 信息
+	nvidiaGPU.State = GPU_ALLOCATED_STATE
+	nvidiaGPU.PodName = podName
+	nvidiaGPU.PodNamespace = podNamespace
+	nvidiaGPU.ContainerName = containerName
+	klog.V(4).InfoS("GPU allocated", "ID", nvidiaGPU.UUID,
+		"Device", nvidiaGPU.DeviceFilePath, "PodName", podName, "Namespace",
+		podNamespace, "ContainerName", containerName)
+	return nvidiaGPU
+}
+
+func SearchGPUMinorByUUID(nvmllib nvml.Interface, uuid string) (int, error) {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
 
-		return 0, fmt.Errorf("nvml Init error: %s", nvml.ErrorString(rt))
+		return 0, fmt.Errorf("nvml Init error: %s", nvmllib.ErrorString(rt))
 	}
-	defer nvml.Shutdown()
-	handle, rt := nvml.DeviceGetHandleByUUID(uuid)
+	defer nvmllib.Shutdown()
+	handle, rt := nvmllib.DeviceGetHandleByUUID(uuid)
 	if rt != nvml.SUCCESS {
-		return 0, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvml.ErrorString(rt))
+		return 0, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvmllib.ErrorString(rt))
 	}
 	number, rt := handle.GetMinorNumber()
 	if rt != nvml.SUCCESS {
-		return 0, fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvml.ErrorString(rt))
+		return 0, fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvmllib.ErrorString(rt))
 	}
 	return number, nil
 }
@@ -232,22 +244,22 @@ func (gpu *NvidiaGPU) ResetState() {
 	gpu.State = GPU_FREE_STATE
 }
 
-func (gpu *NvidiaGPU) GetRunningProcess() ([]nvml.ProcessInfo, error) {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		return nil, fmt.Errorf("nvml Init error: %s", nvml.ErrorString(rt))
+func (gpu *NvidiaGPU) GetRunningProcess(nvmllib nvml.Interface) ([]nvml.ProcessInfo, error) {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		return nil, fmt.Errorf("nvml Init error: %s", nvmllib.ErrorString(rt))
 	}
-	defer nvml.Shutdown()
-	handle, rt := nvml.DeviceGetHandleByUUID(gpu.UUID)
+	defer nvmllib.Shutdown()
+	handle, rt := nvmllib.DeviceGetHandleByUUID(gpu.UUID)
 	if rt != nvml.SUCCESS {
-		return nil, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvml.ErrorString(rt))
+		return nil, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvmllib.ErrorString(rt))
 	}
 	graphicsProcesses, rt := handle.GetGraphicsRunningProcesses()
 	if rt != nvml.SUCCESS {
-		return nil, fmt.Errorf("nvml DeviceGetGraphicsRunningProcesses error: %s", nvml.ErrorString(rt))
+		return nil, fmt.Errorf("nvml DeviceGetGraphicsRunningProcesses error: %s", nvmllib.ErrorString(rt))
 	}
 	computeProcesses, rt := handle.GetComputeRunningProcesses()
 	if rt != nvml.SUCCESS {
-		return nil, fmt.Errorf("nvml DeviceGetComputeRunningProcesses error: %s", nvml.ErrorString(rt))
+		return nil, fmt.Errorf("nvml DeviceGetComputeRunningProcesses error: %s", nvmllib.ErrorString(rt))
 	}
 	return append(graphicsProcesses, computeProcesses...), nil
 }
@@ -0,0 +1,91 @@
+package gpu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/devices/nvidia/fakenvml"
+	"github.com/coldzerofear/device-mounter/pkg/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+var testGPUs = []fakenvml.GPU{
+	{UUID: "GPU-0", Minor: 0, ComputeProcesses: []uint32{100}},
+	{UUID: "GPU-1", Minor: 1, GraphicsProcesses: []uint32{200}, ComputeProcesses: []uint32{300}},
+	{UUID: "GPU-2", Minor: 3},
+}
+
+func Test_GPUCollector(t *testing.T) {
+	// The simulated node serves the PodResources API for the collector.
+	simulator.NewNode(t, "node", map[v1.ResourceName]int{ResourceName: 0})
+	collector, err := NewGPUCollector(fakenvml.New(testGPUs...))
+	require.NoError(t, err)
+
+	require.Len(t, collector.GPUList, 3)
+	for i, gpu := range collector.GPUList {
+		assert.Equal(t, testGPUs[i].UUID, gpu.UUID)
+		assert.Equal(t, testGPUs[i].Minor, gpu.MinorNumber)
+		assert.Equal(t, GPU_FREE_STATE, gpu.State)
+	}
+	assert.Equal(t, "/dev/nvidia3", collector.GPUList[2].DeviceFilePath)
+
+	gpu, err := collector.GetGPUByUUID("GPU-1")
+	require.NoError(t, err)
+	processes, err := gpu.GetRunningProcess(collector.nvmllib)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []nvml.ProcessInfo{{Pid: 200}, {Pid: 300}}, processes)
+	_, err = collector.GetGPUByUUID("GPU-9")
+	assert.Error(t, err)
+
+	// The gpu missing from the list is looked up by its uuid when allocated.
+	collector.GPUList = collector.GPUList[:2]
+	gpu = collector.allocateGPU("GPU-2", "pod", "default", "main")
+	require.NotNil(t, gpu)
+	assert.Equal(t, 3, gpu.MinorNumber)
+	assert.Equal(t, GPU_ALLOCATED_STATE, gpu.State)
+	assert.Len(t, collector.GPUList, 3)
+	assert.Nil(t, collector.allocateGPU("GPU-9", "pod", "default", "main"))
+
+	// The allocations are reset by the pod resources.
+	assert.NoError(t, collector.UpdateGPUStatus())
+	assert.Equal(t, GPU_FREE_STATE, gpu.State)
+}
+
+func Test_NewGPUCollectorError(t *testing.T) {
+	nvmllib := fakenvml.New(testGPUs...)
+	nvmllib.InitFunc = func() nvml.Return { return nvml.ERROR_LIBRARY_NOT_FOUND }
+	assert.False(t, checkDeviceEnvironment(nvmllib))
+	_, err := NewGPUCollector(nvmllib)
+	assert.Error(t, err)
+	_, err = newNvidiaGPUMounter(nvmllib)
+	assert.Error(t, err)
+}
+
+func Test_SearchGPUMinorByUUID(t *testing.T) {
+	nvmllib := fakenvml.New(testGPUs...)
+	minor, err := SearchGPUMinorByUUID(nvmllib, "GPU-2")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, minor)
+	_, err = SearchGPUMinorByUUID(nvmllib, "GPU-9")
+	assert.Error(t, err)
+}
+
+func Test_GetDevicesActiveProcessIDs(t *testing.T) {
+	simulator.NewNode(t, "node", map[v1.ResourceName]int{ResourceName: 0})
+	mounter, err := newNvidiaGPUMounter(fakenvml.New(testGPUs...))
+	require.NoError(t, err)
+
+	deviceInfos := []api.DeviceInfo{{DeviceID: "GPU-0"}, {DeviceID: "GPU-1"}, {DeviceID: ""}}
+	// Only the processes of the container are returned, 300 belongs to another container.
+	pids, err := mounter.GetDevicesActiveProcessIDs(context.Background(), []int{100, 200, 400}, deviceInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 200}, pids)
+
+	pids, err = mounter.GetDevicesActiveProcessIDs(context.Background(), []int{100, 200}, []api.DeviceInfo{{DeviceID: "GPU-2"}})
+	assert.NoError(t, err)
+	assert.Empty(t, pids)
+}
@@ -2,6 +2,7 @@ package gpu
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -21,11 +22,19 @@ type NvidiaGPUMounter struct {
 }
 
 func NewNvidiaGPUMounter() (framework.DeviceMounter, error) {
+	mounter, err := newNvidiaGPUMounter(nvml.New())
+	if err != nil {
+		return nil, err
+	}
+	return mounter, nil
+}
+
+func newNvidiaGPUMounter(nvmllib nvml.Interface) (*NvidiaGPUMounter, error) {
 	klog.Infoln("Creating NvidiaGPUMounter")
-	if !checkDeviceEnvironment() {
+	if !checkDeviceEnvironment(nvmllib) {
 		return nil, fmt.Errorf("The current node environment does not have the operating conditions for NvidiaGPUMounter")
 	}
-	collector, err := NewGPUCollector()
+	collector, err := NewGPUCollector(nvmllib)
 	if err != nil {
 		return nil, err
 	}
@@ -38,28 +47,28 @@ func (m *NvidiaGPUMounter) GetDeviceType() string {
 	return PluginName
 }
 
-func checkDeviceEnvironment() bool {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		klog.Infof("Failed to initialize NVML: %s.", nvml.ErrorString(rt))
+func checkDeviceEnvironment(nvmllib nvml.Interface) bool {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		klog.Infof("Failed to initialize NVML: %s.", nvmllib.ErrorString(rt))
 		klog.Infof("If this is a GPU node, did you set the docker default runtime to `nvidia`?")
 		klog.Infof("You can check the prerequisites at: https://github.com/NVIDIA/k8s-device-plugin#prerequisites")
 		klog.Infof("You can learn how to set the runtime at: https://github.com/NVIDIA/k8s-device-plugin#quick-start")
 		klog.Infof("If this is not a GPU node, you should set up a toleration or nodeSelector to only deploy this plugin on GPU nodes")
 		return false
 	}
-	defer nvml.Shutdown()
+	defer nvmllib.Shutdown()
 	return true
 }
 
-func (m *NvidiaGPUMounter) ValidateMountRequest(_ context.Context, _ *kubernetes.Clientset,
-	node *v1.Node, _ *v1.Pod, _ *api.Container, request map[v1.ResourceName]resource.Quantity,
+func (m *NvidiaGPUMounter) ValidateMountRequest(_ context.Context, _ kubernetes.Interface,
+	calculator util.CapacityCalculator, node *v1.Node, _ *v1.Pod, _ *api.Container, request map[v1.ResourceName]resource.Quantity,
 	_, _ map[string]string) error {
 
 	if !util.CheckResourcesInSlice(request, []string{ResourceName}, nil) {
-		return api.NewMounterError(api.ResultCode_Fail, "Request for resources error")
+		return api.NewMounterError(api.ResultCode_Invalid, "Request for resources error")
 	}
-	if !util.CheckResourcesInNode(node, request) {
-		return api.NewMounterError(api.ResultCode_Insufficient, "Insufficient node resources")
+	if err := util.CheckFreeResourcesInNode(calculator, node, request); err != nil {
+		return api.NewMounterError(api.ResultCode_Insufficient, err.Error())
 	}
 	return nil
 }
@@ -93,7 +102,7 @@ func (m *NvidiaGPUMounter) VerifySupportPodStatus(_ context.Context, slavePod *v
 	if slavePod.Status.Phase == v1.PodFailed {
 		err := fmt.Errorf("device slave container start failed")
 		if len(slavePod.Status.Message) > 0 {
-			err = fmt.Errorf(slavePod.Status.Message)
+			err = errors.New(slavePod.Status.Message)
 		}
 		return api.Fail, err
 	}
@@ -108,7 +117,7 @@ func (m *NvidiaGPUMounter) VerifySupportPodStatus(_ context.Context, slavePod *v
 	return api.Wait, nil
 }
 
-func (m *NvidiaGPUMounter) GetDeviceInfosToMount(_ context.Context, _ *kubernetes.Clientset, ownerPod *v1.Pod,
+func (m *NvidiaGPUMounter) GetDeviceInfosToMount(_ context.Context, _ kubernetes.Interface, ownerPod *v1.Pod,
 	container *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 
 	var deviceInfos []api.DeviceInfo
@@ -182,11 +191,11 @@ func (m *NvidiaGPUMounter) GetDeviceInfosToMount(_ context.Context, _ *kubernete
 	return deviceInfos, nil
 }
 
-func (m *NvidiaGPUMounter) ExecutePostMountActions(_ context.Context, _ *kubernetes.Clientset, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+func (m *NvidiaGPUMounter) ExecutePostMountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
 	return nil
 }
 
-func (m *NvidiaGPUMounter) GetDeviceInfosToUnmount(_ context.Context, _ *kubernetes.Clientset, _ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
+func (m *NvidiaGPUMounter) GetDeviceInfosToUnmount(_ context.Context, _ kubernetes.Interface, _ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 	var deviceInfos []api.DeviceInfo
 	var gpus []*NvidiaGPU
 	for _, slavePod := range slavePods {
@@ -225,7 +234,7 @@ func (m *NvidiaGPUMounter) GetDevicesActiveProcessIDs(_ context.Context, contain
 			if gpuInfo.DeviceID != gpu.UUID {
 				continue
 			}
-			procs, err := gpu.GetRunningProcess()
+			procs, err := gpu.GetRunningProcess(m.nvmllib)
 			if err != nil {
 				break
 			}
@@ -244,11 +253,11 @@ func (m *NvidiaGPUMounter) GetDevicesActiveProcessIDs(_ context.Context, contain
 }
 
 // 卸载设备成功前的后续动作
-func (m *NvidiaGPUMounter) ExecutePostUnmountActions(_ context.Context, _ *kubernetes.Clientset, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
+func (m *NvidiaGPUMounter) ExecutePostUnmountActions(_ context.Context, _ kubernetes.Interface, _ util.Config, _ *v1.Pod, _ *api.Container, _ []*v1.Pod) error {
 	return nil
 }
 
-func (m *NvidiaGPUMounter) GetPodsToCleanup(_ context.Context, _ *kubernetes.Clientset,
+func (m *NvidiaGPUMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
 	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) []api.ObjectKey {
 
 	objKeys := make([]api.ObjectKey, len(slavePods))
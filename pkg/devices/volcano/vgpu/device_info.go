@@ -2,6 +2,7 @@ package vgpu
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -21,14 +22,17 @@ import (
 	"k8s.io/klog/v2"
 )
 
-type VolcanoVGPUMounter struct{}
+type VolcanoVGPUMounter struct {
+	nvmllib nvml.Interface
+}
 
 func NewVolcanoVGPUMounter() (framework.DeviceMounter, error) {
 	klog.Infoln("Creating VolcanoVGPUMounter")
-	if !checkDeviceEnvironment() {
+	nvmllib := nvml.New()
+	if !checkDeviceEnvironment(nvmllib) {
 		return nil, fmt.Errorf("The current node environment does not have the operating conditions for VolcanoVGPUMounter")
 	}
-	mounter := &VolcanoVGPUMounter{}
+	mounter := &VolcanoVGPUMounter{nvmllib: nvmllib}
 	klog.Infoln("Successfully created VolcanoVGPUMounter")
 	return mounter, nil
 }
@@ -39,32 +43,32 @@ func (m *VolcanoVGPUMounter) GetDeviceType() string {
 }
 
 // 检查节点设备环境 如环境不允许则不启动挂载器
-func checkDeviceEnvironment() bool {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		klog.Infof("Failed to initialize NVML: %s.", nvml.ErrorString(rt))
+func checkDeviceEnvironment(nvmllib nvml.Interface) bool {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		klog.Infof("Failed to initialize NVML: %s.", nvmllib.ErrorString(rt))
 		klog.Infof("If this is a GPU node, did you set the docker default runtime to `nvidia`?")
 		klog.Infof("You can check the prerequisites at: https://github.com/NVIDIA/k8s-device-plugin#prerequisites")
 		klog.Infof("You can learn how to set the runtime at: https://github.com/NVIDIA/k8s-device-plugin#quick-start")
 		klog.Infof("If this is not a GPU node, you should set up a toleration or nodeSelector to only deploy this plugin on GPU nodes")
 		return false
 	}
-	defer nvml.Shutdown()
+	defer nvmllib.Shutdown()
 	return true
 }
 
 // 校验挂载资源时的 请求参数 和 节点资源
 func (m *VolcanoVGPUMounter) ValidateMountRequest(_ context.Context,
-	_ *kubernetes.Clientset, node *v1.Node, ownerPod *v1.Pod, container *api.Container,
+	_ kubernetes.Interface, calculator util.CapacityCalculator, node *v1.Node, ownerPod *v1.Pod, container *api.Container,
 	request map[v1.ResourceName]resource.Quantity, annotations, _ map[string]string) error {
 
 	if !util.CheckResourcesInSlice(request, []string{VolcanoVGPUNumber},
 		[]string{VolcanoVGPUMemory, VolcanoVGPUCores, VolcanoVGPUMemoryPercentage}) {
-		return api.NewMounterError(api.ResultCode_Fail, "Request for resources error")
+		return api.NewMounterError(api.ResultCode_Invalid, "Request for resources error")
 	}
-	if !util.CheckResourcesInNode(node, map[v1.ResourceName]resource.Quantity{
+	if err := util.CheckFreeResourcesInNode(calculator, node, map[v1.ResourceName]resource.Quantity{
 		VolcanoVGPUNumber: request[VolcanoVGPUNumber],
-	}) {
-		return api.NewMounterError(api.ResultCode_Insufficient, "Insufficient node resources")
+	}); err != nil {
+		return api.NewMounterError(api.ResultCode_Insufficient, err.Error())
 	}
 
 	expansion := config.AnnoIsExpansion(annotations)
@@ -76,12 +80,12 @@ func (m *VolcanoVGPUMounter) ValidateMountRequest(_ context.Context,
 		quantity := request[VolcanoVGPUNumber]
 		if quantity.Value() > int64(len(usedUUIDs)) {
 			msg := "The requested resource count exceeds the target container resource count and cannot be expanded"
-			return api.NewMounterError(api.ResultCode_Fail, msg)
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
 		}
 	case expansion:
 		if _, err := os.Stat(GetVGPUCacheFileDir(ownerPod, container)); err != nil {
 			msg := "The target container does not have vGPU resources and cannot be expanded"
-			return api.NewMounterError(api.ResultCode_Fail, msg)
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
 		}
 	default:
 		// 非扩展请求校验容器是否初始化过vGPU设备
@@ -89,7 +93,7 @@ func (m *VolcanoVGPUMounter) ValidateMountRequest(_ context.Context,
 		ctrNames := strings.Split(strings.TrimSpace(names), ",")
 		if slices.Contains(ctrNames, container.Name) {
 			msg := "The target container has initialized the vGPU and cannot be mounted again"
-			return api.NewMounterError(api.ResultCode_Fail, msg)
+			return api.NewMounterError(api.ResultCode_Invalid, msg)
 		}
 	}
 
@@ -160,7 +164,7 @@ func (m *VolcanoVGPUMounter) VerifySupportPodStatus(_ context.Context, slavePod
 	if slavePod.Status.Phase == v1.PodFailed {
 		err := fmt.Errorf("device slave container start failed")
 		if len(slavePod.Status.Message) > 0 {
-			err = fmt.Errorf(slavePod.Status.Message)
+			err = errors.New(slavePod.Status.Message)
 		}
 		return api.Fail, err
 	}
@@ -176,7 +180,7 @@ func (m *VolcanoVGPUMounter) VerifySupportPodStatus(_ context.Context, slavePod
 }
 
 // 获取待挂载的设备信息
-func (m *VolcanoVGPUMounter) GetDeviceInfosToMount(_ context.Context, _ *kubernetes.Clientset,
+func (m *VolcanoVGPUMounter) GetDeviceInfosToMount(_ context.Context, _ kubernetes.Interface,
 	ownerPod *v1.Pod, container *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 
 	var deviceInfos []api.DeviceInfo
@@ -190,7 +194,7 @@ func (m *VolcanoVGPUMounter) GetDeviceInfosToMount(_ context.Context, _ *kuberne
 		podDevices := decodePodDevices(slavePod.Annotations[AssignedIDsAnnotations])
 		for _, devs := range podDevices {
 			for _, dev := range devs {
-				minor, err := GetDeviceMinorByUUID(dev.UUID)
+				minor, err := GetDeviceMinorByUUID(m.nvmllib, dev.UUID)
 				if err != nil {
 					return nil, err
 				}
@@ -290,7 +294,7 @@ func subVGPUResource(cacheFile string, devMap map[string]Device) error {
 	})
 }
 
-func ExpansionVGPUDevice(ctx context.Context, kubeClient *kubernetes.Clientset,
+func ExpansionVGPUDevice(ctx context.Context, kubeClient kubernetes.Interface,
 	ownerPod *v1.Pod, container *api.Container, devMap map[string]Device) (RollBackFunc, error) {
 	// 默认调用一次命令，保证vgpu拦截库生成缓存
 	_ = execNvidiaSMI(ctx, kubeClient, ownerPod, container)
@@ -304,7 +308,7 @@ func ExpansionVGPUDevice(ctx context.Context, kubeClient *kubernetes.Clientset,
 	return rollBackFunc, nil
 }
 
-func (m *VolcanoVGPUMounter) ExecutePostMountActions(ctx context.Context, kubeClient *kubernetes.Clientset,
+func (m *VolcanoVGPUMounter) ExecutePostMountActions(ctx context.Context, kubeClient kubernetes.Interface,
 	cfg util.Config, ownerPod *v1.Pod, container *api.Container, slavePods []*v1.Pod) error {
 	for _, slavePod := range slavePods {
 		devMap := GetPodDevMap(slavePod)
@@ -405,7 +409,7 @@ func (m *VolcanoVGPUMounter) ExecutePostMountActions(ctx context.Context, kubeCl
 }
 
 // 获取卸载的设备信息
-func (m *VolcanoVGPUMounter) GetDeviceInfosToUnmount(_ context.Context, _ *kubernetes.Clientset,
+func (m *VolcanoVGPUMounter) GetDeviceInfosToUnmount(_ context.Context, _ kubernetes.Interface,
 	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) ([]api.DeviceInfo, error) {
 	var deviceInfos []api.DeviceInfo
 	for _, slavePod := range slavePods {
@@ -415,7 +419,7 @@ func (m *VolcanoVGPUMounter) GetDeviceInfosToUnmount(_ context.Context, _ *kuber
 		podDevices := decodePodDevices(slavePod.Annotations[AssignedIDsAnnotations])
 		for _, devs := range podDevices {
 			for _, dev := range devs {
-				minor, err := GetDeviceMinorByUUID(dev.UUID)
+				minor, err := GetDeviceMinorByUUID(m.nvmllib, dev.UUID)
 				if err != nil {
 					return nil, err
 				}
@@ -441,7 +445,7 @@ func (m *VolcanoVGPUMounter) GetDevicesActiveProcessIDs(_ context.Context,
 	containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error) {
 	var pids []int
 	for _, info := range deviceInfos {
-		if err := DeviceRunningProcessFunc(info.DeviceID, func(process nvml.ProcessInfo) {
+		if err := DeviceRunningProcessFunc(m.nvmllib, info.DeviceID, func(process nvml.ProcessInfo) {
 			if slices.Contains(containerPids, int(process.Pid)) {
 				pids = append(pids, int(process.Pid))
 			}
@@ -453,7 +457,7 @@ func (m *VolcanoVGPUMounter) GetDevicesActiveProcessIDs(_ context.Context,
 }
 
 // 卸载设备成功前的后续动作
-func (m *VolcanoVGPUMounter) ExecutePostUnmountActions(ctx context.Context, kubeClient *kubernetes.Clientset,
+func (m *VolcanoVGPUMounter) ExecutePostUnmountActions(ctx context.Context, kubeClient kubernetes.Interface,
 	cfg util.Config, ownerPod *v1.Pod, container *api.Container, slavePods []*v1.Pod) error {
 
 	var tmpSlavePods []*v1.Pod
@@ -510,7 +514,7 @@ func (m *VolcanoVGPUMounter) ExecutePostUnmountActions(ctx context.Context, kube
 	return nil
 }
 
-func (m *VolcanoVGPUMounter) GetPodsToCleanup(_ context.Context, _ *kubernetes.Clientset,
+func (m *VolcanoVGPUMounter) GetPodsToCleanup(_ context.Context, _ kubernetes.Interface,
 	_ *v1.Pod, _ *api.Container, slavePods []*v1.Pod) []api.ObjectKey {
 	podKeys := make([]api.ObjectKey, 0, len(slavePods))
 	for _, slavePod := range slavePods {
@@ -0,0 +1,49 @@
+package vgpu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/devices/nvidia/fakenvml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFakeVGPUMounter() *VolcanoVGPUMounter {
+	return &VolcanoVGPUMounter{nvmllib: fakenvml.New(
+		fakenvml.GPU{UUID: "GPU-0", Minor: 0, ComputeProcesses: []uint32{100, 300}},
+		fakenvml.GPU{UUID: "GPU-1", Minor: 2, GraphicsProcesses: []uint32{200}},
+	)}
+}
+
+func Test_GetDeviceInfosToUnmount(t *testing.T) {
+	mounter := newFakeVGPUMounter()
+	slavePod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		AssignedIDsAnnotations: "0,GPU-1,NVIDIA,2000,20:;",
+	}}}
+	deviceInfos, err := mounter.GetDeviceInfosToUnmount(context.Background(), nil, nil, nil, []*v1.Pod{slavePod})
+	require.NoError(t, err)
+	require.Len(t, deviceInfos, 1)
+	assert.Equal(t, "GPU-1", deviceInfos[0].DeviceID)
+	assert.Equal(t, "/dev/nvidia2", deviceInfos[0].DeviceFilePath)
+	assert.Equal(t, int64(2), deviceInfos[0].Rule.Minor)
+	assert.False(t, deviceInfos[0].Rule.Allow)
+
+	slavePod.Annotations[AssignedIDsAnnotations] = "0,GPU-9,NVIDIA,2000,20:;"
+	_, err = mounter.GetDeviceInfosToUnmount(context.Background(), nil, nil, nil, []*v1.Pod{slavePod})
+	assert.Error(t, err)
+}
+
+func Test_GetDevicesActiveProcessIDs(t *testing.T) {
+	mounter := newFakeVGPUMounter()
+	deviceInfos := []api.DeviceInfo{{DeviceID: "GPU-0"}, {DeviceID: "GPU-1"}}
+	pids, err := mounter.GetDevicesActiveProcessIDs(context.Background(), []int{100, 200}, deviceInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 200}, pids)
+
+	_, err = mounter.GetDevicesActiveProcessIDs(context.Background(), []int{100}, []api.DeviceInfo{{DeviceID: "GPU-9"}})
+	assert.Error(t, err)
+}
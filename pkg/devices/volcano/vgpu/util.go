@@ -16,6 +16,7 @@ import (
 	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
 	"github.com/coldzerofear/device-mounter/pkg/client"
+	"github.com/coldzerofear/device-mounter/pkg/util"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	uuid2 "k8s.io/apimachinery/pkg/util/uuid"
@@ -237,36 +238,36 @@ func decodeContainerDevices(str string) ContainerDevices {
 	return contdev
 }
 
-func GetDeviceMinorByUUID(uuid string) (int, error) {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		return 0, fmt.Errorf("nvml Init error: %s", nvml.ErrorString(rt))
+func GetDeviceMinorByUUID(nvmllib nvml.Interface, uuid string) (int, error) {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		return 0, fmt.Errorf("nvml Init error: %s", nvmllib.ErrorString(rt))
 	}
-	defer nvml.Shutdown()
+	defer nvmllib.Shutdown()
 
-	dev, rt := nvml.DeviceGetHandleByUUID(uuid)
+	dev, rt := nvmllib.DeviceGetHandleByUUID(uuid)
 	if rt != nvml.SUCCESS {
-		return 0, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvml.ErrorString(rt))
+		return 0, fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvmllib.ErrorString(rt))
 	}
 	minor, rt := dev.GetMinorNumber()
 	if rt != nvml.SUCCESS {
-		return 0, fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvml.ErrorString(rt))
+		return 0, fmt.Errorf("nvml DeviceGetMinorNumber error: %s", nvmllib.ErrorString(rt))
 	}
 	return minor, nil
 }
 
-func DeviceRunningProcessFunc(uuid string, f func(process nvml.ProcessInfo)) error {
-	if rt := nvml.Init(); rt != nvml.SUCCESS {
-		return fmt.Errorf("nvml Init error: %s", nvml.ErrorString(rt))
+func DeviceRunningProcessFunc(nvmllib nvml.Interface, uuid string, f func(process nvml.ProcessInfo)) error {
+	if rt := nvmllib.Init(); rt != nvml.SUCCESS {
+		return fmt.Errorf("nvml Init error: %s", nvmllib.ErrorString(rt))
 	}
-	defer nvml.Shutdown()
+	defer nvmllib.Shutdown()
 
-	dev, rt := nvml.DeviceGetHandleByUUID(uuid)
+	dev, rt := nvmllib.DeviceGetHandleByUUID(uuid)
 	if rt != nvml.SUCCESS {
-		return fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvml.ErrorString(rt))
+		return fmt.Errorf("nvml DeviceGetHandleByUUID error: %s", nvmllib.ErrorString(rt))
 	}
 	processes, rt := dev.GetComputeRunningProcesses()
 	if rt != nvml.SUCCESS {
-		return fmt.Errorf("nvml DeviceGetComputeRunningProcesses error: %s", nvml.ErrorString(rt))
+		return fmt.Errorf("nvml DeviceGetComputeRunningProcesses error: %s", nvmllib.ErrorString(rt))
 	}
 	for _, process := range processes {
 		if f != nil {
@@ -275,7 +276,7 @@ func DeviceRunningProcessFunc(uuid string, f func(process nvml.ProcessInfo)) err
 	}
 	processes, rt = dev.GetGraphicsRunningProcesses()
 	if rt != nvml.SUCCESS {
-		return fmt.Errorf("nvml DeviceGetGraphicsRunningProcesses error: %s", nvml.ErrorString(rt))
+		return fmt.Errorf("nvml DeviceGetGraphicsRunningProcesses error: %s", nvmllib.ErrorString(rt))
 	}
 	for _, process := range processes {
 		if f != nil {
@@ -286,7 +287,10 @@ func DeviceRunningProcessFunc(uuid string, f func(process nvml.ProcessInfo)) err
 }
 
 func HasVGPU(pod *v1.Pod, container *api.Container) bool {
-	c := pod.Spec.Containers[container.Index]
+	c, ok := util.GetContainerSpec(pod, container)
+	if !ok {
+		return false
+	}
 	q, ok := c.Resources.Limits[VolcanoVGPUNumber]
 	return ok && !q.IsZero()
 }
@@ -386,7 +390,7 @@ func GetInitVGPUShell(envs []string) string {
 	return initShell
 }
 
-func execNvidiaSMI(ctx context.Context, kubeClient *kubernetes.Clientset, ownerPod *v1.Pod, container *api.Container) error {
+func execNvidiaSMI(ctx context.Context, kubeClient kubernetes.Interface, ownerPod *v1.Pod, container *api.Container) error {
 	cmd := []string{"nvidia-smi"}
 	_, _, err := client.ExecCmdToPod(ctx, kubeClient, ownerPod, container, cmd)
 	if err != nil {
@@ -2,6 +2,7 @@ package framework
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -17,8 +18,8 @@ type DeviceMounter interface {
 	// 获取设备的类型标识
 	GetDeviceType() string
 
-	// 验证挂载请求是否有效
-	ValidateMountRequest(ctx context.Context, kubeClient *kubernetes.Clientset, node *v1.Node, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string) error
+	// 验证挂载请求是否有效，calculator 计算节点的空闲资源，可能为空
+	ValidateMountRequest(ctx context.Context, kubeClient kubernetes.Interface, calculator util.CapacityCalculator, node *v1.Node, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string) error
 
 	// 构建辅助Pod模板
 	BuildSupportPodTemplates(ctx context.Context, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string, existingSupportPods []*v1.Pod) ([]*v1.Pod, error)
@@ -27,22 +28,38 @@ type DeviceMounter interface {
 	VerifySupportPodStatus(ctx context.Context, supportPod *v1.Pod) (api.StatusCode, error)
 
 	// 获取待挂载设备的信息
-	GetDeviceInfosToMount(ctx context.Context, kubeClient *kubernetes.Clientset, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error)
+	GetDeviceInfosToMount(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error)
 
 	// 执行挂载设备后的操作
-	ExecutePostMountActions(ctx context.Context, kubeClient *kubernetes.Clientset, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error
+	ExecutePostMountActions(ctx context.Context, kubeClient kubernetes.Interface, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error
 
 	// 获取待卸载设备的信息
-	GetDeviceInfosToUnmount(ctx context.Context, kubeClient *kubernetes.Clientset, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error)
+	GetDeviceInfosToUnmount(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error)
 
 	// 获取设备上的活动进程ID
 	GetDevicesActiveProcessIDs(ctx context.Context, containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error)
 
 	// 执行卸载设备后的操作
-	ExecutePostUnmountActions(ctx context.Context, kubeClient *kubernetes.Clientset, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error
+	ExecutePostUnmountActions(ctx context.Context, kubeClient kubernetes.Interface, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error
 
 	// 获取卸载设备后需要清理的Pod资源
-	GetPodsToCleanup(ctx context.Context, kubeClient *kubernetes.Clientset, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) []api.ObjectKey
+	GetPodsToCleanup(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) []api.ObjectKey
+}
+
+// DirectDeviceMounter 无需辅助Pod直接挂载设备的挂载器，例如主机设备
+// BuildSupportPodTemplates is skipped, the devices to mount are resolved from the request, and the mounted devices
+// are recorded by the device mounter itself. The other methods are called without support pods.
+type DirectDeviceMounter interface {
+	DeviceMounter
+
+	// 根据请求获取待挂载设备的信息
+	GetRequestedDeviceInfos(ctx context.Context, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity) ([]api.DeviceInfo, error)
+
+	// 记录容器挂载或卸载的设备，requestID 为挂载请求的幂等键，可能为空
+	RecordDevices(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, container *api.Container, requestID string, deviceInfos []api.DeviceInfo, mounted bool) error
+
+	// 获取指定请求ID挂载到容器的设备信息，用于重复的挂载请求和按挂载请求卸载
+	GetRequestMountedDeviceInfos(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, container *api.Container, requestID string) ([]api.DeviceInfo, error)
 }
 
 type CreateMounterFunc func() (DeviceMounter, error)
@@ -51,11 +68,31 @@ var (
 	lock                  sync.Mutex
 	registerDeviceMounter map[string]DeviceMounter
 	addDeviceMounterFuncs []CreateMounterFunc
+	reservedDeviceTypes   map[string]struct{}
 )
 
 func init() {
 	registerDeviceMounter = make(map[string]DeviceMounter)
 	addDeviceMounterFuncs = make([]CreateMounterFunc, 0)
+	reservedDeviceTypes = make(map[string]struct{})
+}
+
+// ReserveDeviceTypes Reserve the types of the built-in device mounters,
+// they can not be registered at runtime even if the built-in device mounter is not available on the node.
+func ReserveDeviceTypes(devTypes ...string) {
+	lock.Lock()
+	for _, devType := range devTypes {
+		reservedDeviceTypes[strings.ToUpper(devType)] = struct{}{}
+	}
+	lock.Unlock()
+}
+
+// IsReservedDeviceType Whether the device type belongs to a built-in device mounter.
+func IsReservedDeviceType(devType string) bool {
+	lock.Lock()
+	_, ok := reservedDeviceTypes[strings.ToUpper(devType)]
+	lock.Unlock()
+	return ok
 }
 
 func AddDeviceMounterFuncs(createFunc CreateMounterFunc) {
@@ -98,3 +135,28 @@ func GetDeviceMounter(devType string) (DeviceMounter, bool) {
 	lock.Unlock()
 	return mounter, ok
 }
+
+// RegisterDeviceMounter Register the device mounter at runtime, e.g. the out-of-process plugins.
+// The device mounter already registered with the same type is replaced only if canReplace allows it.
+func RegisterDeviceMounter(mounter DeviceMounter, canReplace func(registered DeviceMounter) bool) error {
+	lock.Lock()
+	defer lock.Unlock()
+	devType := strings.ToUpper(mounter.GetDeviceType())
+	if registered, ok := registerDeviceMounter[devType]; ok && (canReplace == nil || !canReplace(registered)) {
+		return fmt.Errorf("device type %s has already been registered", devType)
+	}
+	registerDeviceMounter[devType] = mounter
+	return nil
+}
+
+// UnregisterDeviceMounter Remove the device mounter if it is still the registered one of its type.
+func UnregisterDeviceMounter(mounter DeviceMounter) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	devType := strings.ToUpper(mounter.GetDeviceType())
+	if registered, ok := registerDeviceMounter[devType]; ok && registered == mounter {
+		delete(registerDeviceMounter, devType)
+		return true
+	}
+	return false
+}
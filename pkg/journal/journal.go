@@ -0,0 +1,276 @@
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+)
+
+type Operation string
+
+const (
+	MountOperation   Operation = "Mount"
+	UnMountOperation Operation = "UnMount"
+)
+
+type Phase string
+
+const (
+	// The operation has been accepted, nothing has been changed on the node yet.
+	PhaseStarted Phase = "Started"
+	// The slave pods recorded in the entry are about to be created (mount only).
+	PhaseSlavePodsPending Phase = "SlavePodsPending"
+	// The device information has been resolved and the rules are about to be written.
+	PhaseDeviceRulesPending Phase = "DeviceRulesPending"
+	// The cgroup device rules have been applied.
+	PhaseDeviceRulesSet Phase = "DeviceRulesSet"
+	// The device files in the container have been created or deleted.
+	PhaseDeviceFilesSet Phase = "DeviceFilesSet"
+)
+
+const (
+	fileSuffix = ".json"
+	resultsDir = "results"
+)
+
+// Entry A write-ahead record of a single mount or unmount operation.
+type Entry struct {
+	ID            string        `json:"id"`
+	Operation     Operation     `json:"operation"`
+	Phase         Phase         `json:"phase"`
+	DeviceType    string        `json:"deviceType"`
+	Pod           api.ObjectKey `json:"pod"`
+	ContainerName string        `json:"containerName"`
+	ContainerID   string        `json:"containerID,omitempty"`
+	// The values of the created-by label of the slave pods created by the operation.
+	SlavePodIDs []string         `json:"slavePodIDs,omitempty"`
+	CleanupPods []api.ObjectKey  `json:"cleanupPods,omitempty"`
+	DeviceInfos []api.DeviceInfo `json:"deviceInfos,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+// Result The result of a finished request kept for the retries with the same request id.
+type Result struct {
+	Key string `json:"key"`
+	// The digest of the request parameters, a retry with other parameters is a conflict.
+	Digest    string          `json:"digest"`
+	Response  json.RawMessage `json:"response"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// Journal Persist the progress of device operations on the node,
+// so that incomplete operations can be recovered after the mounter restarts.
+type Journal struct {
+	dir  string
+	lock sync.Mutex
+}
+
+func NewJournal(dir string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Join(dir, resultsDir), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory %s: %v", dir, err)
+	}
+	return &Journal{dir: dir}, nil
+}
+
+func (j *Journal) Dir() string {
+	if j == nil {
+		return ""
+	}
+	return j.dir
+}
+
+// Begin Create and persist a new journal entry.
+// A nil journal is allowed, in which case nothing is recorded.
+func (j *Journal) Begin(op Operation, deviceType string, pod api.ObjectKey, containerName, containerID string) (*Entry, error) {
+	now := time.Now()
+	entry := &Entry{
+		ID:            uuid.New().String(),
+		Operation:     op,
+		Phase:         PhaseStarted,
+		DeviceType:    deviceType,
+		Pod:           pod,
+		ContainerName: containerName,
+		ContainerID:   containerID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return entry, j.Save(entry)
+}
+
+// Record Update the phase of the entry and persist it.
+func (j *Journal) Record(entry *Entry, phase Phase) error {
+	if entry == nil {
+		return nil
+	}
+	entry.Phase = phase
+	entry.UpdatedAt = time.Now()
+	return j.Save(entry)
+}
+
+// Save Atomically write the entry to disk.
+func (j *Journal) Save(entry *Entry) error {
+	if j == nil || entry == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return writeFile(j.path(entry.ID), data)
+}
+
+// writeFile Write the file through a synced temp file and rename it.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	// Ensure that the record is persisted before continuing with the next step.
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Complete Remove the entry after the operation has finished (successfully or rolled back).
+func (j *Journal) Complete(entry *Entry) error {
+	if j == nil || entry == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	err := os.Remove(j.path(entry.ID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// List Read all unfinished entries, sorted by creation time.
+func (j *Journal) List() ([]*Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(j.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entry := &Entry{}
+		if err = json.Unmarshal(data, entry); err != nil {
+			klog.Warningf("Skip corrupted journal entry %s: %v", file.Name(), err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, k int) bool {
+		return entries[i].CreatedAt.Before(entries[k].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (j *Journal) path(id string) string {
+	return filepath.Join(j.dir, id+fileSuffix)
+}
+
+// SaveResult Persist the result of a finished request, replacing the result with the same key.
+func (j *Journal) SaveResult(result *Result) error {
+	if j == nil || result == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return writeFile(j.resultPath(result.Key), data)
+}
+
+// RemoveResult Remove the result with the key.
+func (j *Journal) RemoveResult(key string) error {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	err := os.Remove(j.resultPath(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// ListResults Read the persisted request results, the expired ones are removed.
+func (j *Journal) ListResults() ([]*Result, error) {
+	if j == nil {
+		return nil, nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	dir := filepath.Join(j.dir, resultsDir)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	results := make([]*Result, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fileSuffix) {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		result := &Result{}
+		if err = json.Unmarshal(data, result); err != nil || now.After(result.ExpiresAt) {
+			_ = os.Remove(filePath)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// resultPath The keys contain the user defined request ids, the file is named by the hash of the key.
+func (j *Journal) resultPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(j.dir, resultsDir, hex.EncodeToString(sum[:])+fileSuffix)
+}
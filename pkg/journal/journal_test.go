@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func Test_Journal(t *testing.T) {
+	j, err := NewJournal(filepath.Join(t.TempDir(), "journal"))
+	assert.NoError(t, err)
+
+	podKey := api.ObjectKey{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "test"},
+		UID:            pointer.String("uid"),
+	}
+	entry, err := j.Begin(MountOperation, "NVIDIA_GPU", podKey, "main", "containerd://abc")
+	assert.NoError(t, err)
+
+	entry.DeviceInfos = []api.DeviceInfo{{
+		Rule: devices.Rule{
+			Type:        devices.CharDevice,
+			Major:       195,
+			Minor:       0,
+			Permissions: "rw",
+			Allow:       true,
+		},
+		DeviceID:       "GPU-0",
+		DeviceFilePath: "/dev/nvidia0",
+	}}
+	assert.NoError(t, j.Record(entry, PhaseDeviceRulesSet))
+
+	entries, err := j.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+	assert.Equal(t, PhaseDeviceRulesSet, entries[0].Phase)
+	assert.Equal(t, podKey, entries[0].Pod)
+	assert.Equal(t, entry.DeviceInfos, entries[0].DeviceInfos)
+
+	// corrupted entries are skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(j.Dir(), "broken.json"), []byte("{"), 0o600))
+	entries, err = j.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, j.Complete(entry))
+	assert.NoError(t, j.Complete(entry))
+	entries, err = j.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func Test_NilJournal(t *testing.T) {
+	var j *Journal
+	entry, err := j.Begin(UnMountOperation, "NVIDIA_GPU", api.ObjectKey{}, "main", "")
+	assert.NoError(t, err)
+	assert.NoError(t, j.Record(entry, PhaseDeviceFilesSet))
+	assert.NoError(t, j.Complete(entry))
+	entries, err := j.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func Test_JournalResults(t *testing.T) {
+	j, err := NewJournal(filepath.Join(t.TempDir(), "journal"))
+	assert.NoError(t, err)
+
+	key := "UnMount/uid/main/a request id with / and spaces"
+	assert.NoError(t, j.SaveResult(&Result{Key: key, Digest: "digest", Response: []byte(`{}`), ExpiresAt: time.Now().Add(time.Hour)}))
+	assert.NoError(t, j.SaveResult(&Result{Key: "expired", Response: []byte(`{}`), ExpiresAt: time.Now().Add(-time.Second)}))
+	// The results are not operations to recover.
+	entries, err := j.List()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	results, err := j.ListResults()
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, key, results[0].Key)
+		assert.Equal(t, "digest", results[0].Digest)
+	}
+	files, err := os.ReadDir(filepath.Join(j.Dir(), resultsDir))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	assert.NoError(t, j.RemoveResult(key))
+	assert.NoError(t, j.RemoveResult(key))
+	results, err = j.ListResults()
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
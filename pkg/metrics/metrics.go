@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+const namespace = "device_mounter"
+
+var (
+	// RequestTotal The number of mount and unmount requests handled by the mounter.
+	RequestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Total number of device mount and unmount requests by device type and result code.",
+	}, []string{"operation", "device_type", "result"})
+
+	// RequestDuration The time taken by the mounter to handle the mount and unmount requests.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of device mount and unmount requests by device type and result code.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"operation", "device_type", "result"})
+
+	// SlavePodsWaitDuration The time waiting for the slave pods to be ready.
+	SlavePodsWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "slave_pods_wait_duration_seconds",
+		Help:      "Latency of waiting for the slave pods to be ready.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"device_type", "ready"})
+
+	// DeviceRuleFailures The number of failures to apply the cgroup device rules.
+	DeviceRuleFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "device_rule_failures_total",
+		Help:      "Total number of failures to apply the cgroup device rules.",
+	})
+
+	// GarbageCollectionFailures The number of slave pods that failed to be deleted.
+	GarbageCollectionFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "garbage_collection_failures_total",
+		Help:      "Total number of pods that failed to be garbage collected.",
+	})
+
+	// MountedDevicesDesc The devices currently mounted on the node, collected by the mounter.
+	MountedDevicesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "mounted_devices"),
+		"Number of devices currently mounted into containers on the node.",
+		[]string{"node", "device_type"}, nil)
+
+	// APIServerRequestTotal The number of requests handled by the apiserver.
+	APIServerRequestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "apiserver",
+		Name:      "requests_total",
+		Help:      "Total number of apiserver requests by operation and http status code.",
+	}, []string{"operation", "code"})
+
+	// APIServerRequestDuration The time taken by the apiserver to handle the requests.
+	APIServerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "apiserver",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of apiserver requests by operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"operation"})
+)
+
+// RegisterMounterMetrics Register the metrics of the mounter daemon.
+func RegisterMounterMetrics(collectors ...prometheus.Collector) {
+	prometheus.MustRegister(RequestTotal, RequestDuration,
+		SlavePodsWaitDuration, DeviceRuleFailures, GarbageCollectionFailures)
+	prometheus.MustRegister(collectors...)
+}
+
+// RegisterAPIServerMetrics Register the metrics of the apiserver.
+func RegisterAPIServerMetrics() {
+	prometheus.MustRegister(APIServerRequestTotal, APIServerRequestDuration)
+}
+
+// ObserveRequest Record a handled mount or unmount request.
+func ObserveRequest(operation, deviceType string, result api.ResultCode, start time.Time) {
+	RequestTotal.WithLabelValues(operation, deviceType, result.String()).Inc()
+	RequestDuration.WithLabelValues(operation, deviceType, result.String()).Observe(time.Since(start).Seconds())
+}
+
+// ObserveSlavePodsWait Record the time waiting for the slave pods.
+func ObserveSlavePodsWait(deviceType string, ready bool, start time.Time) {
+	SlavePodsWaitDuration.WithLabelValues(deviceType, strconv.FormatBool(ready)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveAPIServerRequest Record a request handled by the apiserver.
+func ObserveAPIServerRequest(operation string, code int, start time.Time) {
+	APIServerRequestTotal.WithLabelValues(operation, strconv.Itoa(code)).Inc()
+	APIServerRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// StartServer Serve the registered metrics on the /metrics path of the address,
+// an empty address disables the listener.
+func StartServer(addr string) *http.Server {
+	if len(addr) == 0 {
+		klog.Infoln("Metrics server is disabled")
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		klog.Infoln("Serving metrics server on", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			klog.ErrorS(err, "Metrics server error")
+		}
+	}()
+	return server
+}
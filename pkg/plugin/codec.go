@@ -0,0 +1,224 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	pluginapi "github.com/coldzerofear/device-mounter/pkg/api/plugin"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The helpers below convert the types of the framework to the messages of the plugin api and back,
+// they are shared by the device mounter and the plugins written in go.
+
+func EncodePod(pod *v1.Pod) ([]byte, error) {
+	if pod == nil {
+		return nil, nil
+	}
+	return json.Marshal(pod)
+}
+
+func DecodePod(data []byte) (*v1.Pod, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	pod := &v1.Pod{}
+	if err := json.Unmarshal(data, pod); err != nil {
+		return nil, fmt.Errorf("failed to decode pod: %v", err)
+	}
+	return pod, nil
+}
+
+func EncodePods(pods []*v1.Pod) ([][]byte, error) {
+	items := make([][]byte, len(pods))
+	for i, pod := range pods {
+		data, err := EncodePod(pod)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = data
+	}
+	return items, nil
+}
+
+func DecodePods(items [][]byte) ([]*v1.Pod, error) {
+	pods := make([]*v1.Pod, 0, len(items))
+	for _, data := range items {
+		pod, err := DecodePod(data)
+		if err != nil {
+			return nil, err
+		}
+		if pod != nil {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func EncodeNode(node *v1.Node) ([]byte, error) {
+	if node == nil {
+		return nil, nil
+	}
+	return json.Marshal(node)
+}
+
+func DecodeNode(data []byte) (*v1.Node, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	node := &v1.Node{}
+	if err := json.Unmarshal(data, node); err != nil {
+		return nil, fmt.Errorf("failed to decode node: %v", err)
+	}
+	return node, nil
+}
+
+func EncodeContainer(container *api.Container) *pluginapi.Container {
+	if container == nil {
+		return nil
+	}
+	return &pluginapi.Container{
+		Index: container.GetIndex(),
+		Name:  container.GetName(),
+		Type:  int32(container.GetType()),
+	}
+}
+
+func DecodeContainer(container *pluginapi.Container) *api.Container {
+	if container == nil {
+		return nil
+	}
+	return &api.Container{
+		Index: container.GetIndex(),
+		Name:  container.GetName(),
+		Type:  api.ContainerType(container.GetType()),
+	}
+}
+
+func EncodeResources(resources map[v1.ResourceName]resource.Quantity) map[string]string {
+	quantities := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		quantities[string(name)] = quantity.String()
+	}
+	return quantities
+}
+
+func DecodeResources(quantities map[string]string) (map[v1.ResourceName]resource.Quantity, error) {
+	resources := make(map[v1.ResourceName]resource.Quantity, len(quantities))
+	for name, value := range quantities {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of resource %s: %v", name, err)
+		}
+		resources[v1.ResourceName(name)] = quantity
+	}
+	return resources, nil
+}
+
+func EncodeDeviceInfos(deviceInfos []api.DeviceInfo) []*pluginapi.DeviceInfo {
+	items := make([]*pluginapi.DeviceInfo, len(deviceInfos))
+	for i, deviceInfo := range deviceInfos {
+		items[i] = &pluginapi.DeviceInfo{
+			DeviceId:       deviceInfo.DeviceID,
+			DeviceFilePath: deviceInfo.DeviceFilePath,
+			Type:           string(deviceInfo.Type),
+			Major:          deviceInfo.Major,
+			Minor:          deviceInfo.Minor,
+			Permissions:    string(deviceInfo.Permissions),
+			Allow:          deviceInfo.Allow,
+		}
+	}
+	return items
+}
+
+func DecodeDeviceInfos(items []*pluginapi.DeviceInfo) ([]api.DeviceInfo, error) {
+	deviceInfos := make([]api.DeviceInfo, len(items))
+	for i, item := range items {
+		if len(item.GetType()) != 1 {
+			return nil, fmt.Errorf("invalid type %q of device %s", item.GetType(), item.GetDeviceId())
+		}
+		deviceInfos[i] = api.DeviceInfo{
+			DeviceID:       item.GetDeviceId(),
+			DeviceFilePath: item.GetDeviceFilePath(),
+			Rule: devices.Rule{
+				Type:        devices.Type(item.GetType()[0]),
+				Major:       item.GetMajor(),
+				Minor:       item.GetMinor(),
+				Permissions: devices.Permissions(item.GetPermissions()),
+				Allow:       item.GetAllow(),
+			},
+		}
+	}
+	return deviceInfos, nil
+}
+
+func EncodeObjectKeys(keys []api.ObjectKey) []*pluginapi.ObjectKey {
+	items := make([]*pluginapi.ObjectKey, len(keys))
+	for i, key := range keys {
+		items[i] = &pluginapi.ObjectKey{Namespace: key.Namespace, Name: key.Name}
+		if key.UID != nil {
+			items[i].Uid = *key.UID
+		}
+	}
+	return items
+}
+
+func DecodeObjectKeys(items []*pluginapi.ObjectKey) []api.ObjectKey {
+	keys := make([]api.ObjectKey, len(items))
+	for i, item := range items {
+		keys[i] = api.ObjectKey{NamespacedName: types.NamespacedName{
+			Namespace: item.GetNamespace(),
+			Name:      item.GetName(),
+		}}
+		if len(item.GetUid()) > 0 {
+			uid := item.GetUid()
+			keys[i].UID = &uid
+		}
+	}
+	return keys
+}
+
+// ToStatusError Convert the error returned by the plugin to a grpc status, the result code
+// of the mounter error is kept in the Error detail.
+func ToStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	mounterErr, ok := err.(*api.MounterError)
+	if !ok {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	s, detailErr := status.New(codes.Unknown, mounterErr.Message).
+		WithDetails(&pluginapi.Error{ResultCode: int32(mounterErr.Code)})
+	if detailErr != nil {
+		return status.Error(codes.Unknown, mounterErr.Message)
+	}
+	return s.Err()
+}
+
+// FromStatusError Convert the grpc status returned by the plugin to a mounter error if it carries a result code.
+func FromStatusError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s == nil {
+		return err
+	}
+	for _, detail := range s.Details() {
+		if pluginErr, ok := detail.(*pluginapi.Error); ok {
+			return api.NewMounterError(api.ResultCode(pluginErr.GetResultCode()), s.Message())
+		}
+	}
+	if s.Code() == codes.DeadlineExceeded {
+		return fmt.Errorf("device mounter plugin error: %w", context.DeadlineExceeded)
+	}
+	return fmt.Errorf("device mounter plugin error: %s", s.Message())
+}
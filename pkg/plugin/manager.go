@@ -0,0 +1,233 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	pluginapi "github.com/coldzerofear/device-mounter/pkg/api/plugin"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Version The version of the plugin api.
+	Version = "v1alpha1"
+	// RegistrationSocket The name of the registration socket served by the device mounter in the plugin directory.
+	RegistrationSocket = "device-mounter.sock"
+
+	dialTimeout = 5 * time.Second
+)
+
+// deviceTypeRegexp The device types like the built-in ones, e.g. NVIDIA_GPU, which are valid names of label keys.
+var deviceTypeRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_]*[A-Za-z0-9])?$`)
+
+// Manager Serve the registration of the device mounter plugins, the registered plugins are added to the framework
+// and removed when their connections are lost. Like the kubelet device plugins, the plugins should register again
+// when the registration socket is recreated by the restarted device mounter.
+type Manager struct {
+	pluginapi.UnimplementedRegistrationServer
+
+	dir string
+	// The options of the registration server, e.g. the peer authorization of the unix socket service.
+	serverOptions []grpc.ServerOption
+	server        *grpc.Server
+	lock          sync.Mutex
+	// The connections of the registered plugins by endpoint.
+	plugins map[string]*pluginMounter
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// The handlers called with the device type after a plugin registers.
+	registerHandlers []func(deviceType string)
+}
+
+func NewManager(dir string, serverOptions ...grpc.ServerOption) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		dir:           dir,
+		serverOptions: serverOptions,
+		plugins:       make(map[string]*pluginMounter),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// AddRegisterHandler Add the handler called in the background with the device type after a plugin registers,
+// e.g. to recover the unfinished operations of the device type.
+func (m *Manager) AddRegisterHandler(handler func(deviceType string)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.registerHandlers = append(m.registerHandlers, handler)
+}
+
+// Start Serve the registration socket in the plugin directory.
+func (m *Manager) Start() error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %v", err)
+	}
+	socketFile := filepath.Join(m.dir, RegistrationSocket)
+	_ = os.Remove(socketFile)
+	listen, err := net.Listen("unix", socketFile)
+	if err != nil {
+		return fmt.Errorf("failed to listen on registration socket: %v", err)
+	}
+	m.server = grpc.NewServer(m.serverOptions...)
+	pluginapi.RegisterRegistrationServer(m.server, m)
+	go func() {
+		if err := m.server.Serve(listen); err != nil {
+			klog.ErrorS(err, "Plugin registration grpc error")
+		}
+	}()
+	klog.Infoln("Serving plugin registration", "socket", socketFile)
+	return nil
+}
+
+// Stop Stop the registration and remove the registered plugins.
+func (m *Manager) Stop() {
+	if m.server != nil {
+		m.server.Stop()
+	}
+	m.cancel()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for endpoint, plugin := range m.plugins {
+		framework.UnregisterDeviceMounter(plugin)
+		_ = plugin.conn.Close()
+		delete(m.plugins, endpoint)
+	}
+}
+
+func (m *Manager) Register(_ context.Context, req *pluginapi.RegisterRequest) (*pluginapi.Empty, error) {
+	if err := validateRegisterRequest(req); err != nil {
+		klog.ErrorS(err, "Reject plugin registration", "endpoint", req.GetEndpoint(), "deviceType", req.GetDeviceType())
+		return nil, ToStatusError(err)
+	}
+	deviceType := strings.ToUpper(req.GetDeviceType())
+	socketFile := filepath.Join(m.dir, req.GetEndpoint())
+	if _, err := os.Stat(socketFile); err != nil {
+		return nil, ToStatusError(api.NewMounterError(api.ResultCode_NotFound,
+			fmt.Sprintf("Plugin endpoint %s not found: %v", req.GetEndpoint(), err)))
+	}
+	ctx, cancel := context.WithTimeout(m.ctx, dialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "unix://"+socketFile,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial plugin endpoint %s: %v", req.GetEndpoint(), err)
+	}
+	plugin := newPluginMounter(deviceType, req.GetEndpoint(), conn)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	// Only the plugin restarted on the same endpoint can replace the registered plugin,
+	// the built-in device mounters and the plugins of the other endpoints can not be replaced.
+	var replaced *pluginMounter
+	err = framework.RegisterDeviceMounter(plugin, func(registered framework.DeviceMounter) bool {
+		replaced, _ = registered.(*pluginMounter)
+		return replaced != nil && replaced.endpoint == plugin.endpoint
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, ToStatusError(api.NewMounterError(api.ResultCode_Conflict, err.Error()))
+	}
+	if replaced != nil {
+		m.removePlugin(replaced)
+	}
+	// The plugin on the endpoint may have been registered with another device type.
+	if old, ok := m.plugins[plugin.endpoint]; ok {
+		framework.UnregisterDeviceMounter(old)
+		m.removePlugin(old)
+	}
+	m.plugins[plugin.endpoint] = plugin
+	go m.watch(plugin)
+	for _, handler := range m.registerHandlers {
+		go handler(deviceType)
+	}
+	klog.Infoln("Registered device mounter plugin", "deviceType", deviceType, "endpoint", req.GetEndpoint())
+	return &pluginapi.Empty{}, nil
+}
+
+func validateRegisterRequest(req *pluginapi.RegisterRequest) error {
+	if req.GetVersion() != Version {
+		return api.NewMounterError(api.ResultCode_Invalid,
+			fmt.Sprintf("Unsupported plugin api version %q, expected %s", req.GetVersion(), Version))
+	}
+	endpoint := req.GetEndpoint()
+	if len(endpoint) == 0 || endpoint != filepath.Base(endpoint) || endpoint == RegistrationSocket {
+		return api.NewMounterError(api.ResultCode_Invalid,
+			fmt.Sprintf("Invalid plugin endpoint %q, expected a socket name in the plugin directory", endpoint))
+	}
+	if len(strings.TrimSpace(req.GetDeviceType())) == 0 {
+		return api.NewMounterError(api.ResultCode_Invalid, "Parameter 'device_type' cannot be empty")
+	}
+	// The device type shows up in the node labels, the REST paths and the RBAC resource names.
+	if deviceType := req.GetDeviceType(); len(deviceType) > validation.LabelValueMaxLength || !deviceTypeRegexp.MatchString(deviceType) {
+		return api.NewMounterError(api.ResultCode_Invalid,
+			fmt.Sprintf("Invalid device type %q: must consist of alphanumeric characters, '-' or '_', "+
+				"start and end with an alphanumeric character and be no more than %d characters, e.g. MY_FPGA",
+				deviceType, validation.LabelValueMaxLength))
+	}
+	if framework.IsReservedDeviceType(req.GetDeviceType()) {
+		return api.NewMounterError(api.ResultCode_Conflict,
+			fmt.Sprintf("Device type %s is reserved by the built-in device mounter", strings.ToUpper(req.GetDeviceType())))
+	}
+	return nil
+}
+
+// removePlugin Forget the plugin and close its connection, the caller holds the lock.
+func (m *Manager) removePlugin(plugin *pluginMounter) {
+	if m.plugins[plugin.endpoint] == plugin {
+		delete(m.plugins, plugin.endpoint)
+	}
+	_ = plugin.conn.Close()
+}
+
+// watch Remove the plugin once its connection is lost, e.g. the plugin has exited.
+func (m *Manager) watch(plugin *pluginMounter) {
+	for {
+		state := plugin.conn.GetState()
+		if state == connectivity.Idle {
+			plugin.conn.Connect()
+		}
+		if state == connectivity.TransientFailure || state == connectivity.Shutdown {
+			break
+		}
+		if !plugin.conn.WaitForStateChange(m.ctx, state) {
+			return
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if framework.UnregisterDeviceMounter(plugin) {
+		klog.Infoln("Removed device mounter plugin", "deviceType", plugin.deviceType, "endpoint", plugin.endpoint)
+	}
+	m.removePlugin(plugin)
+}
+
+// Register Register the plugin served on the endpoint socket in the plugin directory to the device mounter,
+// used by the plugins written in go.
+func Register(ctx context.Context, dir, endpoint, deviceType string) error {
+	conn, err := grpc.DialContext(ctx, "unix://"+filepath.Join(dir, RegistrationSocket),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = pluginapi.NewRegistrationClient(conn).Register(ctx, &pluginapi.RegisterRequest{
+		Version:    Version,
+		Endpoint:   endpoint,
+		DeviceType: deviceType,
+	})
+	return FromStatusError(err)
+}
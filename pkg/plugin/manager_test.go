@@ -0,0 +1,261 @@
+package plugin
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	pluginapi "github.com/coldzerofear/device-mounter/pkg/api/plugin"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakePlugin struct {
+	pluginapi.UnimplementedDeviceMounterPluginServer
+}
+
+func (p *fakePlugin) ValidateMountRequest(_ context.Context, req *pluginapi.ValidateRequest) (*pluginapi.Empty, error) {
+	resources, err := DecodeResources(req.GetResources())
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+	if quantity := resources["example.com/fpga"]; quantity.Value() > 1 {
+		return nil, ToStatusError(api.NewMounterError(api.ResultCode_Insufficient, "Insufficient fpga"))
+	}
+	return &pluginapi.Empty{}, nil
+}
+
+func (p *fakePlugin) BuildSupportPodTemplates(_ context.Context, req *pluginapi.BuildSupportPodTemplatesRequest) (*pluginapi.PodList, error) {
+	pod, err := DecodePod(req.GetPod())
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+	slavePod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      pod.Name + "-slave",
+		Namespace: pod.Namespace,
+		Labels:    req.GetLabels(),
+	}}
+	pods, err := EncodePods([]*v1.Pod{slavePod})
+	return &pluginapi.PodList{Pods: pods}, ToStatusError(err)
+}
+
+func (p *fakePlugin) GetDeviceInfosToMount(_ context.Context, req *pluginapi.DeviceInfosRequest) (*pluginapi.DeviceInfosResponse, error) {
+	return &pluginapi.DeviceInfosResponse{DeviceInfos: EncodeDeviceInfos([]api.DeviceInfo{{
+		DeviceID:       "fpga-0",
+		DeviceFilePath: "/dev/fpga0",
+		Rule: devices.Rule{
+			Type:        devices.CharDevice,
+			Major:       240,
+			Minor:       int64(len(req.GetSupportPods())),
+			Permissions: "rw",
+			Allow:       true,
+		},
+	}})}, nil
+}
+
+func startFakePlugin(t *testing.T, dir, endpoint string) *grpc.Server {
+	listen, err := net.Listen("unix", filepath.Join(dir, endpoint))
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	pluginapi.RegisterDeviceMounterPluginServer(server, &fakePlugin{})
+	go func() { _ = server.Serve(listen) }()
+	return server
+}
+
+func Test_Manager(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := Register(ctx, dir, "../fpga.sock", "FPGA")
+	assert.IsType(t, &api.MounterError{}, err)
+	err = Register(ctx, dir, "fpga.sock", "MY.FPGA")
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_Invalid, err.(*api.MounterError).Code)
+	}
+	// The plugin socket does not exist.
+	err = Register(ctx, dir, "fpga.sock", "FPGA")
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_NotFound, err.(*api.MounterError).Code)
+	}
+
+	server := startFakePlugin(t, dir, "fpga.sock")
+	assert.NoError(t, Register(ctx, dir, "fpga.sock", "fpga"))
+	mounter, ok := framework.GetDeviceMounter("FPGA")
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "FPGA", mounter.GetDeviceType())
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"}}
+	container := &api.Container{Name: "main"}
+	err = mounter.ValidateMountRequest(ctx, nil, nil, &v1.Node{}, pod, container,
+		map[v1.ResourceName]resource.Quantity{"example.com/fpga": resource.MustParse("2")}, nil, nil)
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_Insufficient, err.(*api.MounterError).Code)
+		assert.Equal(t, "Insufficient fpga", err.Error())
+	}
+
+	slavePods, err := mounter.BuildSupportPodTemplates(ctx, pod, container, nil, nil, map[string]string{"app": "slave"}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, slavePods, 1) {
+		assert.Equal(t, "main-slave", slavePods[0].Name)
+		assert.Equal(t, "slave", slavePods[0].Labels["app"])
+	}
+
+	deviceInfos, err := mounter.GetDeviceInfosToMount(ctx, nil, pod, container, slavePods)
+	assert.NoError(t, err)
+	assert.Equal(t, []api.DeviceInfo{{
+		DeviceID:       "fpga-0",
+		DeviceFilePath: "/dev/fpga0",
+		Rule:           devices.Rule{Type: devices.CharDevice, Major: 240, Minor: 1, Permissions: "rw", Allow: true},
+	}}, deviceInfos)
+
+	// The unimplemented methods return errors instead of crashing the device mounter.
+	_, err = mounter.GetDeviceInfosToUnmount(ctx, nil, pod, container, slavePods)
+	assert.Error(t, err)
+
+	// The plugin is removed once it exits.
+	server.Stop()
+	assert.Eventually(t, func() bool {
+		_, ok := framework.GetDeviceMounter("FPGA")
+		return !ok
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func Test_RegisterUnderscoreDeviceType(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	registered := make(chan string, 1)
+	manager.AddRegisterHandler(func(deviceType string) { registered <- deviceType })
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+	server := startFakePlugin(t, dir, "my-fpga.sock")
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// The device types follow the convention of the built-in ones, e.g. NVIDIA_GPU.
+	assert.NoError(t, Register(ctx, dir, "my-fpga.sock", "my_fpga"))
+	_, ok := framework.GetDeviceMounter("MY_FPGA")
+	assert.True(t, ok)
+	select {
+	case deviceType := <-registered:
+		assert.Equal(t, "MY_FPGA", deviceType)
+	case <-ctx.Done():
+		t.Fatal("register handler not called")
+	}
+	for _, deviceType := range []string{"_FPGA", "FPGA-", "MY.FPGA", "MY/FPGA", strings.Repeat("A", 64)} {
+		err := Register(ctx, dir, "my-fpga.sock", deviceType)
+		if assert.IsType(t, &api.MounterError{}, err, deviceType) {
+			assert.Equal(t, api.ResultCode_Invalid, err.(*api.MounterError).Code)
+		}
+	}
+}
+
+func Test_RegisterBuiltinDeviceType(t *testing.T) {
+	builtin := newPluginMounter("BUILTIN", "", nil)
+	// A registered device mounter that is not a plugin.
+	assert.NoError(t, framework.RegisterDeviceMounter(struct{ framework.DeviceMounter }{builtin}, nil))
+
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+	server := startFakePlugin(t, dir, "builtin.sock")
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := Register(ctx, dir, "builtin.sock", "builtin")
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_Conflict, err.(*api.MounterError).Code)
+	}
+}
+
+func Test_RegisterReservedDeviceType(t *testing.T) {
+	framework.ReserveDeviceTypes("RESERVED")
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+	server := startFakePlugin(t, dir, "reserved.sock")
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// The built-in device type is reserved even if its device mounter is not registered.
+	err := Register(ctx, dir, "reserved.sock", "reserved")
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_Conflict, err.(*api.MounterError).Code)
+	}
+	_, ok := framework.GetDeviceMounter("RESERVED")
+	assert.False(t, ok)
+}
+
+func Test_ReplacePlugin(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+	server := startFakePlugin(t, dir, "dpu.sock")
+	defer server.Stop()
+	other := startFakePlugin(t, dir, "other.sock")
+	defer other.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, Register(ctx, dir, "dpu.sock", "DPU"))
+	registered, _ := framework.GetDeviceMounter("DPU")
+
+	// The plugin of another endpoint can not take over the device type.
+	err := Register(ctx, dir, "other.sock", "DPU")
+	if assert.IsType(t, &api.MounterError{}, err) {
+		assert.Equal(t, api.ResultCode_Conflict, err.(*api.MounterError).Code)
+	}
+	mounter, _ := framework.GetDeviceMounter("DPU")
+	assert.Same(t, registered, mounter)
+
+	// The plugin registering again on the same endpoint replaces the old one.
+	assert.NoError(t, Register(ctx, dir, "dpu.sock", "DPU"))
+	mounter, _ = framework.GetDeviceMounter("DPU")
+	assert.NotSame(t, registered, mounter)
+	assert.Equal(t, connectivity.Shutdown, registered.(*pluginMounter).conn.GetState())
+	manager.lock.Lock()
+	assert.Equal(t, map[string]*pluginMounter{"dpu.sock": mounter.(*pluginMounter)}, manager.plugins)
+	manager.lock.Unlock()
+}
+
+func Test_RegistrationServerOptions(t *testing.T) {
+	dir := t.TempDir()
+	deny := func(context.Context, any, *grpc.UnaryServerInfo, grpc.UnaryHandler) (any, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+	manager := NewManager(dir, grpc.UnaryInterceptor(deny))
+	assert.NoError(t, manager.Start())
+	defer manager.Stop()
+	server := startFakePlugin(t, dir, "denied.sock")
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := Register(ctx, dir, "denied.sock", "DENIED")
+	assert.EqualError(t, err, "device mounter plugin error: denied")
+	_, ok := framework.GetDeviceMounter("DENIED")
+	assert.False(t, ok)
+}
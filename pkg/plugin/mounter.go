@@ -0,0 +1,216 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	pluginapi "github.com/coldzerofear/device-mounter/pkg/api/plugin"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+var _ framework.DeviceMounter = &pluginMounter{}
+
+// pluginMounter Expose the device mounter plugin registered over the unix socket as a framework.DeviceMounter.
+// The plugins use their own kube clients, the kube client of the device mounter is not passed.
+type pluginMounter struct {
+	deviceType string
+	endpoint   string
+	conn       *grpc.ClientConn
+	client     pluginapi.DeviceMounterPluginClient
+}
+
+func newPluginMounter(deviceType, endpoint string, conn *grpc.ClientConn) *pluginMounter {
+	return &pluginMounter{
+		deviceType: deviceType,
+		endpoint:   endpoint,
+		conn:       conn,
+		client:     pluginapi.NewDeviceMounterPluginClient(conn),
+	}
+}
+
+func (m *pluginMounter) GetDeviceType() string {
+	return m.deviceType
+}
+
+func (m *pluginMounter) ValidateMountRequest(ctx context.Context, _ kubernetes.Interface, _ util.CapacityCalculator, node *v1.Node, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string) error {
+	nodeData, err := EncodeNode(node)
+	if err != nil {
+		return err
+	}
+	podData, err := EncodePod(pod)
+	if err != nil {
+		return err
+	}
+	_, err = m.client.ValidateMountRequest(ctx, &pluginapi.ValidateRequest{
+		Node:        nodeData,
+		Pod:         podData,
+		Container:   EncodeContainer(container),
+		Resources:   EncodeResources(resources),
+		Annotations: annotations,
+		Labels:      labels,
+	})
+	return FromStatusError(err)
+}
+
+func (m *pluginMounter) BuildSupportPodTemplates(ctx context.Context, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity, annotations, labels map[string]string, existingSupportPods []*v1.Pod) ([]*v1.Pod, error) {
+	podData, err := EncodePod(pod)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := EncodePods(existingSupportPods)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.BuildSupportPodTemplates(ctx, &pluginapi.BuildSupportPodTemplatesRequest{
+		Pod:                 podData,
+		Container:           EncodeContainer(container),
+		Resources:           EncodeResources(resources),
+		Annotations:         annotations,
+		Labels:              labels,
+		ExistingSupportPods: existing,
+	})
+	if err != nil {
+		return nil, FromStatusError(err)
+	}
+	return DecodePods(resp.GetPods())
+}
+
+func (m *pluginMounter) VerifySupportPodStatus(ctx context.Context, supportPod *v1.Pod) (api.StatusCode, error) {
+	podData, err := EncodePod(supportPod)
+	if err != nil {
+		return api.Fail, err
+	}
+	resp, err := m.client.VerifySupportPodStatus(ctx, &pluginapi.VerifySupportPodStatusRequest{SupportPod: podData})
+	if err != nil {
+		return api.Fail, FromStatusError(err)
+	}
+	return api.StatusCode(resp.GetStatusCode()), nil
+}
+
+func (m *pluginMounter) GetDeviceInfosToMount(ctx context.Context, _ kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error) {
+	req, err := newDeviceInfosRequest(pod, container, supportPods)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.GetDeviceInfosToMount(ctx, req)
+	if err != nil {
+		return nil, FromStatusError(err)
+	}
+	return DecodeDeviceInfos(resp.GetDeviceInfos())
+}
+
+func (m *pluginMounter) ExecutePostMountActions(ctx context.Context, _ kubernetes.Interface, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error {
+	req, err := newPostActionsRequest(config, pod, container, supportPods)
+	if err != nil {
+		return err
+	}
+	_, err = m.client.ExecutePostMountActions(ctx, req)
+	return FromStatusError(err)
+}
+
+func (m *pluginMounter) GetDeviceInfosToUnmount(ctx context.Context, _ kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) ([]api.DeviceInfo, error) {
+	req, err := newDeviceInfosRequest(pod, container, supportPods)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.GetDeviceInfosToUnmount(ctx, req)
+	if err != nil {
+		return nil, FromStatusError(err)
+	}
+	return DecodeDeviceInfos(resp.GetDeviceInfos())
+}
+
+func (m *pluginMounter) GetDevicesActiveProcessIDs(ctx context.Context, containerPids []int, deviceInfos []api.DeviceInfo) ([]int, error) {
+	pids := make([]int64, len(containerPids))
+	for i, pid := range containerPids {
+		pids[i] = int64(pid)
+	}
+	resp, err := m.client.GetDevicesActiveProcessIDs(ctx, &pluginapi.ActiveProcessIDsRequest{
+		ContainerPids: pids,
+		DeviceInfos:   EncodeDeviceInfos(deviceInfos),
+	})
+	if err != nil {
+		return nil, FromStatusError(err)
+	}
+	activePids := make([]int, len(resp.GetPids()))
+	for i, pid := range resp.GetPids() {
+		activePids[i] = int(pid)
+	}
+	return activePids, nil
+}
+
+func (m *pluginMounter) ExecutePostUnmountActions(ctx context.Context, _ kubernetes.Interface, config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) error {
+	req, err := newPostActionsRequest(config, pod, container, supportPods)
+	if err != nil {
+		return err
+	}
+	_, err = m.client.ExecutePostUnmountActions(ctx, req)
+	return FromStatusError(err)
+}
+
+func (m *pluginMounter) GetPodsToCleanup(ctx context.Context, _ kubernetes.Interface, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) []api.ObjectKey {
+	podData, err := EncodePod(pod)
+	if err != nil {
+		klog.ErrorS(err, "Encode pod failed")
+		return nil
+	}
+	supportPodsData, err := EncodePods(supportPods)
+	if err != nil {
+		klog.ErrorS(err, "Encode support pods failed")
+		return nil
+	}
+	resp, err := m.client.GetPodsToCleanup(ctx, &pluginapi.PodsToCleanupRequest{
+		Pod:         podData,
+		Container:   EncodeContainer(container),
+		SupportPods: supportPodsData,
+	})
+	if err != nil {
+		klog.ErrorS(FromStatusError(err), "Get pods to cleanup from plugin failed", "deviceType", m.deviceType)
+		return nil
+	}
+	return DecodeObjectKeys(resp.GetPods())
+}
+
+func newDeviceInfosRequest(pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) (*pluginapi.DeviceInfosRequest, error) {
+	podData, err := EncodePod(pod)
+	if err != nil {
+		return nil, err
+	}
+	supportPodsData, err := EncodePods(supportPods)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginapi.DeviceInfosRequest{
+		Pod:         podData,
+		Container:   EncodeContainer(container),
+		SupportPods: supportPodsData,
+	}, nil
+}
+
+func newPostActionsRequest(config util.Config, pod *v1.Pod, container *api.Container, supportPods []*v1.Pod) (*pluginapi.PostActionsRequest, error) {
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	podData, err := EncodePod(pod)
+	if err != nil {
+		return nil, err
+	}
+	supportPodsData, err := EncodePods(supportPods)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginapi.PostActionsRequest{
+		NsenterConfig: configData,
+		Pod:           podData,
+		Container:     EncodeContainer(container),
+		SupportPods:   supportPodsData,
+	}, nil
+}
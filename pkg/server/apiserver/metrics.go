@@ -0,0 +1,19 @@
+package apiserver
+
+import (
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
+	"github.com/emicklei/go-restful/v3"
+)
+
+// MetricsFilter Record the count and latency of the requests by route operation and status code.
+func MetricsFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	start := time.Now()
+	chain.ProcessFilter(request, response)
+	operation := "unknown"
+	if route := request.SelectedRoute(); route != nil && len(route.Operation()) > 0 {
+		operation = route.Operation()
+	}
+	metrics.ObserveAPIServerRequest(operation, response.StatusCode(), start)
+}
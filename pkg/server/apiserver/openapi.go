@@ -0,0 +1,181 @@
+package apiserver
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+	generatedopenapi "github.com/coldzerofear/device-mounter/pkg/generated/openapi"
+	"github.com/emicklei/go-restful/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/kube-openapi/pkg/builder3"
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/common/restfuladapter"
+	"k8s.io/kube-openapi/pkg/handler"
+	"k8s.io/kube-openapi/pkg/handler3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/util"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	OpenAPIV2Path = "/openapi/v2"
+	OpenAPIV3Path = "/openapi/v3"
+)
+
+var (
+	// The path parameters with regular expressions, e.g. {name:[a-z0-9]*}, which are not recognized by OpenAPI.
+	pathParameterRegexp = regexp.MustCompile(`\{([^}:]+):[^}]*}`)
+
+	// The error responses are written as status, see writeStatus.
+	statusModel = metav1.Status{}
+)
+
+// InstallOpenAPI Serve the OpenAPI v2 document of the web service at /openapi/v2, and the OpenAPI v3
+// document of the device-mounter.io group version at /openapi/v3, the models are the definitions generated
+// by openapi-gen, see make generate. The routes added to the web service later are not documented.
+func InstallOpenAPI(container *restful.Container, ws *restful.WebService) error {
+	swagger, openapi, err := BuildOpenAPISpecs(ws)
+	if err != nil {
+		return err
+	}
+	handler.NewOpenAPIService(swagger).RegisterOpenAPIVersionedService(OpenAPIV2Path, container)
+
+	service := handler3.NewOpenAPIService()
+	service.UpdateGroupVersion("apis/"+v1alpha1.GroupVersion.GroupVersion, openapi)
+	return service.RegisterOpenAPIV3VersionedService(OpenAPIV3Path, &openAPIPathHandler{container})
+}
+
+// BuildOpenAPISpecs Build the OpenAPI v2 document of all routes and the OpenAPI v3 document of the group version.
+func BuildOpenAPISpecs(ws *restful.WebService) (*spec.Swagger, *spec3.OpenAPI, error) {
+	adapter := &restfuladapter.WebServiceAdapter{WebService: ws}
+	getDefinitions := generatedopenapi.GetOpenAPIDefinitions
+	info := &spec.Info{InfoProps: spec.InfoProps{Title: "Device Mounter", Version: v1alpha1.Version}}
+
+	swagger, err := builder.BuildOpenAPISpecFromRoutes([]common.RouteContainer{&openAPIRoutes{adapter, ""}}, &common.Config{
+		Info:              info,
+		GetDefinitions:    getDefinitions,
+		GetDefinitionName: definitionName,
+		PostProcessSpec: func(swagger *spec.Swagger) (*spec.Swagger, error) {
+			definitions := getDefinitions(func(name string) spec.Ref {
+				defName, _ := definitionName(name)
+				return spec.MustCreateRef("#/definitions/" + common.EscapeJsonPointer(defName))
+			})
+			statusSchema := addStatusDefinitions(definitions, func(name string, schema spec.Schema) {
+				swagger.Definitions[name] = schema
+			}, "#/definitions/")
+			for _, item := range swagger.Paths.Paths {
+				for _, op := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+					if op != nil && op.Responses.Default == nil {
+						op.Responses.Default = &spec.Response{ResponseProps: spec.ResponseProps{
+							Description: "Status", Schema: statusSchema}}
+					}
+				}
+			}
+			return swagger, nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefix := "/apis/" + v1alpha1.GroupVersion.GroupVersion
+	openapi, err := builder3.BuildOpenAPISpecFromRoutes([]common.RouteContainer{&openAPIRoutes{adapter, prefix}}, &common.OpenAPIV3Config{
+		Info:              info,
+		GetDefinitions:    getDefinitions,
+		GetDefinitionName: definitionName,
+		PostProcessSpec: func(openapi *spec3.OpenAPI) (*spec3.OpenAPI, error) {
+			definitions := getDefinitions(func(name string) spec.Ref {
+				defName, _ := definitionName(name)
+				return spec.MustCreateRef("#/components/schemas/" + common.EscapeJsonPointer(defName))
+			})
+			statusSchema := addStatusDefinitions(definitions, func(name string, schema spec.Schema) {
+				openapi.Components.Schemas[name] = &schema
+			}, "#/components/schemas/")
+			for _, item := range openapi.Paths.Paths {
+				for _, op := range []*spec3.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+					if op != nil && op.Responses.Default == nil {
+						op.Responses.Default = &spec3.Response{ResponseProps: spec3.ResponseProps{
+							Description: "Status",
+							Content: map[string]*spec3.MediaType{restful.MIME_JSON: {
+								MediaTypeProps: spec3.MediaTypeProps{Schema: statusSchema}}},
+						}}
+					}
+				}
+			}
+			return openapi, nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return swagger, openapi, nil
+}
+
+// addStatusDefinitions Add the definitions of the status and the types it references,
+// which are not referenced by the routes, and return the schema referencing the status.
+func addStatusDefinitions(definitions map[string]common.OpenAPIDefinition,
+	add func(name string, schema spec.Schema), refPrefix string) *spec.Schema {
+
+	statusName := util.GetCanonicalTypeName(statusModel)
+	names := sets.New[string]()
+	var walk func(name string)
+	walk = func(name string) {
+		if names.Has(name) {
+			return
+		}
+		names.Insert(name)
+		defName, _ := definitionName(name)
+		add(defName, definitions[name].Schema)
+		for _, dependency := range definitions[name].Dependencies {
+			walk(dependency)
+		}
+	}
+	walk(statusName)
+	defName, _ := definitionName(statusName)
+	return spec.RefSchema(refPrefix + common.EscapeJsonPointer(defName))
+}
+
+// definitionName Name the definitions by the reversed package path, e.g. io.k8s.apimachinery.pkg.apis.meta.v1.Status.
+func definitionName(name string) (string, spec.Extensions) {
+	return util.ToRESTFriendlyName(name), nil
+}
+
+type openAPIPathHandler struct {
+	*restful.Container
+}
+
+func (h *openAPIPathHandler) HandlePrefix(path string, handler http.Handler) {
+	// The patterns ending with a slash match all the paths with the prefix.
+	h.Handle(path, handler)
+}
+
+// openAPIRoutes The routes of the web service under the path prefix.
+type openAPIRoutes struct {
+	*restfuladapter.WebServiceAdapter
+	prefix string
+}
+
+func (c *openAPIRoutes) Routes() []common.Route {
+	var routes []common.Route
+	for _, route := range c.WebServiceAdapter.Routes() {
+		path := pathParameterRegexp.ReplaceAllString(route.Path(), "{$1}")
+		if !strings.HasPrefix(path, c.prefix) {
+			continue
+		}
+		routes = append(routes, &openAPIRoute{Route: route, path: path})
+	}
+	return routes
+}
+
+type openAPIRoute struct {
+	common.Route
+	path string
+}
+
+func (r *openAPIRoute) Path() string {
+	return r.path
+}
@@ -0,0 +1,116 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func newOpenAPITestWebService() *restful.WebService {
+	noop := func(*restful.Request, *restful.Response) {}
+	ws := new(restful.WebService)
+	ws.Route(ws.PUT("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/pods/{name:[a-z0-9][a-z0-9\\-]*}/mount").
+		To(noop).Operation("MountDevices").Consumes(restful.MIME_JSON).
+		Reads(RequestMountBody{}).
+		Param(ws.PathParameter("namespace", "").Required(true)).
+		Param(ws.PathParameter("name", "").Required(true)).
+		Param(ws.QueryParameter("wait_second", "").DataType("integer")).
+		Returns(http.StatusOK, "OK", DeviceResult{}))
+	ws.Route(ws.GET("/apis/"+v1alpha1.GroupVersion.GroupVersion+"/namespaces/{namespace}/pods/{name}/operations/{id}").
+		To(noop).Operation("GetOperation").Produces(restful.MIME_JSON).
+		Param(ws.PathParameter("namespace", "").Required(true)).
+		Param(ws.PathParameter("name", "").Required(true)).
+		Param(ws.PathParameter("id", "").Required(true)).
+		Returns(http.StatusOK, "OK", OperationResult{}))
+	ws.Route(ws.GET("/apis").To(noop).Operation("GetAPIGroupList").Produces(restful.MIME_JSON).
+		Returns(http.StatusOK, "OK", metav1.APIGroupList{}).
+		Returns(http.StatusNotFound, "NotFound", ""))
+	return ws
+}
+
+func Test_BuildOpenAPISpecs(t *testing.T) {
+	swagger, openapi, err := BuildOpenAPISpecs(newOpenAPITestWebService())
+	assert.NoError(t, err)
+
+	mountPath := "/apis/device-mounter.io/v1alpha1/namespaces/{namespace}/pods/{name}/mount"
+	assert.Contains(t, swagger.Paths.Paths, mountPath)
+	assert.Contains(t, swagger.Paths.Paths, "/apis")
+
+	bodyName := "com.github.coldzerofear.device-mounter.pkg.server.apiserver.RequestMountBody"
+	mount := swagger.Paths.Paths[mountPath].Put
+	var body *spec.Parameter
+	for i := range mount.Parameters {
+		if mount.Parameters[i].In == "body" {
+			body = &mount.Parameters[i]
+		}
+	}
+	if assert.NotNil(t, body) {
+		assert.Equal(t, "#/definitions/"+bodyName, body.Schema.Ref.String())
+	}
+	assert.Equal(t, []string{"resources"}, swagger.Definitions[bodyName].Required)
+	assert.Equal(t, "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.Status", mount.Responses.Default.Schema.Ref.String())
+	assert.Contains(t, swagger.Definitions, "io.k8s.apimachinery.pkg.apis.meta.v1.StatusDetails")
+
+	result := swagger.Definitions["com.github.coldzerofear.device-mounter.pkg.server.apiserver.DeviceResult"]
+	assert.Equal(t, "#/definitions/com.github.coldzerofear.device-mounter.pkg.api.v1alpha1.MountedDevice",
+		result.Properties["devices"].Items.Schema.Ref.String())
+	assert.Equal(t, "#/definitions/io.k8s.apimachinery.pkg.runtime.RawExtension",
+		result.Properties["plannedSlavePods"].Items.Schema.Ref.String())
+	assert.Contains(t, swagger.Definitions, "io.k8s.apimachinery.pkg.runtime.RawExtension")
+	operation := swagger.Definitions["com.github.coldzerofear.device-mounter.pkg.server.apiserver.OperationResult"]
+	startTime := operation.Properties["startTime"]
+	assert.Equal(t, "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.Time", startTime.Ref.String())
+	assert.Equal(t, spec.StringOrArray{"string"}, swagger.Definitions["io.k8s.apimachinery.pkg.apis.meta.v1.Time"].Type)
+	assert.Equal(t, "date-time", swagger.Definitions["io.k8s.apimachinery.pkg.apis.meta.v1.Time"].Format)
+
+	// The v3 document only contains the routes of the group version.
+	assert.Contains(t, openapi.Paths.Paths, mountPath)
+	assert.NotContains(t, openapi.Paths.Paths, "/apis")
+	assert.Contains(t, openapi.Components.Schemas, bodyName)
+	assert.Contains(t, openapi.Components.Schemas, "io.k8s.apimachinery.pkg.apis.meta.v1.Status")
+}
+
+func Test_InstallOpenAPI(t *testing.T) {
+	container := restful.NewContainer()
+	ws := newOpenAPITestWebService()
+	container.Add(ws)
+	assert.NoError(t, InstallOpenAPI(container, ws))
+	server := httptest.NewServer(container)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + OpenAPIV2Path)
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		swagger := spec.Swagger{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&swagger))
+		assert.Equal(t, "2.0", swagger.Swagger)
+	}
+
+	resp, err = http.Get(server.URL + OpenAPIV3Path)
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		discovery := struct {
+			Paths map[string]interface{} `json:"paths"`
+		}{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&discovery))
+		assert.Contains(t, discovery.Paths, "apis/device-mounter.io/v1alpha1")
+	}
+
+	resp, err = http.Get(server.URL + OpenAPIV3Path + "/apis/device-mounter.io/v1alpha1")
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		openapi := spec3.OpenAPI{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&openapi))
+		assert.Equal(t, "3.0.0", openapi.Version)
+	}
+}
@@ -0,0 +1,139 @@
+package apiserver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+type mounterConn struct {
+	podUID types.UID
+	podIP  string
+	conn   *grpc.ClientConn
+	// The number of requests using the connection, the removed connection is closed by the last one.
+	refs    int
+	removed bool
+}
+
+// mounterPool Keep a gRPC connection to the device mounter of each node, which is shared by the requests.
+type mounterPool struct {
+	lock  sync.Mutex
+	conns map[string]*mounterConn
+	dial  func(target string) (*grpc.ClientConn, error)
+}
+
+func newMounterPool(port string, mounterTLS *MounterTLS) *mounterPool {
+	return &mounterPool{
+		conns: make(map[string]*mounterConn),
+		dial: func(target string) (*grpc.ClientConn, error) {
+			transportCredentials := insecure.NewCredentials()
+			if mounterTLS != nil {
+				tlsConfig, err := mounterTLS.Watch.GetClientConfig(mounterTLS.ServerName)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load mounter client certificate: %w", err)
+				}
+				transportCredentials = credentials.NewTLS(tlsConfig)
+			}
+			return grpc.Dial(target+port,
+				grpc.WithTransportCredentials(transportCredentials), grpc.WithTimeout(5*time.Second))
+		},
+	}
+}
+
+// Get Get the connection to the device mounter pod, reconnect when the pod has been
+// recreated, its IP has changed or the connection has failed.
+// The returned function must be called when the request no longer uses the connection.
+func (p *mounterPool) Get(mounterPod *v1.Pod) (*grpc.ClientConn, func(), error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	nodeName := mounterPod.Spec.NodeName
+	if c, ok := p.conns[nodeName]; ok {
+		if c.podUID == mounterPod.UID && c.podIP == mounterPod.Status.PodIP && isHealthy(c.conn) {
+			c.refs++
+			return c.conn, p.releaseFunc(c), nil
+		}
+		klog.V(4).Infoln("Reconnect to device mounter", "node", nodeName, "pod", mounterPod.Name, "podIP", mounterPod.Status.PodIP)
+		p.remove(nodeName, c)
+	}
+	conn, err := p.dial(mounterPod.Status.PodIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &mounterConn{
+		podUID: mounterPod.UID,
+		podIP:  mounterPod.Status.PodIP,
+		conn:   conn,
+		refs:   1,
+	}
+	p.conns[nodeName] = c
+	return conn, p.releaseFunc(c), nil
+}
+
+func (p *mounterPool) releaseFunc(c *mounterConn) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			c.refs--
+			if c.removed && c.refs == 0 {
+				_ = c.conn.Close()
+			}
+		})
+	}
+}
+
+// remove Remove the connection from the pool, it is closed after the running requests finish.
+func (p *mounterPool) remove(nodeName string, c *mounterConn) {
+	delete(p.conns, nodeName)
+	c.removed = true
+	if c.refs == 0 {
+		_ = c.conn.Close()
+	}
+}
+
+func isHealthy(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
+}
+
+// Remove Close the connection to the device mounter pod once it is no longer used.
+func (p *mounterPool) Remove(mounterPod *v1.Pod) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	nodeName := mounterPod.Spec.NodeName
+	if c, ok := p.conns[nodeName]; ok && c.podUID == mounterPod.UID {
+		p.remove(nodeName, c)
+	}
+}
+
+// ResourceEventHandler Release the connections of the deleted or changed device mounter pods.
+func (p *mounterPool) ResourceEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok1 := oldObj.(*v1.Pod)
+			newPod, ok2 := newObj.(*v1.Pod)
+			if ok1 && ok2 && (oldPod.Status.PodIP != newPod.Status.PodIP || oldPod.Spec.NodeName != newPod.Spec.NodeName) {
+				p.Remove(oldPod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*v1.Pod); ok {
+				p.Remove(pod)
+			}
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newMounterPod(name, nodeName, podIP string, uid types.UID) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			UID:       uid,
+			Labels:    map[string]string{"app": "device-mounter"},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			PodIP:             podIP,
+			ContainerStatuses: []v1.ContainerStatus{{Ready: true}},
+		},
+	}
+}
+
+func Test_GetMounterPodOnNodeName(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{NodeNameIndex: indexByNodeName})
+	_ = indexer.Add(newMounterPod("mounter-a", "node-a", "10.0.0.1", "a"))
+	other := newMounterPod("other-b", "node-b", "10.0.0.2", "b")
+	other.Labels = nil
+	_ = indexer.Add(other)
+	s := &service{
+		mounterSelector: &mounterSelector{
+			targetNamespace: "kube-system",
+			labelSelector:   labels.SelectorFromSet(labels.Set{"app": "device-mounter"}),
+		},
+		mounterIndexer: indexer,
+	}
+
+	pod, err := s.GetMounterPodOnNodeName("node-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "mounter-a", pod.Name)
+
+	// The pods not matching the selector are ignored.
+	_, err = s.GetMounterPodOnNodeName("node-b")
+	assert.EqualError(t, err, "there is no device mounter on the target node node-b")
+}
+
+func Test_MounterPool(t *testing.T) {
+	var dialed []string
+	pool := &mounterPool{
+		conns: make(map[string]*mounterConn),
+		dial: func(target string) (*grpc.ClientConn, error) {
+			dialed = append(dialed, target)
+			return grpc.Dial("passthrough:///"+target+":1200", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		},
+	}
+	mounterPod := newMounterPod("mounter-a", "node-a", "10.0.0.1", "a")
+	conn, release, err := pool.Get(mounterPod)
+	assert.NoError(t, err)
+	again, releaseAgain, err := pool.Get(mounterPod)
+	assert.NoError(t, err)
+	assert.Same(t, conn, again)
+	releaseAgain()
+	releaseAgain()
+
+	// Reconnect when the mounter pod is recreated with another IP,
+	// the old connection is closed after the running request releases it.
+	recreated := newMounterPod("mounter-b", "node-a", "10.0.0.9", "b")
+	newConn, releaseNew, err := pool.Get(recreated)
+	assert.NoError(t, err)
+	assert.NotSame(t, conn, newConn)
+	assert.NotEqual(t, connectivity.Shutdown, conn.GetState())
+	release()
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.9"}, dialed)
+
+	// The deletion of the old pod does not close the connection of the new pod.
+	pool.ResourceEventHandler().OnDelete(mounterPod)
+	assert.Len(t, pool.conns, 1)
+	pool.ResourceEventHandler().OnDelete(recreated)
+	assert.Empty(t, pool.conns)
+	assert.NotEqual(t, connectivity.Shutdown, newConn.GetState())
+	releaseNew()
+	assert.Equal(t, connectivity.Shutdown, newConn.GetState())
+
+	// The idle connection is closed immediately.
+	idleConn, releaseIdle, err := pool.Get(recreated)
+	assert.NoError(t, err)
+	releaseIdle()
+	pool.Remove(recreated)
+	assert.Equal(t, connectivity.Shutdown, idleConn.GetState())
+}
@@ -1,6 +1,7 @@
 package apiserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,18 +13,23 @@ import (
 
 	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/authConfig"
+	"github.com/coldzerofear/device-mounter/pkg/tlsconfig"
 	"github.com/emicklei/go-restful/v3"
-	"google.golang.org/grpc"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
 
 type APIService interface {
 	MountDevice(request *restful.Request, response *restful.Response)
 	UnMountDevice(request *restful.Request, response *restful.Response)
+	ListMountedDevices(request *restful.Request, response *restful.Response)
+	GetOperation(request *restful.Request, response *restful.Response)
+	CancelOperation(request *restful.Request, response *restful.Response)
 }
 
 type mounterSelector struct {
@@ -32,25 +38,52 @@ type mounterSelector struct {
 	labelSelector    labels.Selector
 }
 
+// MounterTLS The mutual TLS config of the connections to the device mounters.
+type MounterTLS struct {
+	Watch tlsconfig.MutualWatch
+	// The name that the certificates of the device mounters are issued to.
+	ServerName string
+}
+
 type service struct {
 	*mounterSelector
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	podLister  listerv1.PodLister
 	authConfig authConfig.Reader
+	// Whether to authorize the device types of the requests with SubjectAccessReview.
+	authorizeDeviceTypes bool
+	// The device mounter pods indexed by node name.
+	mounterIndexer cache.Indexer
+	pool           *mounterPool
 }
 
-func NewService(kubeClient *kubernetes.Clientset, authConfig authConfig.Reader,
-	mounterPort, mounterNamespace string, mounterLabelSelector labels.Selector) (APIService, error) {
+// NewService Create the service, the pod informer caches the target pods and the mounter informer
+// caches the device mounter pods, which must be started after the service is created.
+func NewService(kubeClient kubernetes.Interface, podInformer, mounterInformer cache.SharedIndexInformer,
+	authConfig authConfig.Reader, authorizeDeviceTypes bool, mounterPort, mounterNamespace string,
+	mounterLabelSelector labels.Selector, mounterTLS *MounterTLS) (APIService, error) {
 	if mounterLabelSelector == nil || mounterLabelSelector.Empty() {
 		return nil, fmt.Errorf("The label selector of the device mounter cannot be empty")
 	}
+	if err := mounterInformer.AddIndexers(cache.Indexers{NodeNameIndex: indexByNodeName}); err != nil {
+		return nil, err
+	}
+	pool := newMounterPool(mounterPort, mounterTLS)
+	if _, err := mounterInformer.AddEventHandler(pool.ResourceEventHandler()); err != nil {
+		return nil, err
+	}
 	return &service{
 		mounterSelector: &mounterSelector{
 			targetServerPort: mounterPort,
 			targetNamespace:  mounterNamespace,
 			labelSelector:    mounterLabelSelector,
 		},
-		kubeClient: kubeClient,
-		authConfig: authConfig,
+		kubeClient:           kubeClient,
+		podLister:            listerv1.NewPodLister(podInformer.GetIndexer()),
+		mounterIndexer:       mounterInformer.GetIndexer(),
+		pool:                 pool,
+		authConfig:           authConfig,
+		authorizeDeviceTypes: authorizeDeviceTypes,
 	}, nil
 }
 
@@ -58,38 +91,36 @@ func (s *service) MountDevice(request *restful.Request, response *restful.Respon
 	klog.Infoln("Call MountDevice")
 	params, err := readMountRequestParameters(request)
 	if err != nil {
-		_ = response.WriteError(http.StatusBadRequest, err)
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
 		return
 	}
 	klog.V(4).Infoln("Request parameters", params)
 
-	if err := s.check(request); err != nil {
-		_ = response.WriteError(http.StatusBadRequest, err)
+	user, err := s.check(request)
+	if err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
 		return
 	}
-	pod, err := s.kubeClient.CoreV1().Pods(params.namespace).
-		Get(context.TODO(), params.name, metav1.GetOptions{
-			ResourceVersion: "0",
-		})
+	if err = s.authorize(request.Request.Context(), user, params.namespace, VerbMount, params.deviceType); err != nil {
+		writeStatusError(response, err)
+		return
+	}
+	pod, err := s.getTargetPod(request.Request.Context(), params.namespace, params.name)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			_ = response.WriteError(http.StatusNotFound, fmt.Errorf("target pod does not exist: %w", err))
-		} else {
-			_ = response.WriteError(http.StatusInternalServerError, fmt.Errorf("error getting Pod: %w", err))
-		}
+		writeStatusError(response, err)
 		return
 	}
 	mPod, err := s.GetMounterPodOnNodeName(pod.Spec.NodeName)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, err)
+		writeStatus(response, &errors.NewServiceUnavailable(err.Error()).ErrStatus)
 		return
 	}
-	conn, err := grpc.Dial(mPod.Status.PodIP+s.targetServerPort, grpc.WithInsecure(), grpc.WithTimeout(5*time.Second))
+	conn, release, err := s.pool.Get(mPod)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, fmt.Errorf("failed to connect to device mounter: %v", err))
+		writeStatus(response, &errors.NewServiceUnavailable(fmt.Sprintf("failed to connect to device mounter: %v", err)).ErrStatus)
 		return
 	}
-	defer conn.Close()
+	defer release()
 
 	var cont *api.Container
 	if len(params.container) > 0 {
@@ -107,20 +138,23 @@ func (s *service) MountDevice(request *restful.Request, response *restful.Respon
 		Container:    cont,
 		DeviceType:   params.deviceType,
 		Patches:      params.Patches,
+		DryRun:       params.dryRun,
+		Async:        params.async,
+		RequestId:    params.requestID,
 	}
 	timeout := time.Duration(params.timeoutSeconds) * time.Second
 	ctx, cancelFunc := context.WithTimeout(request.Request.Context(), timeout)
 	defer cancelFunc()
 	resp, err := client.MountDevice(ctx, &req)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, err)
+		writeGRPCError(response, err, params.name)
 		return
 	}
 
 	if resp.Result == api.ResultCode_Success {
-		_, _ = response.Write([]byte(resp.Message))
+		writeDeviceResult(response, resp)
 	} else {
-		_ = response.WriteError(http.StatusBadRequest, fmt.Errorf(resp.Message))
+		writeResultError(response, resp.Result, resp.Message, params.name)
 	}
 }
 
@@ -139,6 +173,41 @@ func getWaitTimeoutSecond(request *restful.Request) (int64, error) {
 	return timeout, nil
 }
 
+// getDryRun Read the dryRun query parameter, consistent with kubernetes only "All" is supported.
+func getDryRun(request *restful.Request) (bool, error) {
+	switch dryRun := request.QueryParameter("dryRun"); dryRun {
+	case "":
+		return false, nil
+	case metav1.DryRunAll:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid dryRun value %q, supported values: %q", dryRun, metav1.DryRunAll)
+	}
+}
+
+// getAsync Read the async query parameter.
+func getAsync(request *restful.Request) bool {
+	return strings.ToLower(request.QueryParameter("async")) == "true"
+}
+
+// getRequestID Read the idempotency key from the request_id query parameter or the Idempotency-Key header.
+func getRequestID(request *restful.Request) string {
+	if requestID := strings.TrimSpace(request.QueryParameter("request_id")); requestID != "" {
+		return requestID
+	}
+	return strings.TrimSpace(request.HeaderParameter(IdempotencyKeyHeader))
+}
+
+// writeDeviceResult Write the result of the mount or unmount request,
+// the accepted asynchronous operation responds with 202.
+func writeDeviceResult(response *restful.Response, resp *api.DeviceResponse) {
+	if len(resp.GetOperationId()) > 0 {
+		_ = response.WriteHeaderAndJson(http.StatusAccepted, newDeviceResult(resp), restful.MIME_JSON)
+	} else {
+		_ = response.WriteAsJson(newDeviceResult(resp))
+	}
+}
+
 func readMountRequestParameters(request *restful.Request) (*requestMountParams, error) {
 	namespace := strings.TrimSpace(request.PathParameter("namespace"))
 	name := strings.TrimSpace(request.PathParameter("name"))
@@ -154,13 +223,17 @@ func readMountRequestParameters(request *restful.Request) (*requestMountParams,
 	if err != nil {
 		return nil, err
 	}
+	dryRun, err := getDryRun(request)
+	if err != nil {
+		return nil, err
+	}
 	body, err := io.ReadAll(request.Request.Body)
 	if err != nil {
 		return nil, err
 	}
 	defer request.Request.Body.Close()
 
-	reqBody := requestMountBody{}
+	reqBody := RequestMountBody{}
 	if err = json.Unmarshal(body, &reqBody); err != nil {
 		return nil, err
 	}
@@ -169,8 +242,11 @@ func readMountRequestParameters(request *restful.Request) (*requestMountParams,
 		namespace:        namespace,
 		container:        container,
 		deviceType:       devType,
-		requestMountBody: reqBody,
+		RequestMountBody: reqBody,
 		timeoutSeconds:   uint32(timeout),
+		dryRun:           dryRun,
+		async:            getAsync(request),
+		requestID:        getRequestID(request),
 	}, nil
 }
 
@@ -194,13 +270,29 @@ func readUnMountRequestParameters(request *restful.Request) (*requestUnMountPara
 	if strings.ToLower(forceStr) == "true" {
 		force = true
 	}
+	body, err := io.ReadAll(request.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer request.Request.Body.Close()
+
+	// The request body is optional, all devices of the type are unmounted without it.
+	reqBody := RequestUnMountBody{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err = json.Unmarshal(body, &reqBody); err != nil {
+			return nil, err
+		}
+	}
 	return &requestUnMountParams{
-		name:           name,
-		namespace:      namespace,
-		container:      container,
-		deviceType:     devType,
-		timeoutSeconds: uint32(timeout),
-		force:          force,
+		name:               name,
+		namespace:          namespace,
+		container:          container,
+		deviceType:         devType,
+		RequestUnMountBody: reqBody,
+		timeoutSeconds:     uint32(timeout),
+		force:              force,
+		async:              getAsync(request),
+		requestID:          getRequestID(request),
 	}, nil
 }
 
@@ -209,38 +301,42 @@ func (s *service) UnMountDevice(request *restful.Request, response *restful.Resp
 
 	params, err := readUnMountRequestParameters(request)
 	if err != nil {
-		_ = response.WriteError(http.StatusBadRequest, err)
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
 		return
 	}
 	klog.V(4).Infoln("Request parameters", params)
-	if err := s.check(request); err != nil {
-		_ = response.WriteError(http.StatusBadRequest, err)
+	user, err := s.check(request)
+	if err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
 		return
 	}
-	pod, err := s.kubeClient.CoreV1().Pods(params.namespace).
-		Get(context.TODO(), params.name, metav1.GetOptions{
-			ResourceVersion: "0",
-		})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			_ = response.WriteError(http.StatusNotFound, fmt.Errorf("target pod does not exist: %w", err))
-		} else {
-			_ = response.WriteError(http.StatusInternalServerError, fmt.Errorf("error getting Pod: %w", err))
+	if err = s.authorize(request.Request.Context(), user, params.namespace, VerbUnMount, params.deviceType); err != nil {
+		writeStatusError(response, err)
+		return
+	}
+	if params.force {
+		if err = s.authorize(request.Request.Context(), user, params.namespace, VerbForceUnMount, params.deviceType); err != nil {
+			writeStatusError(response, err)
+			return
 		}
+	}
+	pod, err := s.getTargetPod(request.Request.Context(), params.namespace, params.name)
+	if err != nil {
+		writeStatusError(response, err)
 		return
 	}
 	mPod, err := s.GetMounterPodOnNodeName(pod.Spec.NodeName)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, err)
+		writeStatus(response, &errors.NewServiceUnavailable(err.Error()).ErrStatus)
 		return
 	}
 
-	conn, err := grpc.Dial(mPod.Status.PodIP+s.targetServerPort, grpc.WithInsecure(), grpc.WithTimeout(5*time.Second))
+	conn, release, err := s.pool.Get(mPod)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, fmt.Errorf("failed to connect to device mounter: %v", err))
+		writeStatus(response, &errors.NewServiceUnavailable(fmt.Sprintf("failed to connect to device mounter: %v", err)).ErrStatus)
 		return
 	}
-	defer conn.Close()
+	defer release()
 
 	var cont *api.Container
 	if len(params.container) > 0 {
@@ -253,18 +349,195 @@ func (s *service) UnMountDevice(request *restful.Request, response *restful.Resp
 		Container:    cont,
 		Force:        params.force,
 		DeviceType:   params.deviceType,
+		Async:        params.async,
+		RequestId:    params.requestID,
+		Resources:    params.Resources,
 	}
 	timeout := time.Duration(params.timeoutSeconds) * time.Second
 	ctx, cancelFunc := context.WithTimeout(request.Request.Context(), timeout)
 	defer cancelFunc()
 	resp, err := client.UnMountDevice(ctx, &req)
 	if err != nil {
-		_ = response.WriteError(http.StatusInternalServerError, err)
+		writeGRPCError(response, err, params.name)
+		return
+	}
+	if resp.Result == api.ResultCode_Success {
+		writeDeviceResult(response, resp)
+	} else {
+		writeResultError(response, resp.Result, resp.Message, params.name)
+	}
+}
+
+func readListRequestParameters(request *restful.Request) (*requestListParams, error) {
+	namespace := strings.TrimSpace(request.PathParameter("namespace"))
+	name := strings.TrimSpace(request.PathParameter("name"))
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("namespace and name parameters are required")
+	}
+	container := strings.TrimSpace(request.QueryParameter("container"))
+	devType := strings.TrimSpace(request.QueryParameter("device_type"))
+	timeout, err := getWaitTimeoutSecond(request)
+	if err != nil {
+		return nil, err
+	}
+	return &requestListParams{
+		name:           name,
+		namespace:      namespace,
+		container:      container,
+		deviceType:     devType,
+		timeoutSeconds: uint32(timeout),
+	}, nil
+}
+
+func (s *service) ListMountedDevices(request *restful.Request, response *restful.Response) {
+	klog.Infoln("Call ListMountedDevices")
+
+	params, err := readListRequestParameters(request)
+	if err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
+		return
+	}
+	klog.V(4).Infoln("Request parameters", params)
+	if _, err := s.check(request); err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
+		return
+	}
+	pod, err := s.getTargetPod(request.Request.Context(), params.namespace, params.name)
+	if err != nil {
+		writeStatusError(response, err)
+		return
+	}
+	mPod, err := s.GetMounterPodOnNodeName(pod.Spec.NodeName)
+	if err != nil {
+		writeStatus(response, &errors.NewServiceUnavailable(err.Error()).ErrStatus)
+		return
+	}
+
+	conn, release, err := s.pool.Get(mPod)
+	if err != nil {
+		writeStatus(response, &errors.NewServiceUnavailable(fmt.Sprintf("failed to connect to device mounter: %v", err)).ErrStatus)
+		return
+	}
+	defer release()
+
+	var cont *api.Container
+	if len(params.container) > 0 {
+		cont = &api.Container{Name: params.container}
+	}
+	client := api.NewDeviceMountServiceClient(conn)
+	req := api.ListMountedDevicesRequest{
+		PodName:      params.name,
+		PodNamespace: params.namespace,
+		Container:    cont,
+		DeviceType:   params.deviceType,
+	}
+	timeout := time.Duration(params.timeoutSeconds) * time.Second
+	ctx, cancelFunc := context.WithTimeout(request.Request.Context(), timeout)
+	defer cancelFunc()
+	resp, err := client.ListMountedDevices(ctx, &req)
+	if err != nil {
+		writeGRPCError(response, err, params.name)
 		return
 	}
 	if resp.Result == api.ResultCode_Success {
-		_, _ = response.Write([]byte(resp.Message))
+		_ = response.WriteAsJson(newMountedDeviceList(resp.GetItems()))
 	} else {
-		_ = response.WriteError(http.StatusBadRequest, fmt.Errorf(resp.Message))
+		writeResultError(response, resp.Result, resp.Message, params.name)
+	}
+}
+
+func readOperationRequestParameters(request *restful.Request) (*requestOperationParams, error) {
+	namespace := strings.TrimSpace(request.PathParameter("namespace"))
+	name := strings.TrimSpace(request.PathParameter("name"))
+	id := strings.TrimSpace(request.PathParameter("id"))
+	if namespace == "" || name == "" || id == "" {
+		return nil, fmt.Errorf("namespace, name and id parameters are required")
+	}
+	timeout, err := getWaitTimeoutSecond(request)
+	if err != nil {
+		return nil, err
+	}
+	return &requestOperationParams{
+		name:           name,
+		namespace:      namespace,
+		operationID:    id,
+		timeoutSeconds: uint32(timeout),
+	}, nil
+}
+
+func (s *service) GetOperation(request *restful.Request, response *restful.Response) {
+	klog.Infoln("Call GetOperation")
+	s.callOperation(request, response, false)
+}
+
+func (s *service) CancelOperation(request *restful.Request, response *restful.Response) {
+	klog.Infoln("Call CancelOperation")
+	s.callOperation(request, response, true)
+}
+
+// callOperation Forward the operation request to the device mounter on the node of the target pod.
+// The operation is looked up first to authorize the caller for its device type like the mount and unmount requests.
+func (s *service) callOperation(request *restful.Request, response *restful.Response, cancel bool) {
+	params, err := readOperationRequestParameters(request)
+	if err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
+		return
+	}
+	klog.V(4).Infoln("Request parameters", params)
+	user, err := s.check(request)
+	if err != nil {
+		writeStatus(response, &errors.NewBadRequest(err.Error()).ErrStatus)
+		return
+	}
+	pod, err := s.getTargetPod(request.Request.Context(), params.namespace, params.name)
+	if err != nil {
+		writeStatusError(response, err)
+		return
+	}
+	mPod, err := s.GetMounterPodOnNodeName(pod.Spec.NodeName)
+	if err != nil {
+		writeStatus(response, &errors.NewServiceUnavailable(err.Error()).ErrStatus)
+		return
+	}
+
+	conn, release, err := s.pool.Get(mPod)
+	if err != nil {
+		writeStatus(response, &errors.NewServiceUnavailable(fmt.Sprintf("failed to connect to device mounter: %v", err)).ErrStatus)
+		return
+	}
+	defer release()
+
+	client := api.NewDeviceMountServiceClient(conn)
+	req := api.OperationRequest{
+		PodName:      params.name,
+		PodNamespace: params.namespace,
+		OperationId:  params.operationID,
+	}
+	timeout := time.Duration(params.timeoutSeconds) * time.Second
+	ctx, cancelFunc := context.WithTimeout(request.Request.Context(), timeout)
+	defer cancelFunc()
+	resp, err := client.GetOperation(ctx, &req)
+	if err == nil && resp.Result == api.ResultCode_Success {
+		operation := resp.GetOperation()
+		if err = s.authorize(ctx, user, params.namespace, operationVerb(operation.GetType()), operation.GetDeviceType()); err != nil {
+			writeStatusError(response, err)
+			return
+		}
+		if cancel {
+			resp, err = client.CancelOperation(ctx, &req)
+		}
+	}
+	if err != nil {
+		writeGRPCError(response, err, params.name)
+		return
+	}
+	switch resp.Result {
+	case api.ResultCode_Success:
+		_ = response.WriteAsJson(newOperationResult(resp.GetOperation()))
+	case api.ResultCode_Invalid:
+		// The operation has already finished.
+		writeResultError(response, api.ResultCode_Conflict, resp.Message, params.name)
+	default:
+		writeResultError(response, resp.Result, resp.Message, params.name)
 	}
 }
@@ -0,0 +1,134 @@
+package apiserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/authConfig"
+	authfake "github.com/coldzerofear/device-mounter/pkg/authConfig/fake"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	authzv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeOperationMounter struct {
+	api.UnimplementedDeviceMountServiceServer
+	cancelled int
+}
+
+func (m *fakeOperationMounter) GetOperation(_ context.Context, req *api.OperationRequest) (*api.OperationResponse, error) {
+	return &api.OperationResponse{Result: api.ResultCode_Success, Operation: &api.Operation{
+		Id: req.OperationId, Type: "Mount", PodName: req.PodName, PodNamespace: req.PodNamespace,
+		Container: "main", DeviceType: "NVIDIA_GPU", State: api.OperationState_Running,
+	}}, nil
+}
+
+func (m *fakeOperationMounter) CancelOperation(ctx context.Context, req *api.OperationRequest) (*api.OperationResponse, error) {
+	m.cancelled++
+	return m.GetOperation(ctx, req)
+}
+
+func Test_CallOperationAuthorize(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	mounter := &fakeOperationMounter{}
+	server := grpc.NewServer()
+	api.RegisterDeviceMountServiceServer(server, mounter)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	kubeClient := fake.NewSimpleClientset()
+	// Only the group "gpu-users" may operate NVIDIA_GPU.
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.ResourceAttributes.Verb == VerbMount &&
+			len(sar.Spec.Groups) > 0 && sar.Spec.Groups[0] == "gpu-users"
+		return true, sar, nil
+	})
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, podIndexer.Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: "node-a"},
+	}))
+	mounterIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{NodeNameIndex: indexByNodeName})
+	require.NoError(t, mounterIndexer.Add(newMounterPod("mounter-a", "node-a", "10.0.0.1", "a")))
+	s := &service{
+		mounterSelector: &mounterSelector{
+			targetNamespace: "kube-system",
+			labelSelector:   labels.SelectorFromSet(labels.Set{"app": "device-mounter"}),
+		},
+		kubeClient:           kubeClient,
+		podLister:            listerv1.NewPodLister(podIndexer),
+		authConfig:           authfake.NewFakeReader(),
+		authorizeDeviceTypes: true,
+		mounterIndexer:       mounterIndexer,
+		pool: &mounterPool{
+			conns: make(map[string]*mounterConn),
+			dial: func(string) (*grpc.ClientConn, error) {
+				return grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			},
+		},
+	}
+	ws := new(restful.WebService)
+	path := "/namespaces/{namespace}/pods/{name}/operations/{id}"
+	ws.Route(ws.GET(path).To(s.GetOperation))
+	ws.Route(ws.DELETE(path).To(s.CancelOperation))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	call := func(method, group string) int {
+		req := httptest.NewRequest(method, "/namespaces/default/pods/test-pod/operations/op-1", nil)
+		req.Header.Set(authConfig.DefaultUserHeader, "alice")
+		req.Header.Set(authConfig.DefaultGroupHeader, group)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	// The caller must be allowed to mount the device type of the operation.
+	assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "developers"))
+	assert.Equal(t, http.StatusForbidden, call(http.MethodDelete, "developers"))
+	assert.Equal(t, 0, mounter.cancelled)
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "gpu-users"))
+	assert.Equal(t, http.StatusOK, call(http.MethodDelete, "gpu-users"))
+	assert.Equal(t, 1, mounter.cancelled)
+}
+
+func Test_OperationVerb(t *testing.T) {
+	assert.Equal(t, VerbMount, operationVerb("Mount"))
+	assert.Equal(t, VerbMount, operationVerb("Remount"))
+	assert.Equal(t, VerbUnMount, operationVerb("UnMount"))
+}
+
+func Test_ReadUnMountRequestParameters(t *testing.T) {
+	read := func(body string) (*requestUnMountParams, error) {
+		httpReq := httptest.NewRequest(http.MethodPut, "/unmount?device_type=HOST_DEVICE", strings.NewReader(body))
+		req := restful.NewRequest(httpReq)
+		req.PathParameters()["namespace"], req.PathParameters()["name"] = "default", "test-pod"
+		return readUnMountRequestParameters(req)
+	}
+	// The request body is optional.
+	params, err := read("")
+	require.NoError(t, err)
+	assert.Empty(t, params.Resources)
+	params, err = read(`{"resources": {"/dev/kvm": "1"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/dev/kvm": "1"}, params.Resources)
+	_, err = read("{")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/emicklei/go-restful/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resultStatusCode The http status code and the reason of the failed result code.
+func resultStatusCode(result api.ResultCode) (int32, metav1.StatusReason) {
+	switch result {
+	case api.ResultCode_NotFound:
+		return http.StatusNotFound, metav1.StatusReasonNotFound
+	case api.ResultCode_Invalid:
+		return http.StatusUnprocessableEntity, metav1.StatusReasonInvalid
+	case api.ResultCode_Insufficient, api.ResultCode_DeviceBusy, api.ResultCode_Conflict:
+		return http.StatusConflict, metav1.StatusReasonConflict
+	case api.ResultCode_Unschedulable:
+		return http.StatusServiceUnavailable, metav1.StatusReasonServiceUnavailable
+	case api.ResultCode_Timeout:
+		return http.StatusGatewayTimeout, metav1.StatusReasonTimeout
+	case api.ResultCode_PermissionDenied:
+		return http.StatusForbidden, metav1.StatusReasonForbidden
+	default:
+		return http.StatusInternalServerError, metav1.StatusReasonInternalError
+	}
+}
+
+// newResultStatus Build the status of the failed result, the result code is reported as the cause type.
+func newResultStatus(result api.ResultCode, message, podName string) *metav1.Status {
+	code, reason := resultStatusCode(result)
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     code,
+		Details: &metav1.StatusDetails{
+			Name: podName,
+			Kind: "pods",
+			Causes: []metav1.StatusCause{{
+				Type:    metav1.CauseType(result.String()),
+				Message: message,
+			}},
+		},
+	}
+}
+
+func writeStatus(response *restful.Response, s *metav1.Status) {
+	_ = response.WriteHeaderAndJson(int(s.Code), s, restful.MIME_JSON)
+}
+
+// writeResultError Write the failed result returned by the device mounter.
+func writeResultError(response *restful.Response, result api.ResultCode, message, podName string) {
+	writeStatus(response, newResultStatus(result, message, podName))
+}
+
+// writeStatusError Write the error as a status, errors that are not api status are internal errors.
+func writeStatusError(response *restful.Response, err error) {
+	if apiStatus, ok := err.(apierrors.APIStatus); ok {
+		s := apiStatus.Status()
+		s.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+		writeStatus(response, &s)
+		return
+	}
+	writeStatus(response, &apierrors.NewInternalError(err).ErrStatus)
+}
+
+// writeGRPCError Write the error of calling the device mounter.
+func writeGRPCError(response *restful.Response, err error, podName string) {
+	s, _ := status.FromError(err)
+	message := fmt.Sprintf("failed to call device mounter: %s", s.Message())
+	switch s.Code() {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		writeResultError(response, api.ResultCode_PermissionDenied, message, podName)
+	case codes.DeadlineExceeded:
+		writeResultError(response, api.ResultCode_Timeout, message, podName)
+	case codes.Unavailable:
+		writeStatus(response, &apierrors.NewServiceUnavailable(message).ErrStatus)
+	default:
+		writeStatus(response, &apierrors.NewInternalError(errors.New(message)).ErrStatus)
+	}
+}
@@ -0,0 +1,90 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_ResultStatusCode(t *testing.T) {
+	tests := []struct {
+		result api.ResultCode
+		code   int32
+		reason metav1.StatusReason
+	}{
+		{api.ResultCode_NotFound, http.StatusNotFound, metav1.StatusReasonNotFound},
+		{api.ResultCode_Invalid, http.StatusUnprocessableEntity, metav1.StatusReasonInvalid},
+		{api.ResultCode_Insufficient, http.StatusConflict, metav1.StatusReasonConflict},
+		{api.ResultCode_DeviceBusy, http.StatusConflict, metav1.StatusReasonConflict},
+		{api.ResultCode_Conflict, http.StatusConflict, metav1.StatusReasonConflict},
+		{api.ResultCode_Unschedulable, http.StatusServiceUnavailable, metav1.StatusReasonServiceUnavailable},
+		{api.ResultCode_Timeout, http.StatusGatewayTimeout, metav1.StatusReasonTimeout},
+		{api.ResultCode_PermissionDenied, http.StatusForbidden, metav1.StatusReasonForbidden},
+		{api.ResultCode_Fail, http.StatusInternalServerError, metav1.StatusReasonInternalError},
+		{api.ResultCode_Unknown, http.StatusInternalServerError, metav1.StatusReasonInternalError},
+	}
+	for _, test := range tests {
+		code, reason := resultStatusCode(test.result)
+		assert.Equal(t, test.code, code, test.result.String())
+		assert.Equal(t, test.reason, reason, test.result.String())
+	}
+}
+
+func writeToRecorder(write func(response *restful.Response)) (*httptest.ResponseRecorder, *metav1.Status) {
+	recorder := httptest.NewRecorder()
+	response := restful.NewResponse(recorder)
+	response.SetRequestAccepts(restful.MIME_JSON)
+	write(response)
+	s := &metav1.Status{}
+	_ = json.Unmarshal(recorder.Body.Bytes(), s)
+	return recorder, s
+}
+
+func Test_WriteStatus(t *testing.T) {
+	recorder, s := writeToRecorder(func(response *restful.Response) {
+		writeResultError(response, api.ResultCode_Insufficient, "Insufficient node resources", "test")
+	})
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "Status", s.Kind)
+	assert.Equal(t, metav1.StatusFailure, s.Status)
+	assert.Equal(t, metav1.StatusReasonConflict, s.Reason)
+	assert.Equal(t, "Insufficient node resources", s.Message)
+	assert.Equal(t, "test", s.Details.Name)
+	assert.Equal(t, metav1.CauseType("Insufficient"), s.Details.Causes[0].Type)
+
+	recorder, s = writeToRecorder(func(response *restful.Response) {
+		writeStatusError(response, apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "test"))
+	})
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, metav1.StatusReasonNotFound, s.Reason)
+
+	recorder, s = writeToRecorder(func(response *restful.Response) {
+		writeStatusError(response, fmt.Errorf("unexpected"))
+	})
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, metav1.StatusReasonInternalError, s.Reason)
+
+	recorder, s = writeToRecorder(func(response *restful.Response) {
+		writeGRPCError(response, status.Error(codes.PermissionDenied, "pod is not allowed"), "test")
+	})
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, metav1.CauseType("PermissionDenied"), s.Details.Causes[0].Type)
+
+	// The messages of the device mounter are not format strings.
+	recorder, s = writeToRecorder(func(response *restful.Response) {
+		writeGRPCError(response, status.Error(codes.Internal, "GPU usage 100%d"), "test")
+	})
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, s.Message, "failed to call device mounter: GPU usage 100%d")
+}
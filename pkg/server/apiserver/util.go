@@ -6,92 +6,336 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/api/v1alpha1"
 	"github.com/emicklei/go-restful/v3"
 	authzv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 )
 
-type requestMountBody struct {
+const (
+	// DeviceTypesResource The virtual resource authorized for the device types, named after the device type.
+	DeviceTypesResource = "devicetypes"
+
+	VerbMount        = "mount"
+	VerbUnMount      = "unmount"
+	VerbForceUnMount = "force-unmount"
+
+	// unMountOperationType The type of the unmount operations of the device mounter, other operations mount devices.
+	unMountOperationType = "UnMount"
+
+	// IdempotencyKeyHeader The header carrying the request id, an alternative to the request_id query parameter.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// RequestMountBody is the request body of the mount request.
+// +k8s:openapi-gen=true
+type RequestMountBody struct {
 	Resources   map[string]string `json:"resources"`
 	Annotations map[string]string `json:"annotations,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
-	Patches     []string          `json:"patches,omitempty"`
+	// +listType=atomic
+	Patches []string `json:"patches,omitempty"`
+}
+
+// RequestUnMountBody is the optional request body of the unmount request.
+// +k8s:openapi-gen=true
+type RequestUnMountBody struct {
+	// Only unmount the devices of these resources, e.g. the paths of the host devices.
+	Resources map[string]string `json:"resources,omitempty"`
 }
 
 type requestMountParams struct {
-	requestMountBody
+	RequestMountBody
 	container      string
 	deviceType     string
 	name           string
 	namespace      string
 	timeoutSeconds uint32
+	dryRun         bool
+	async          bool
+	requestID      string
 }
 
 type requestUnMountParams struct {
+	RequestUnMountBody
 	name           string
 	namespace      string
 	container      string
 	deviceType     string
 	timeoutSeconds uint32
 	force          bool
+	async          bool
+	requestID      string
 }
 
-func (s *service) GetMounterPodOnNodeName(nodeName string) (*v1.Pod, error) {
+type requestListParams struct {
+	name           string
+	namespace      string
+	container      string
+	deviceType     string
+	timeoutSeconds uint32
+}
+
+type requestOperationParams struct {
+	name           string
+	namespace      string
+	operationID    string
+	timeoutSeconds uint32
+}
 
-	podList, err := s.kubeClient.CoreV1().Pods(s.targetNamespace).
-		List(context.TODO(), metav1.ListOptions{
-			LabelSelector:   s.labelSelector.String(),
-			ResourceVersion: "0",
-		})
+// ContainerMountedDevices describes the devices of one type mounted into a container.
+// +k8s:openapi-gen=true
+type ContainerMountedDevices struct {
+	Container  string `json:"container"`
+	DeviceType string `json:"deviceType"`
+	// +listType=atomic
+	Devices []v1alpha1.MountedDevice `json:"devices"`
+	// +listType=atomic
+	SlavePods []string `json:"slavePods"`
+}
+
+// MountedDeviceList is the response body of the mounted devices query.
+// +k8s:openapi-gen=true
+type MountedDeviceList struct {
+	// +listType=atomic
+	Items []ContainerMountedDevices `json:"items"`
+}
+
+// DeviceResult is the response body of the mount and unmount requests.
+// +k8s:openapi-gen=true
+type DeviceResult struct {
+	Message string `json:"message"`
+	// +listType=atomic
+	Devices []v1alpha1.MountedDevice `json:"devices"`
+	// +listType=atomic
+	SlavePods []string `json:"slavePods"`
+	// The slave pods that would be created, only returned by dry run requests.
+	// +listType=atomic
+	PlannedSlavePods []runtime.RawExtension `json:"plannedSlavePods,omitempty"`
+	// The id of the operation running in the background, only returned by async requests.
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// OperationResult is the response body of the operation query and cancellation.
+// +k8s:openapi-gen=true
+type OperationResult struct {
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	Container  string       `json:"container"`
+	DeviceType string       `json:"deviceType"`
+	Stage      string       `json:"stage"`
+	State      string       `json:"state"`
+	StartTime  metav1.Time  `json:"startTime"`
+	EndTime    *metav1.Time `json:"endTime,omitempty"`
+	// The success or failure message of the finished operation.
+	Message string `json:"message,omitempty"`
+	// The result of the successful operation.
+	Result *DeviceResult `json:"result,omitempty"`
+}
+
+func newDeviceResult(resp *api.DeviceResponse) *DeviceResult {
+	result := &DeviceResult{
+		Message:     resp.GetMessage(),
+		Devices:     newMountedDevices(resp.GetDevices()),
+		SlavePods:   append([]string{}, resp.GetSlavePods()...),
+		OperationID: resp.GetOperationId(),
+	}
+	for _, pod := range resp.GetPlannedSlavePods() {
+		result.PlannedSlavePods = append(result.PlannedSlavePods, runtime.RawExtension{Raw: []byte(pod)})
+	}
+	return result
+}
+
+func newOperationResult(op *api.Operation) *OperationResult {
+	result := &OperationResult{
+		ID:         op.GetId(),
+		Type:       op.GetType(),
+		Container:  op.GetContainer(),
+		DeviceType: op.GetDeviceType(),
+		Stage:      op.GetStage(),
+		State:      op.GetState().String(),
+		StartTime:  metav1.Unix(op.GetStartTime(), 0),
+		Message:    op.GetResponse().GetMessage(),
+	}
+	if op.GetEndTime() > 0 {
+		endTime := metav1.Unix(op.GetEndTime(), 0)
+		result.EndTime = &endTime
+	}
+	if op.GetState() == api.OperationState_Succeeded {
+		result.Result = newDeviceResult(op.GetResponse())
+	}
+	return result
+}
+
+func newMountedDevices(devices []*api.MountedDevice) []v1alpha1.MountedDevice {
+	mountedDevices := make([]v1alpha1.MountedDevice, len(devices))
+	for i, device := range devices {
+		mountedDevices[i] = v1alpha1.MountedDevice{
+			DeviceID:       device.GetDeviceId(),
+			DeviceFilePath: device.GetDeviceFilePath(),
+			Type:           device.GetType(),
+			Major:          device.GetMajor(),
+			Minor:          device.GetMinor(),
+			Permissions:    device.GetPermissions(),
+		}
+	}
+	return mountedDevices
+}
+
+func newMountedDeviceList(items []*api.ContainerDevices) *MountedDeviceList {
+	list := &MountedDeviceList{Items: make([]ContainerMountedDevices, len(items))}
+	for i, item := range items {
+		list.Items[i] = ContainerMountedDevices{
+			Container:  item.GetContainer().GetName(),
+			DeviceType: item.GetDeviceType(),
+			Devices:    newMountedDevices(item.GetDevices()),
+			SlavePods:  item.GetSlavePods(),
+		}
+	}
+	return list
+}
+
+// NodeNameIndex The index of the device mounter pods by the name of their node.
+const NodeNameIndex = "nodeName"
+
+func indexByNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || len(pod.Spec.NodeName) == 0 {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// TransformTargetPod Only keep the fields of the target pods used by the apiserver to reduce the memory of the informer.
+func TransformTargetPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	meta := *pod.ObjectMeta.DeepCopy()
+	meta.ManagedFields = nil
+	return &v1.Pod{
+		ObjectMeta: meta,
+		Spec:       v1.PodSpec{NodeName: pod.Spec.NodeName},
+		Status:     v1.PodStatus{Phase: pod.Status.Phase},
+	}, nil
+}
+
+// getTargetPod Get the target pod from the informer cache, the pods not synced yet are read from the kube-apiserver.
+func (s *service) getTargetPod(ctx context.Context, namespace, name string) (*v1.Pod, error) {
+	pod, err := s.podLister.Pods(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return s.kubeClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	return pod, err
+}
+
+func (s *service) GetMounterPodOnNodeName(nodeName string) (*v1.Pod, error) {
+	objs, err := s.mounterIndexer.ByIndex(NodeNameIndex, nodeName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting device mounter Pod: %w", err)
 	}
 	// 根据node name找到对应的daemon
 	var mPod *v1.Pod
-	for _, mounterPod := range podList.Items {
-		if mounterPod.Spec.NodeName == nodeName && mounterPod.Status.Phase == v1.PodRunning {
+	for _, obj := range objs {
+		mounterPod, ok := obj.(*v1.Pod)
+		if !ok || mounterPod.Namespace != s.targetNamespace || !s.labelSelector.Matches(labels.Set(mounterPod.Labels)) {
+			continue
+		}
+		if mounterPod.Status.Phase == v1.PodRunning && mounterPod.DeletionTimestamp == nil {
 			for _, status := range mounterPod.Status.ContainerStatuses {
 				if !status.Ready {
 					return nil, fmt.Errorf("the device mounter is not ready on the target node %s", nodeName)
 				}
 			}
-			mPod = mounterPod.DeepCopy()
+			mPod = mounterPod
 			break
 		}
 	}
 	if mPod == nil {
 		return nil, fmt.Errorf("there is no device mounter on the target node %s", nodeName)
 	}
-	if mPod.Status.Phase != v1.PodRunning {
-		return nil, fmt.Errorf("target device mounter not in running state")
-	}
 	return mPod, nil
 }
 
-func (s *service) check(request *restful.Request) error {
+// requestUser The user information passed by the kube-apiserver through the request headers.
+type requestUser struct {
+	name   string
+	groups []string
+	extra  map[string]authzv1.ExtraValue
+}
+
+func (s *service) check(request *restful.Request) (*requestUser, error) {
 	requestHeader := request.Request.Header
 
 	user, err := s.getAuthUsername(requestHeader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	klog.V(4).Infoln("Visiting users", user)
 	groups, err := s.getAuthGroups(requestHeader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	klog.V(4).Infoln("Auth groups", groups)
 
-	if _, err := s.getAuthExtraHeaders(requestHeader); err != nil {
-		return err
+	extra, err := s.getAuthExtraHeaders(requestHeader)
+	if err != nil {
+		return nil, err
 	}
 
+	return &requestUser{name: user, groups: groups, extra: extra}, nil
+}
+
+// authorize Check whether the user is allowed to operate the device type in the namespace with a SubjectAccessReview.
+// An empty device type means all device types.
+func (s *service) authorize(ctx context.Context, user *requestUser, namespace, verb, deviceType string) error {
+	if !s.authorizeDeviceTypes {
+		return nil
+	}
+	deviceType = strings.ToUpper(deviceType)
+	sar := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			User:   user.name,
+			Groups: user.groups,
+			Extra:  user.extra,
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     v1alpha1.Group,
+				Resource:  DeviceTypesResource,
+				Name:      deviceType,
+			},
+		},
+	}
+	result, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	if !result.Status.Allowed {
+		klog.V(3).Infoln("Request denied", "user", user.name, "verb", verb, "deviceType", deviceType, "reason", result.Status.Reason)
+		groupResource := schema.GroupResource{Group: v1alpha1.Group, Resource: DeviceTypesResource}
+		return apierrors.NewForbidden(groupResource, deviceType, fmt.Errorf("user %q cannot %s device type %q in namespace %q",
+			user.name, verb, deviceType, namespace))
+	}
 	return nil
 }
 
+// operationVerb The verb authorized for the operations of the type, the same as the request starting the operation.
+func operationVerb(operationType string) string {
+	if strings.EqualFold(operationType, unMountOperationType) {
+		return VerbUnMount
+	}
+	return VerbMount
+}
+
 func (s *service) getAuthUsername(requestHeader http.Header) (string, error) {
 	userHeaders, err := s.authConfig.GetUserHeaders()
 	if err != nil {
@@ -0,0 +1,39 @@
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_Authorize(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	// Only the group "gpu-users" may mount NVIDIA_GPU, other device types are allowed to everyone.
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		attrs := sar.Spec.ResourceAttributes
+		allowed := attrs.Resource == DeviceTypesResource && attrs.Verb == VerbMount &&
+			(attrs.Name != "NVIDIA_GPU" || (len(sar.Spec.Groups) > 0 && sar.Spec.Groups[0] == "gpu-users"))
+		sar.Status.Allowed = allowed
+		return true, sar, nil
+	})
+	s := &service{kubeClient: kubeClient, authorizeDeviceTypes: true}
+	user := &requestUser{name: "alice", groups: []string{"developers"}}
+	gpuUser := &requestUser{name: "bob", groups: []string{"gpu-users"}}
+
+	assert.NoError(t, s.authorize(context.Background(), user, "default", VerbMount, "volcano_vgpu"))
+	assert.NoError(t, s.authorize(context.Background(), gpuUser, "default", VerbMount, "NVIDIA_GPU"))
+	err := s.authorize(context.Background(), user, "default", VerbMount, "NVIDIA_GPU")
+	assert.True(t, apierrors.IsForbidden(err))
+	err = s.authorize(context.Background(), gpuUser, "default", VerbForceUnMount, "NVIDIA_GPU")
+	assert.True(t, apierrors.IsForbidden(err))
+
+	s.authorizeDeviceTypes = false
+	assert.NoError(t, s.authorize(context.Background(), user, "default", VerbMount, "NVIDIA_GPU"))
+}
@@ -0,0 +1,232 @@
+package mounter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// The maximum length of the request id, which is stamped onto the slave pods as an annotation.
+const maxRequestIDLength = 128
+
+func checkRequestID(paramName, requestID string) error {
+	if len(requestID) > maxRequestIDLength {
+		msg := fmt.Sprintf("parameter '%s' must be no more than %d characters", paramName, maxRequestIDLength)
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
+	}
+	return nil
+}
+
+// requestDigest Compute the digest of the request parameters that decide the result,
+// the retries of the request must carry the same parameters.
+func requestDigest(req proto.Message) string {
+	req = proto.Clone(req)
+	switch r := req.(type) {
+	case *api.MountDeviceRequest:
+		r.RequestId, r.Async, r.DryRun = "", false, false
+		r.DeviceType = strings.ToUpper(r.DeviceType)
+	case *api.UnMountDeviceRequest:
+		r.RequestId, r.Async = "", false
+		r.DeviceType = strings.ToUpper(r.DeviceType)
+	}
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func requestConflictError(requestID string) error {
+	msg := fmt.Sprintf("The request id %s has been used by a request with different parameters", requestID)
+	return api.NewMounterError(api.ResultCode_Conflict, msg)
+}
+
+// How long the results of the finished unmount requests are retained.
+var RequestResultRetention = 24 * time.Hour
+
+type requestResult struct {
+	digest  string
+	resp    *api.DeviceResponse
+	expired time.Time
+}
+
+// requestCache Keep the results of the finished requests with request ids,
+// used by the unmount requests whose slave pods have been deleted.
+// The results are persisted in the journal to survive the restarts of the device mounter.
+type requestCache struct {
+	lock    sync.Mutex
+	journal *journal.Journal
+	results map[string]requestResult
+}
+
+func newRequestCache(opJournal *journal.Journal) *requestCache {
+	c := &requestCache{journal: opJournal, results: make(map[string]requestResult)}
+	persisted, err := opJournal.ListResults()
+	if err != nil {
+		klog.Errorf("Failed to load the persisted request results: %v", err)
+	}
+	for _, result := range persisted {
+		resp := &api.DeviceResponse{}
+		if err = protojson.Unmarshal(result.Response, resp); err != nil {
+			klog.Warningf("Skip corrupted request result %s: %v", result.Key, err)
+			continue
+		}
+		c.results[result.Key] = requestResult{digest: result.Digest, resp: resp, expired: result.ExpiresAt}
+	}
+	return c
+}
+
+func requestCacheKey(operationType string, podUID types.UID, containerName, requestID string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", operationType, podUID, containerName, requestID)
+}
+
+// Get Return the result of the request with the key, the result of the request
+// with a different digest is reported as a conflict.
+func (c *requestCache) Get(key, digest string) (*api.DeviceResponse, bool, error) {
+	if c == nil {
+		return nil, false, nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cleanup()
+	result, ok := c.results[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if result.digest != digest {
+		return nil, true, fmt.Errorf("request digest mismatch")
+	}
+	return proto.Clone(result.resp).(*api.DeviceResponse), true, nil
+}
+
+func (c *requestCache) Put(key, digest string, resp *api.DeviceResponse) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cleanup()
+	result := requestResult{
+		digest:  digest,
+		resp:    proto.Clone(resp).(*api.DeviceResponse),
+		expired: time.Now().Add(RequestResultRetention),
+	}
+	c.results[key] = result
+	data, err := protojson.Marshal(result.resp)
+	if err == nil {
+		err = c.journal.SaveResult(&journal.Result{
+			Key: key, Digest: digest, Response: data, ExpiresAt: result.expired,
+		})
+	}
+	if err != nil {
+		klog.Errorf("Failed to persist the result of request %s: %v", key, err)
+	}
+}
+
+// cleanup Remove the results that have exceeded the retention period.
+func (c *requestCache) cleanup() {
+	now := time.Now()
+	for key, result := range c.results {
+		if now.After(result.expired) {
+			delete(c.results, key)
+			_ = c.journal.RemoveResult(key)
+		}
+	}
+}
+
+// getMountRequestResult Rebuild the result of the mount request from the slave pods stamped with the request id,
+// nil is returned if the request has not mounted any devices.
+func (s *DeviceMounterServer) getMountRequestResult(ctx context.Context, deviceMounter framework.DeviceMounter,
+	deviceType, requestID, digest string, pod *v1.Pod, container *api.Container) (*api.DeviceResponse, error) {
+
+	slavePods, err := s.GetSlavePods(deviceType, pod, container)
+	if err != nil {
+		return nil, err
+	}
+	requestPods := filterRequestSlavePods(slavePods, requestID)
+	if len(requestPods) == 0 {
+		return nil, nil
+	}
+	for _, slavePod := range requestPods {
+		// The digest is recorded with the request id, the slave pods without it are not trusted.
+		if slavePod.Annotations[config.RequestDigestAnnotationKey] != digest {
+			return nil, requestConflictError(requestID)
+		}
+	}
+	deviceInfos, err := deviceMounter.GetDeviceInfosToMount(ctx, s.kubeClient, pod, container, requestPods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect mount device info: %v", err)
+	}
+	slavePodKeys := make([]string, len(requestPods))
+	for i, slavePod := range requestPods {
+		slavePodKeys[i] = api.ObjectKeyFromObject(slavePod).String()
+	}
+	klog.Infoln("Devices already mounted by request", "requestId", requestID, "slavePods", slavePodKeys)
+	return &api.DeviceResponse{
+		Result:    api.ResultCode_Success,
+		Message:   fmt.Sprintf("Successfully mounted %s devices", deviceType),
+		Devices:   NewMountedDevices(deviceInfos),
+		SlavePods: slavePodKeys,
+	}, nil
+}
+
+// getDirectMountRequestResult Rebuild the result of the mount request from the devices recorded
+// by the direct device mounter, nil is returned if the request has not mounted any devices.
+func (s *DeviceMounterServer) getDirectMountRequestResult(ctx context.Context, directMounter framework.DirectDeviceMounter,
+	deviceType, requestID string, pod *v1.Pod, container *api.Container, resources map[v1.ResourceName]resource.Quantity) (*api.DeviceResponse, error) {
+
+	deviceInfos, err := directMounter.GetRequestMountedDeviceInfos(ctx, s.kubeClient, pod, container, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect mount device info: %v", err)
+	}
+	if len(deviceInfos) == 0 {
+		return nil, nil
+	}
+	// The retry must request the same devices.
+	requested, err := directMounter.GetRequestedDeviceInfos(ctx, pod, container, resources)
+	if err != nil || !sameDeviceFiles(deviceInfos, requested) {
+		return nil, requestConflictError(requestID)
+	}
+	klog.Infoln("Devices already mounted by request", "requestId", requestID)
+	return &api.DeviceResponse{
+		Result:  api.ResultCode_Success,
+		Message: fmt.Sprintf("Successfully mounted %s devices", deviceType),
+		Devices: NewMountedDevices(deviceInfos),
+	}, nil
+}
+
+func sameDeviceFiles(deviceInfos, others []api.DeviceInfo) bool {
+	paths, otherPaths := sets.New[string](), sets.New[string]()
+	for _, deviceInfo := range deviceInfos {
+		paths.Insert(deviceInfo.DeviceFilePath)
+	}
+	for _, deviceInfo := range others {
+		otherPaths.Insert(deviceInfo.DeviceFilePath)
+	}
+	return paths.Equal(otherPaths)
+}
+
+// filterRequestSlavePods Return the slave pods created by the mount request with the request id.
+func filterRequestSlavePods(slavePods []*v1.Pod, requestID string) []*v1.Pod {
+	var requestPods []*v1.Pod
+	for _, slavePod := range slavePods {
+		if slavePod.Annotations[config.RequestIdAnnotationKey] == requestID {
+			requestPods = append(requestPods, slavePod)
+		}
+	}
+	return requestPods
+}
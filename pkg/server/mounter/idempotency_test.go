@@ -0,0 +1,65 @@
+package mounter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestCache(t *testing.T) {
+	opJournal, err := journal.NewJournal(t.TempDir())
+	require.NoError(t, err)
+	cache := newRequestCache(opJournal)
+	key := requestCacheKey(UnMountOperationType, "uid", "main", "req-1")
+	_, ok, err := cache.Get(key, "digest")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	cache.Put(key, "digest", &api.DeviceResponse{Result: api.ResultCode_Success, SlavePods: []string{"default/slave"}})
+	resp, ok, err := cache.Get(key, "digest")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"default/slave"}, resp.SlavePods)
+	_, _, err = cache.Get(key, "other")
+	assert.Error(t, err)
+
+	// The same request id of another container is a different request.
+	_, ok, _ = cache.Get(requestCacheKey(UnMountOperationType, "uid", "sidecar", "req-1"), "digest")
+	assert.False(t, ok)
+
+	// The results are loaded from the journal.
+	cache = newRequestCache(opJournal)
+	resp, ok, err = cache.Get(key, "digest")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"default/slave"}, resp.SlavePods)
+
+	cache.results[key] = requestResult{digest: "digest", resp: resp, expired: time.Now().Add(-time.Second)}
+	_, ok, _ = cache.Get(key, "digest")
+	assert.False(t, ok)
+	assert.Empty(t, cache.results)
+	results, err := opJournal.ListResults()
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	assert.NoError(t, checkRequestID("request_id", "req-1"))
+	assert.Error(t, checkRequestID("request_id", strings.Repeat("a", maxRequestIDLength+1)))
+}
+
+func Test_RequestDigest(t *testing.T) {
+	req := &api.UnMountDeviceRequest{PodName: "pod", DeviceType: "nvidia_gpu", RequestId: "req-1"}
+	digest := requestDigest(req)
+	// The retries differing in the request id, async and the case of the device type are the same request.
+	assert.Equal(t, digest, requestDigest(&api.UnMountDeviceRequest{PodName: "pod", DeviceType: "NVIDIA_GPU", Async: true}))
+	assert.NotEqual(t, digest, requestDigest(&api.UnMountDeviceRequest{PodName: "pod", DeviceType: "NVIDIA_GPU", Force: true}))
+	assert.Equal(t, "req-1", req.RequestId)
+
+	mountReq := &api.MountDeviceRequest{Resources: map[string]string{"a": "1", "b": "2"}}
+	assert.Equal(t, requestDigest(mountReq), requestDigest(&api.MountDeviceRequest{Resources: map[string]string{"b": "2", "a": "1"}, DryRun: true}))
+	assert.NotEqual(t, requestDigest(mountReq), requestDigest(&api.MountDeviceRequest{Resources: map[string]string{"a": "2", "b": "2"}}))
+}
@@ -0,0 +1,119 @@
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// ContainerLockPolicy What to do when another operation is running on the same container.
+type ContainerLockPolicy string
+
+const (
+	// ContainerLockPolicyWait Wait for the running operation to finish until the request times out.
+	ContainerLockPolicyWait ContainerLockPolicy = "Wait"
+	// ContainerLockPolicyReject Fail fast with the Conflict result code.
+	ContainerLockPolicyReject ContainerLockPolicy = "Reject"
+)
+
+// ParseContainerLockPolicy Verify the configured container lock policy.
+func ParseContainerLockPolicy(policy string) (ContainerLockPolicy, error) {
+	switch p := ContainerLockPolicy(policy); p {
+	case ContainerLockPolicyWait, ContainerLockPolicyReject:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported container lock policy %q, supported values: %s, %s",
+			policy, ContainerLockPolicyWait, ContainerLockPolicyReject)
+	}
+}
+
+type containerLock struct {
+	sem chan struct{}
+	// The number of operations holding or waiting for the lock.
+	refs int
+	// The type of the operation holding the lock.
+	holder string
+}
+
+// containerLocker Serialize the mount and unmount operations on the same container.
+type containerLocker struct {
+	lock   sync.Mutex
+	policy ContainerLockPolicy
+	locks  map[string]*containerLock
+}
+
+func newContainerLocker(policy ContainerLockPolicy) *containerLocker {
+	return &containerLocker{policy: policy, locks: make(map[string]*containerLock)}
+}
+
+// Acquire Lock the container of the pod for the operation, the returned function releases the lock.
+func (l *containerLocker) Acquire(ctx context.Context, podUID types.UID, containerName, operationType string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	key := string(podUID) + "/" + containerName
+	l.lock.Lock()
+	cl, ok := l.locks[key]
+	if !ok {
+		cl = &containerLock{sem: make(chan struct{}, 1)}
+		l.locks[key] = cl
+	}
+	cl.refs++
+	holder := cl.holder
+	l.lock.Unlock()
+
+	acquired := false
+	select {
+	case cl.sem <- struct{}{}:
+		acquired = true
+	default:
+	}
+	if !acquired && l.policy == ContainerLockPolicyWait {
+		klog.V(4).Infoln("Waiting for the running operation on the container", "container", key, "operation", holder)
+		select {
+		case cl.sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !acquired {
+		l.unref(key, cl)
+		operation := "operation"
+		if len(cl.holder) > 0 {
+			operation = cl.holder + " operation"
+		}
+		// The waiting request is not rejected, it ran out of time or was cancelled.
+		switch {
+		case l.policy != ContainerLockPolicyWait || ctx.Err() == nil:
+			msg := fmt.Sprintf("Another %s is in progress on container %s", operation, containerName)
+			return nil, api.NewMounterError(api.ResultCode_Conflict, msg)
+		case ctx.Err() == context.DeadlineExceeded:
+			msg := fmt.Sprintf("Timed out waiting for another %s on container %s", operation, containerName)
+			return nil, api.NewMounterError(api.ResultCode_Timeout, msg)
+		default:
+			msg := fmt.Sprintf("Cancelled waiting for another %s on container %s", operation, containerName)
+			return nil, api.NewMounterError(api.ResultCode_Fail, msg)
+		}
+	}
+	cl.holder = operationType
+	return func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		cl.holder = ""
+		<-cl.sem
+		l.unref(key, cl)
+	}, nil
+}
+
+func (l *containerLocker) unref(key string, cl *containerLock) {
+	if cl.refs--; cl.refs == 0 {
+		delete(l.locks, key)
+	}
+}
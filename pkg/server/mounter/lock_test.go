@@ -0,0 +1,72 @@
+package mounter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContainerLocker(t *testing.T) {
+	locker := newContainerLocker(ContainerLockPolicyReject)
+	release, err := locker.Acquire(context.Background(), "uid", "main", MountOperationType)
+	assert.NoError(t, err)
+
+	// Another container of the pod is not blocked.
+	releaseOther, err := locker.Acquire(context.Background(), "uid", "sidecar", UnMountOperationType)
+	assert.NoError(t, err)
+	releaseOther()
+
+	_, err = locker.Acquire(context.Background(), "uid", "main", UnMountOperationType)
+	mErr, ok := err.(*api.MounterError)
+	assert.True(t, ok)
+	assert.Equal(t, api.ResultCode_Conflict, mErr.Code)
+	assert.Equal(t, "Another Mount operation is in progress on container main", mErr.Message)
+
+	release()
+	release, err = locker.Acquire(context.Background(), "uid", "main", UnMountOperationType)
+	assert.NoError(t, err)
+	release()
+	assert.Empty(t, locker.locks)
+
+	// The waiting operation runs after the holder releases the lock.
+	locker = newContainerLocker(ContainerLockPolicyWait)
+	release, _ = locker.Acquire(context.Background(), "uid", "main", MountOperationType)
+	acquired := make(chan struct{})
+	go func() {
+		r, err := locker.Acquire(context.Background(), "uid", "main", UnMountOperationType)
+		assert.NoError(t, err)
+		close(acquired)
+		r()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the lock is acquired by two operations")
+	case <-time.After(100 * time.Millisecond):
+	}
+	release()
+	<-acquired
+
+	// The waiting operation gives up when the request times out.
+	release, _ = locker.Acquire(context.Background(), "uid", "main", MountOperationType)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = locker.Acquire(ctx, "uid", "main", UnMountOperationType)
+	mErr, ok = err.(*api.MounterError)
+	assert.True(t, ok)
+	assert.Equal(t, api.ResultCode_Timeout, mErr.Code)
+	assert.Equal(t, "Timed out waiting for another Mount operation on container main", mErr.Message)
+	cancelCtx, cancelWait := context.WithCancel(context.Background())
+	cancelWait()
+	_, err = locker.Acquire(cancelCtx, "uid", "main", UnMountOperationType)
+	mErr, ok = err.(*api.MounterError)
+	assert.True(t, ok)
+	assert.Equal(t, api.ResultCode_Fail, mErr.Code)
+	release()
+	assert.Empty(t, locker.locks)
+
+	_, err = ParseContainerLockPolicy("Unknown")
+	assert.Error(t, err)
+}
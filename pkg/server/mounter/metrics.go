@@ -0,0 +1,162 @@
+package mounter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// The period to recount the mounted devices, which also catches the slave pods garbage collected by the controllers.
+const mountedDevicesRefreshPeriod = time.Minute
+
+// mountedDevicesCollector Keep the number of devices mounted on the node in memory,
+// counting the devices queries the device mounters, so the scrapes only read the last counts.
+type mountedDevicesCollector struct {
+	nodeName string
+	lock     sync.RWMutex
+	counts   map[string]int
+	// Buffered notification to recount the devices after the slave pods changed.
+	changed chan struct{}
+}
+
+func newMountedDevicesCollector(nodeName string) *mountedDevicesCollector {
+	return &mountedDevicesCollector{
+		nodeName: nodeName,
+		counts:   make(map[string]int),
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// NewMountedDevicesCollector Collect the number of devices currently mounted on the node,
+// the counts are refreshed by RefreshMountedDevices.
+func NewMountedDevicesCollector(server *DeviceMounterServer) prometheus.Collector {
+	return server.mountedDevices
+}
+
+func (c *mountedDevicesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metrics.MountedDevicesDesc
+}
+
+func (c *mountedDevicesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for deviceType, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(metrics.MountedDevicesDesc,
+			prometheus.GaugeValue, float64(count), c.nodeName, deviceType)
+	}
+}
+
+func (c *mountedDevicesCollector) set(counts map[string]int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts = counts
+}
+
+// notify Request to recount the devices, the notifications are merged while counting.
+func (c *mountedDevicesCollector) notify() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// SlavePodEventHandler Recount the mounted devices when the slave pods on the node are created or deleted,
+// by mounting, unmounting or garbage collection.
+func (s *DeviceMounterServer) SlavePodEventHandler() cache.ResourceEventHandler {
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			pod, ok := obj.(*v1.Pod)
+			return ok && len(pod.Labels[config.CreatedByLabelKey]) > 0
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { s.mountedDevices.notify() },
+			DeleteFunc: func(interface{}) { s.mountedDevices.notify() },
+		},
+	}
+}
+
+// RefreshMountedDevices Count the mounted devices at startup, after the slave pods changed
+// and periodically, until the context is done.
+func (s *DeviceMounterServer) RefreshMountedDevices(ctx context.Context) {
+	ticker := time.NewTicker(mountedDevicesRefreshPeriod)
+	defer ticker.Stop()
+	for {
+		countCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		s.mountedDevices.set(s.countMountedDevices(countCtx))
+		cancel()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.mountedDevices.changed:
+		}
+	}
+}
+
+type slavePodOwner struct {
+	namespace  string
+	name       string
+	uid        string
+	container  string
+	deviceType string
+}
+
+// countMountedDevices Count the mounted devices of each device type through the slave pods on the node.
+func (s *DeviceMounterServer) countMountedDevices(ctx context.Context) map[string]int {
+	counts := make(map[string]int)
+	for _, deviceType := range framework.GetDeviceMounterTypes() {
+		counts[deviceType] = 0
+	}
+	requirement, _ := labels.NewRequirement(config.CreatedByLabelKey, selection.Exists, nil)
+	pods, err := s.podLister.List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		klog.V(4).ErrorS(err, "List slave pods failed")
+		return counts
+	}
+	slavePods := make(map[slavePodOwner][]*v1.Pod)
+	for _, pod := range pods {
+		owner := slavePodOwner{
+			namespace:  pod.Namespace,
+			name:       pod.Labels[config.OwnerNameLabelKey],
+			uid:        pod.Labels[config.OwnerUidLabelKey],
+			container:  pod.Labels[config.MountContainerLabelKey],
+			deviceType: pod.Annotations[config.DeviceTypeAnnotationKey],
+		}
+		slavePods[owner] = append(slavePods[owner], pod.DeepCopy())
+	}
+	for owner, pods := range slavePods {
+		deviceMounter, ok := framework.GetDeviceMounter(owner.deviceType)
+		if !ok {
+			continue
+		}
+		pod, err := s.podLister.Pods(owner.namespace).Get(owner.name)
+		if err != nil || string(pod.UID) != owner.uid {
+			continue
+		}
+		container, err := CheckPodContainer(pod, &api.Container{Name: owner.container})
+		if err != nil {
+			continue
+		}
+		deviceInfos, err := deviceMounter.GetDeviceInfosToUnmount(ctx, s.kubeClient, pod, container, pods)
+		if err != nil {
+			klog.V(4).ErrorS(err, "Get mounted device info error", "pod", owner.namespace+"/"+owner.name)
+			continue
+		}
+		counts[owner.deviceType] += len(deviceInfos)
+	}
+	return counts
+}
@@ -0,0 +1,460 @@
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/devices/fake"
+	"github.com/coldzerofear/device-mounter/pkg/devices/hostdevice"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"github.com/coldzerofear/device-mounter/pkg/simulator"
+	"github.com/opencontainers/runc/libcontainer/devices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func newSimulatedServer(t *testing.T) (*DeviceMounterServer, *simulator.Node, *fake.FakeMounter, *v1.Pod) {
+	node := simulator.NewNode(t, "node-1", map[v1.ResourceName]int{fake.ResourceName: 2})
+	mounter := fake.NewFakeMounter()
+	require.NoError(t, framework.RegisterDeviceMounter(mounter, func(framework.DeviceMounter) bool { return true }))
+	t.Cleanup(func() { framework.UnregisterDeviceMounter(mounter) })
+	operationJournal, err := journal.NewJournal(t.TempDir())
+	require.NoError(t, err)
+
+	server := NewDeviceMounterServer(node.Name, node.KubeClient, node.PodLister, node.NodeLister,
+		nil, &record.FakeRecorder{}, operationJournal, ContainerLockPolicyWait)
+	pod := node.RunPod(t, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+	})
+	return server, node, mounter, pod
+}
+
+func mountRequest(pod *v1.Pod, quantity string) *api.MountDeviceRequest {
+	return &api.MountDeviceRequest{
+		PodName:      pod.Name,
+		PodNamespace: pod.Namespace,
+		Container:    &api.Container{Name: "main"},
+		DeviceType:   fake.PluginName,
+		Resources:    map[string]string{fake.ResourceName: quantity},
+	}
+}
+
+func unmountRequest(pod *v1.Pod, force bool) *api.UnMountDeviceRequest {
+	return &api.UnMountDeviceRequest{
+		PodName:      pod.Name,
+		PodNamespace: pod.Namespace,
+		Container:    &api.Container{Name: "main"},
+		DeviceType:   fake.PluginName,
+		Force:        force,
+	}
+}
+
+func listSlavePods(t *testing.T, node *simulator.Node) []v1.Pod {
+	pods, err := node.KubeClient.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var slavePods []v1.Pod
+	for _, pod := range pods.Items {
+		if pod.Name != "pod" {
+			slavePods = append(slavePods, pod)
+		}
+	}
+	return slavePods
+}
+
+// waitSlavePodsCached Wait for the informer to observe the slave pods, GetSlavePods reads them from the cache.
+func waitSlavePodsCached(t *testing.T, server *DeviceMounterServer, pod *v1.Pod, count int) {
+	assert.Eventually(t, func() bool {
+		slavePods, err := server.GetSlavePods(fake.PluginName, pod, &api.Container{Name: "main"})
+		return err == nil && len(slavePods) == count
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func Test_MountAndUnMountDevice(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := server.MountDevice(ctx, mountRequest(pod, "1"))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	require.Len(t, resp.Devices, 1)
+	assert.Equal(t, "/dev/fake0", resp.Devices[0].DeviceFilePath)
+	assert.Len(t, resp.SlavePods, 1)
+	deviceFile, err := node.ReadDeviceFile(pid, "/dev/fake0")
+	assert.NoError(t, err)
+	assert.Equal(t, "c 240 0", deviceFile)
+	rule, err := node.ReadCGroupFile(pod, "main", "devices.allow")
+	assert.NoError(t, err)
+	assert.Equal(t, "c 240:0 rw", rule)
+	deviceRules, err := node.ReadCGroupFile(pod, "main", "devices.list")
+	assert.NoError(t, err)
+	assert.Contains(t, deviceRules, "c 240:0 rw\n")
+	assert.Len(t, listSlavePods(t, node), 1)
+
+	// The devices are busy while the container process holds them open.
+	waitSlavePodsCached(t, server, pod, 1)
+	require.NoError(t, node.OpenFile(pid, "/dev/fake0"))
+	resp, err = server.UnMountDevice(ctx, unmountRequest(pod, false))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_DeviceBusy, resp.Result, resp.Message)
+	assert.Empty(t, node.KilledProcesses())
+
+	resp, err = server.UnMountDevice(ctx, unmountRequest(pod, true))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	assert.Equal(t, []int{pid}, node.KilledProcesses())
+	_, err = node.ReadDeviceFile(pid, "/dev/fake0")
+	assert.True(t, os.IsNotExist(err))
+	deviceRules, err = node.ReadCGroupFile(pod, "main", "devices.list")
+	assert.NoError(t, err)
+	assert.NotContains(t, deviceRules, "c 240:0")
+	assert.Empty(t, listSlavePods(t, node))
+}
+
+func Test_MountDeviceRollback(t *testing.T) {
+	server, node, mounter, pod := newSimulatedServer(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Insufficient devices are rejected before any changes.
+	resp, err := server.MountDevice(ctx, mountRequest(pod, "3"))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Insufficient, resp.Result, resp.Message)
+	assert.Empty(t, listSlavePods(t, node))
+
+	// The failure after the devices are mounted rolls back the device files and the slave pods.
+	mounter.InjectError(fake.StepExecutePostMountActions, fmt.Errorf("post mount actions failed"))
+	resp, err = server.MountDevice(ctx, mountRequest(pod, "2"))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Fail, resp.Result)
+	assert.Contains(t, resp.Message, "post mount actions failed")
+	for _, path := range []string{"/dev/fake0", "/dev/fake1"} {
+		_, err = node.ReadDeviceFile(pid, path)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+	assert.Empty(t, listSlavePods(t, node))
+
+	// The devices released by the rollback can be mounted again.
+	mounter.InjectError(fake.StepExecutePostMountActions, nil)
+	resp, err = server.MountDevice(ctx, mountRequest(pod, "2"))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	assert.Len(t, resp.Devices, 2)
+}
+
+func Test_UnMountDeviceRollback(t *testing.T) {
+	server, node, mounter, pod := newSimulatedServer(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := server.MountDevice(ctx, mountRequest(pod, "1"))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	waitSlavePodsCached(t, server, pod, 1)
+
+	// The failure after the devices are removed restores the device files and keeps the slave pods.
+	mounter.InjectError(fake.StepExecutePostUnmountActions, fmt.Errorf("post unmount actions failed"))
+	resp, err = server.UnMountDevice(ctx, unmountRequest(pod, false))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Fail, resp.Result)
+	deviceFile, err := node.ReadDeviceFile(pid, "/dev/fake0")
+	assert.NoError(t, err)
+	assert.Equal(t, "c 240 0", deviceFile)
+	assert.Len(t, listSlavePods(t, node), 1)
+
+	mounter.InjectError(fake.StepExecutePostUnmountActions, nil)
+	resp, err = server.UnMountDevice(ctx, unmountRequest(pod, false))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	assert.Empty(t, listSlavePods(t, node))
+}
+
+func Test_UnMountDeviceByMountRequestId(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, requestID := range []string{"req-1", "req-2"} {
+		req := mountRequest(pod, "1")
+		req.RequestId = requestID
+		resp, err := server.MountDevice(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	}
+	waitSlavePodsCached(t, server, pod, 2)
+
+	// Only the devices mounted by the request are unmounted.
+	req := unmountRequest(pod, false)
+	req.MountRequestId = "req-1"
+	resp, err := server.UnMountDevice(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	_, err = node.ReadDeviceFile(pid, "/dev/fake0")
+	assert.True(t, os.IsNotExist(err))
+	_, err = node.ReadDeviceFile(pid, "/dev/fake1")
+	assert.NoError(t, err)
+	slavePods := listSlavePods(t, node)
+	require.Len(t, slavePods, 1)
+	assert.Equal(t, "req-2", slavePods[0].Annotations[config.RequestIdAnnotationKey])
+
+	waitSlavePodsCached(t, server, pod, 1)
+	resp, err = server.UnMountDevice(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_NotFound, resp.Result, resp.Message)
+}
+
+func Test_IdempotentRequests(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mountReq := mountRequest(pod, "1")
+	mountReq.RequestId = "mount-1"
+	resp, err := server.MountDevice(ctx, mountReq)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	waitSlavePodsCached(t, server, pod, 1)
+
+	// The retry returns the mounted devices, the same key with other parameters is a conflict.
+	resp, err = server.MountDevice(ctx, mountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	assert.Len(t, listSlavePods(t, node), 1)
+	mountReq.Resources[fake.ResourceName] = "2"
+	resp, err = server.MountDevice(ctx, mountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Conflict, resp.Result, resp.Message)
+
+	// The slave pods without the digest of the request are not trusted.
+	slavePod := listSlavePods(t, node)[0]
+	delete(slavePod.Annotations, config.RequestDigestAnnotationKey)
+	_, err = node.KubeClient.CoreV1().Pods(slavePod.Namespace).Update(ctx, &slavePod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		slavePods, err := server.GetSlavePods(fake.PluginName, pod, &api.Container{Name: "main"})
+		return err == nil && len(slavePods) == 1 && len(slavePods[0].Annotations[config.RequestDigestAnnotationKey]) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	mountReq.Resources[fake.ResourceName] = "1"
+	resp, err = server.MountDevice(ctx, mountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Conflict, resp.Result, resp.Message)
+
+	unmountReq := unmountRequest(pod, false)
+	unmountReq.RequestId = "unmount-1"
+	resp, err = server.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+
+	// The unmount result survives the restart of the device mounter.
+	restarted := NewDeviceMounterServer(node.Name, node.KubeClient, node.PodLister, node.NodeLister,
+		nil, &record.FakeRecorder{}, server.journal, ContainerLockPolicyWait)
+	retried, err := restarted.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Success, retried.Result, retried.Message)
+	assert.Equal(t, resp.SlavePods, retried.SlavePods)
+	unmountReq.Force = true
+	resp, err = restarted.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Conflict, resp.Result, resp.Message)
+}
+
+// registerHostDeviceMounter Register the host device mounter allowing /dev/null and /dev/zero in the default namespace.
+func registerHostDeviceMounter(t *testing.T) {
+	allowedPaths, allowedNamespaces := config.HostDeviceAllowedPaths, config.HostDeviceAllowedNamespaces
+	config.HostDeviceAllowedPaths, config.HostDeviceAllowedNamespaces = []string{"/dev/null", "/dev/zero"}, []string{"default"}
+	t.Cleanup(func() {
+		config.HostDeviceAllowedPaths, config.HostDeviceAllowedNamespaces = allowedPaths, allowedNamespaces
+	})
+	mounter, err := hostdevice.NewHostDeviceMounter()
+	require.NoError(t, err)
+	require.NoError(t, framework.RegisterDeviceMounter(mounter, func(framework.DeviceMounter) bool { return true }))
+	t.Cleanup(func() { framework.UnregisterDeviceMounter(mounter) })
+}
+
+func Test_DirectMountIdempotentRequests(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	registerHostDeviceMounter(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	newRequest := func(requestID, path string) *api.MountDeviceRequest {
+		req := mountRequest(pod, "1")
+		req.DeviceType, req.RequestId = hostdevice.PluginName, requestID
+		req.Resources = map[string]string{path: "1"}
+		return req
+	}
+
+	for _, path := range []string{"/dev/null", "/dev/zero"} {
+		resp, err := server.MountDevice(ctx, newRequest(path+"-request", path))
+		require.NoError(t, err)
+		require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	}
+	// The retry returns the recorded devices instead of failing as already mounted.
+	resp, err := server.MountDevice(ctx, newRequest("/dev/null-request", "/dev/null"))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	require.Len(t, resp.Devices, 1)
+	assert.Equal(t, "/dev/null", resp.Devices[0].DeviceFilePath)
+	resp, err = server.MountDevice(ctx, newRequest("/dev/null-request", "/dev/zero"))
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Conflict, resp.Result, resp.Message)
+
+	// Only the devices of the mount request are unmounted.
+	unmountReq := unmountRequest(pod, false)
+	unmountReq.DeviceType, unmountReq.MountRequestId = hostdevice.PluginName, "/dev/null-request"
+	resp, err = server.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	_, err = node.ReadDeviceFile(pid, "/dev/null")
+	assert.True(t, os.IsNotExist(err))
+	_, err = node.ReadDeviceFile(pid, "/dev/zero")
+	assert.NoError(t, err)
+	latestPod, err := node.KubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/zero"}, hostdevice.GetMountedDevicePaths(latestPod, &api.Container{Name: "main"}))
+}
+
+func Test_DirectUnMountDeviceByResources(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	registerHostDeviceMounter(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req := mountRequest(pod, "1")
+	req.DeviceType, req.Resources = hostdevice.PluginName, map[string]string{"/dev/null": "1", "/dev/zero": "1"}
+	resp, err := server.MountDevice(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+
+	// Only the devices of the requested resources are unmounted.
+	unmountReq := unmountRequest(pod, false)
+	unmountReq.DeviceType, unmountReq.Resources = hostdevice.PluginName, map[string]string{"/dev/null": "1"}
+	resp, err = server.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	require.Len(t, resp.Devices, 1)
+	assert.Equal(t, "/dev/null", resp.Devices[0].DeviceFilePath)
+	_, err = node.ReadDeviceFile(pid, "/dev/null")
+	assert.True(t, os.IsNotExist(err))
+	_, err = node.ReadDeviceFile(pid, "/dev/zero")
+	assert.NoError(t, err)
+	latestPod, err := node.KubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/zero"}, hostdevice.GetMountedDevicePaths(latestPod, &api.Container{Name: "main"}))
+
+	// The device that is no longer mounted is not found.
+	resp, err = server.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_NotFound, resp.Result, resp.Message)
+
+	// The device types with slave pods do not support unmounting by resources.
+	unmountReq = unmountRequest(pod, false)
+	unmountReq.Resources = map[string]string{fake.ResourceName: "1"}
+	resp, err = server.UnMountDevice(ctx, unmountReq)
+	require.NoError(t, err)
+	assert.Equal(t, api.ResultCode_Invalid, resp.Result, resp.Message)
+}
+
+func Test_RefreshMountedDevices(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := node.PodInformer.AddEventHandler(server.SlavePodEventHandler())
+	require.NoError(t, err)
+	go server.RefreshMountedDevices(ctx)
+	mountedDevices := func() int {
+		server.mountedDevices.lock.RLock()
+		defer server.mountedDevices.lock.RUnlock()
+		return server.mountedDevices.counts[fake.PluginName]
+	}
+
+	resp, err := server.MountDevice(ctx, mountRequest(pod, "2"))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	// The devices are recounted when the informer observes the slave pods.
+	assert.Eventually(t, func() bool { return mountedDevices() == 2 }, 5*time.Second, 50*time.Millisecond)
+
+	resp, err = server.UnMountDevice(ctx, unmountRequest(pod, false))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+	assert.Eventually(t, func() bool { return mountedDevices() == 0 }, 5*time.Second, 50*time.Millisecond)
+}
+
+// lateMounter A device mounter registered after the startup, like the device mounter plugins.
+type lateMounter struct {
+	*fake.FakeMounter
+}
+
+func (m lateMounter) GetDeviceType() string {
+	return "MY_PLUGIN"
+}
+
+func Test_RecoverDeferredJournal(t *testing.T) {
+	server, node, _, pod := newSimulatedServer(t)
+	pid, err := node.GetContainerPid(pod, "main")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := server.MountDevice(ctx, mountRequest(pod, "1"))
+	require.NoError(t, err)
+	require.Equal(t, api.ResultCode_Success, resp.Result, resp.Message)
+
+	// An unmount of the plugin device type interrupted after the device rules were changed.
+	latestPod, err := node.KubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	containerID := server.GetContainerID(latestPod, &api.Container{Name: "main"})
+	entry, err := server.journal.Begin(journal.UnMountOperation, "MY_PLUGIN", api.ObjectKeyFromObject(pod), "main", containerID)
+	require.NoError(t, err)
+	entry.DeviceInfos = []api.DeviceInfo{{
+		DeviceFilePath: "/dev/fake0",
+		Rule:           devices.Rule{Type: devices.CharDevice, Major: fake.DeviceMajor, Minor: 0, Permissions: "rw"},
+	}}
+	require.NoError(t, server.journal.Record(entry, journal.PhaseDeviceRulesSet))
+
+	// The entry is kept until the device type registers.
+	require.NoError(t, server.RecoverJournal(ctx))
+	entries, err := server.journal.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	deviceRules, err := node.ReadCGroupFile(pod, "main", "devices.list")
+	assert.NoError(t, err)
+	assert.Contains(t, deviceRules, "c 240:0 rw\n")
+
+	// The operations started after the startup are not recovered.
+	running, err := server.journal.Begin(journal.UnMountOperation, "MY_PLUGIN", api.ObjectKeyFromObject(pod), "main", containerID)
+	require.NoError(t, err)
+	mounter := lateMounter{FakeMounter: fake.NewFakeMounter()}
+	require.NoError(t, framework.RegisterDeviceMounter(mounter, func(framework.DeviceMounter) bool { return true }))
+	t.Cleanup(func() { framework.UnregisterDeviceMounter(mounter) })
+	require.NoError(t, server.RecoverDeferredJournal(ctx, "my_plugin"))
+	entries, err = server.journal.List()
+	require.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, running.ID, entries[0].ID)
+	}
+	deviceRules, err = node.ReadCGroupFile(pod, "main", "devices.list")
+	assert.NoError(t, err)
+	assert.NotContains(t, deviceRules, "c 240:0")
+	_, err = node.ReadDeviceFile(pid, "/dev/fake0")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,181 @@
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+const (
+	MountOperationType   = "Mount"
+	UnMountOperationType = "UnMount"
+	RemountOperationType = "Remount"
+)
+
+// The stages of a mount or unmount operation.
+const (
+	StagePending             = "Pending"
+	StageSlavePodsCreated    = "SlavePodsCreated"
+	StageSlavePodsReady      = "SlavePodsReady"
+	StageDeviceRulesSet      = "DeviceRulesSet"
+	StageDeviceFilesSet      = "DeviceFilesSet"
+	StagePostActionsExecuted = "PostActionsExecuted"
+	StageSlavePodsCleaned    = "SlavePodsCleaned"
+)
+
+var (
+	// The maximum execution time of the background operation.
+	OperationTimeout = 30 * time.Minute
+	// How long the finished operations are retained.
+	OperationRetention = 10 * time.Minute
+)
+
+type operation struct {
+	lock      sync.Mutex
+	op        *api.Operation
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+func (o *operation) setStage(stage string) {
+	o.lock.Lock()
+	o.op.Stage = stage
+	o.lock.Unlock()
+}
+
+func (o *operation) snapshot() *api.Operation {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return proto.Clone(o.op).(*api.Operation)
+}
+
+func (o *operation) finished() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.op.State != api.OperationState_Running
+}
+
+type operationKey struct{}
+
+// setOperationStage Report the completed stage to the operation running the request, if any.
+func setOperationStage(ctx context.Context, stage string) {
+	if o, ok := ctx.Value(operationKey{}).(*operation); ok {
+		o.setStage(stage)
+	}
+}
+
+type operationManager struct {
+	lock       sync.Mutex
+	operations map[string]*operation
+}
+
+func newOperationManager() *operationManager {
+	return &operationManager{operations: make(map[string]*operation)}
+}
+
+// Start Run the function in the background and return the operation tracking it.
+func (m *operationManager) Start(op *api.Operation, run func(ctx context.Context) (*api.DeviceResponse, error)) *api.Operation {
+	op.Id = uuid.New().String()
+	op.Stage = StagePending
+	op.State = api.OperationState_Running
+	op.StartTime = time.Now().Unix()
+
+	o := &operation{op: op}
+	ctx, cancel := context.WithTimeout(context.Background(), OperationTimeout)
+	o.cancel = cancel
+	ctx = context.WithValue(ctx, operationKey{}, o)
+
+	m.lock.Lock()
+	m.cleanup()
+	m.operations[op.Id] = o
+	m.lock.Unlock()
+
+	go func() {
+		defer cancel()
+		resp, err := run(ctx)
+		if err != nil {
+			resp = &api.DeviceResponse{Result: api.ResultCode_Fail, Message: err.Error()}
+		}
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		o.op.Response = resp
+		o.op.EndTime = time.Now().Unix()
+		switch {
+		case resp.Result == api.ResultCode_Success:
+			o.op.State = api.OperationState_Succeeded
+		case o.cancelled:
+			o.op.State = api.OperationState_Cancelled
+		default:
+			o.op.State = api.OperationState_Failed
+		}
+		klog.Infoln("Operation finished", "id", o.op.Id, "type", o.op.Type, "state", o.op.State.String())
+	}()
+	return o.snapshot()
+}
+
+func (m *operationManager) Get(id string) (*operation, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.cleanup()
+	o, ok := m.operations[id]
+	return o, ok
+}
+
+// Cancel Cancel the running operation, the completed steps will be rolled back.
+func (m *operationManager) Cancel(id string) (*operation, bool) {
+	o, ok := m.Get(id)
+	if !ok {
+		return nil, false
+	}
+	o.lock.Lock()
+	if o.op.State == api.OperationState_Running {
+		o.cancelled = true
+		o.cancel()
+	}
+	o.lock.Unlock()
+	return o, true
+}
+
+// cleanup Remove the finished operations that have exceeded the retention period.
+func (m *operationManager) cleanup() {
+	deadline := time.Now().Add(-OperationRetention).Unix()
+	for id, o := range m.operations {
+		o.lock.Lock()
+		expired := o.op.State != api.OperationState_Running && o.op.EndTime < deadline
+		o.lock.Unlock()
+		if expired {
+			delete(m.operations, id)
+		}
+	}
+}
+
+// startOperation Start an asynchronous operation and return the accepted response.
+func (s *DeviceMounterServer) startOperation(op *api.Operation, run func(ctx context.Context) (*api.DeviceResponse, error)) *api.DeviceResponse {
+	op = s.operations.Start(op, run)
+	klog.Infoln("Operation accepted", "id", op.Id, "type", op.Type, "pod", op.PodNamespace+"/"+op.PodName)
+	return &api.DeviceResponse{
+		Result:      api.ResultCode_Success,
+		Message:     "Operation accepted",
+		OperationId: op.Id,
+	}
+}
+
+// getOperation Find the operation of the requested pod.
+func (s *DeviceMounterServer) getOperation(req *api.OperationRequest) (*operation, error) {
+	o, ok := s.operations.Get(req.GetOperationId())
+	if ok {
+		op := o.snapshot()
+		ok = op.PodName == req.GetPodName() && op.PodNamespace == req.GetPodNamespace()
+	}
+	if !ok {
+		msg := fmt.Sprintf("Not found operation %s of pod %s/%s", req.GetOperationId(), req.GetPodNamespace(), req.GetPodName())
+		return nil, api.NewMounterError(api.ResultCode_NotFound, msg)
+	}
+	return o, nil
+}
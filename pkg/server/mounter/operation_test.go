@@ -0,0 +1,50 @@
+package mounter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Operation(t *testing.T) {
+	s := &DeviceMounterServer{operations: newOperationManager()}
+	started := make(chan struct{})
+	resp := s.startOperation(&api.Operation{
+		Type:         MountOperationType,
+		PodName:      "test",
+		PodNamespace: "default",
+	}, func(ctx context.Context) (*api.DeviceResponse, error) {
+		setOperationStage(ctx, StageSlavePodsCreated)
+		close(started)
+		<-ctx.Done()
+		return &api.DeviceResponse{Result: api.ResultCode_Fail, Message: ctx.Err().Error()}, nil
+	})
+	assert.Equal(t, api.ResultCode_Success, resp.Result)
+	assert.NotEmpty(t, resp.OperationId)
+	<-started
+
+	req := &api.OperationRequest{PodName: "test", PodNamespace: "default", OperationId: resp.OperationId}
+	opResp, _ := s.GetOperation(context.Background(), req)
+	assert.Equal(t, api.ResultCode_Success, opResp.Result)
+	assert.Equal(t, api.OperationState_Running, opResp.Operation.State)
+	assert.Equal(t, StageSlavePodsCreated, opResp.Operation.Stage)
+
+	// The operation of another pod is not visible.
+	opResp, _ = s.GetOperation(context.Background(), &api.OperationRequest{
+		PodName: "other", PodNamespace: "default", OperationId: resp.OperationId})
+	assert.Equal(t, api.ResultCode_NotFound, opResp.Result)
+
+	opResp, _ = s.CancelOperation(context.Background(), req)
+	assert.Equal(t, api.ResultCode_Success, opResp.Result)
+	assert.Eventually(t, func() bool {
+		opResp, _ = s.GetOperation(context.Background(), req)
+		return opResp.Operation.State == api.OperationState_Cancelled
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Finished operations cannot be cancelled.
+	opResp, _ = s.CancelOperation(context.Background(), req)
+	assert.Equal(t, api.ResultCode_Invalid, opResp.Result)
+}
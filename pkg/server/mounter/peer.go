@@ -0,0 +1,214 @@
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// PeerAuthInfo The credentials of the process connected to the unix socket.
+type PeerAuthInfo struct {
+	credentials.CommonAuthInfo
+	Pid int32
+	Uid uint32
+	Gid uint32
+}
+
+func (PeerAuthInfo) AuthType() string {
+	return "peercred"
+}
+
+// peerCredentials The transport credentials of the unix socket, which read SO_PEERCRED of each connection.
+type peerCredentials struct{}
+
+// NewPeerCredentials Create the transport credentials that record the peer process of the unix socket connections.
+func NewPeerCredentials() credentials.TransportCredentials {
+	return peerCredentials{}
+}
+
+func (peerCredentials) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, PeerAuthInfo{}, nil
+}
+
+func (peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported connection type %T", conn)
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		ucred   *unix.Ucred
+		credErr error
+	)
+	if err = rawConn.Control(func(fd uintptr) {
+		ucred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return nil, nil, err
+	}
+	if credErr != nil {
+		return nil, nil, fmt.Errorf("failed to read peer credentials: %v", credErr)
+	}
+	return conn, PeerAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity},
+		Pid:            ucred.Pid,
+		Uid:            ucred.Uid,
+		Gid:            ucred.Gid,
+	}, nil
+}
+
+func (peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+func (c peerCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (peerCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// PeerPolicy Which local processes may call the unix socket service.
+type PeerPolicy struct {
+	// The namespaces of the pods allowed to call.
+	Namespaces []string
+	// The service accounts of the pods allowed to call, in the format of namespace:name.
+	ServiceAccounts []string
+	// Whether processes that do not belong to any pod are allowed to call.
+	AllowHost bool
+}
+
+// Enabled Whether the policy restricts the pods or the host processes.
+func (p *PeerPolicy) Enabled() bool {
+	return p.restrictPods() || !p.AllowHost
+}
+
+// restrictPods Whether only the pods in the namespaces or with the service accounts are allowed, otherwise all pods are allowed.
+func (p *PeerPolicy) restrictPods() bool {
+	return len(p.Namespaces) > 0 || len(p.ServiceAccounts) > 0
+}
+
+type peerAuthorizer struct {
+	podLister       listerv1.PodLister
+	restrictPods    bool
+	namespaces      sets.Set[string]
+	serviceAccounts sets.Set[string]
+	allowHost       bool
+	// getCGroup Read the cgroup of the process, which is used to find the pod of the process.
+	getCGroup func(pid int32) (string, error)
+}
+
+func newPeerAuthorizer(podLister listerv1.PodLister, policy *PeerPolicy) *peerAuthorizer {
+	return &peerAuthorizer{
+		podLister:       podLister,
+		restrictPods:    policy.restrictPods(),
+		namespaces:      sets.New[string](policy.Namespaces...),
+		serviceAccounts: sets.New[string](policy.ServiceAccounts...),
+		allowHost:       policy.AllowHost,
+		getCGroup:       util.GetProcessCGroup,
+	}
+}
+
+// NewPeerAuthInterceptor Create the interceptor that rejects the callers not allowed by the policy with PermissionDenied.
+func NewPeerAuthInterceptor(podLister listerv1.PodLister, policy *PeerPolicy) grpc.UnaryServerInterceptor {
+	return newPeerAuthorizer(podLister, policy).intercept
+}
+
+// NewPeerAuthStreamInterceptor Create the stream interceptor that rejects the callers not allowed by the policy with PermissionDenied,
+// so that the streaming methods are restricted like the unary ones.
+func NewPeerAuthStreamInterceptor(podLister listerv1.PodLister, policy *PeerPolicy) grpc.StreamServerInterceptor {
+	return newPeerAuthorizer(podLister, policy).interceptStream
+}
+
+func (a *peerAuthorizer) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := a.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *peerAuthorizer) interceptStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// check Authorize the peer process of the call.
+func (a *peerAuthorizer) check(ctx context.Context, method string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "unknown peer")
+	}
+	authInfo, ok := p.AuthInfo.(PeerAuthInfo)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "missing peer credentials")
+	}
+	if err := a.authorize(authInfo); err != nil {
+		klog.Warningln("Reject local caller", "pid", authInfo.Pid, "uid", authInfo.Uid, "method", method, "reason", err)
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+func (a *peerAuthorizer) authorize(authInfo PeerAuthInfo) error {
+	content, err := a.getCGroup(authInfo.Pid)
+	if err != nil {
+		return fmt.Errorf("failed to read the cgroup of process %d: %v", authInfo.Pid, err)
+	}
+	pods, err := a.podLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	pod, inPod, err := util.FindPodByCGroup(content, pods)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the pod of process %d: %v", authInfo.Pid, err)
+	}
+	if !inPod {
+		if a.allowHost {
+			return nil
+		}
+		return fmt.Errorf("process %d does not belong to a pod", authInfo.Pid)
+	}
+	if !a.restrictPods {
+		return nil
+	}
+	serviceAccount := pod.Spec.ServiceAccountName
+	if len(serviceAccount) == 0 {
+		serviceAccount = "default"
+	}
+	if a.namespaces.Has(pod.Namespace) || a.serviceAccounts.Has(pod.Namespace+":"+serviceAccount) {
+		klog.V(4).Infoln("Accept local caller", "pid", authInfo.Pid, "pod", pod.Namespace+"/"+pod.Name)
+		return nil
+	}
+	return fmt.Errorf("pod %s/%s with service account %s is not allowed", pod.Namespace, pod.Name, serviceAccount)
+}
+
+// ParseServiceAccounts Validate the service accounts in the format of namespace:name.
+func ParseServiceAccounts(serviceAccounts []string) ([]string, error) {
+	var result []string
+	for _, serviceAccount := range serviceAccounts {
+		serviceAccount = strings.TrimSpace(serviceAccount)
+		if parts := strings.Split(serviceAccount, ":"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid service account %q, expected namespace:name", serviceAccount)
+		}
+		result = append(result, serviceAccount)
+	}
+	return result, nil
+}
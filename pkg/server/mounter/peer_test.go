@@ -0,0 +1,132 @@
+package mounter
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_PeerCredentials(t *testing.T) {
+	socketFile := filepath.Join(t.TempDir(), "test.sock")
+	listen, err := net.Listen("unix", socketFile)
+	assert.NoError(t, err)
+	defer listen.Close()
+
+	go func() {
+		conn, err := net.Dial("unix", socketFile)
+		if err == nil {
+			defer conn.Close()
+			_, _ = conn.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := listen.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, authInfo, err := NewPeerCredentials().ServerHandshake(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(os.Getpid()), authInfo.(PeerAuthInfo).Pid)
+}
+
+func Test_PeerAuthorizer(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "gpu-system", UID: "0f5b1a3e-7c1d-4e8a-9b2f-3d4c5e6f7a8b"},
+		Spec:       v1.PodSpec{ServiceAccountName: "agent"},
+		Status:     v1.PodStatus{QOSClass: v1.PodQOSBurstable},
+	}))
+	assert.NoError(t, indexer.Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "1a2b3c4d-7c1d-4e8a-9b2f-3d4c5e6f7a8b"},
+		Status:     v1.PodStatus{QOSClass: v1.PodQOSBestEffort},
+	}))
+	cgroups := map[int32]string{
+		1: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod0f5b1a3e_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice/cri-containerd-1.scope\n",
+		2: "0::/../../kubepods-besteffort-pod1a2b3c4d_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice/cri-containerd-2.scope\n",
+		3: "0::/system.slice/sshd.service\n",
+		4: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod9f5b1a3e_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice\n",
+	}
+	newAuthorizer := func(policy *PeerPolicy) *peerAuthorizer {
+		return &peerAuthorizer{
+			podLister:       listerv1.NewPodLister(indexer),
+			restrictPods:    policy.restrictPods(),
+			namespaces:      sets.New[string](policy.Namespaces...),
+			serviceAccounts: sets.New[string](policy.ServiceAccounts...),
+			allowHost:       policy.AllowHost,
+			getCGroup: func(pid int32) (string, error) {
+				return cgroups[pid], nil
+			},
+		}
+	}
+
+	byNamespace := newAuthorizer(&PeerPolicy{Namespaces: []string{"gpu-system"}})
+	assert.NoError(t, byNamespace.authorize(PeerAuthInfo{Pid: 1}))
+	assert.Error(t, byNamespace.authorize(PeerAuthInfo{Pid: 2}))
+	assert.Error(t, byNamespace.authorize(PeerAuthInfo{Pid: 3}))
+
+	byServiceAccount := newAuthorizer(&PeerPolicy{ServiceAccounts: []string{"default:default"}, AllowHost: true})
+	assert.Error(t, byServiceAccount.authorize(PeerAuthInfo{Pid: 1}))
+	assert.NoError(t, byServiceAccount.authorize(PeerAuthInfo{Pid: 2}))
+	assert.NoError(t, byServiceAccount.authorize(PeerAuthInfo{Pid: 3}))
+	// The processes of the pods not found on the node are rejected.
+	assert.Error(t, byServiceAccount.authorize(PeerAuthInfo{Pid: 4}))
+
+	// Only the host processes are restricted.
+	hostPolicy := &PeerPolicy{AllowHost: false}
+	assert.True(t, hostPolicy.Enabled())
+	assert.False(t, (&PeerPolicy{AllowHost: true}).Enabled())
+	denyHost := newAuthorizer(hostPolicy)
+	assert.NoError(t, denyHost.authorize(PeerAuthInfo{Pid: 1}))
+	assert.NoError(t, denyHost.authorize(PeerAuthInfo{Pid: 2}))
+	assert.Error(t, denyHost.authorize(PeerAuthInfo{Pid: 3}))
+
+	_, err := ParseServiceAccounts([]string{"default"})
+	assert.Error(t, err)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func Test_PeerAuthStreamInterceptor(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	authorizer := newPeerAuthorizer(listerv1.NewPodLister(indexer), &PeerPolicy{AllowHost: false})
+	authorizer.getCGroup = func(pid int32) (string, error) {
+		return "0::/system.slice/sshd.service\n", nil
+	}
+	called := false
+	handler := func(any, grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	// The host process is rejected before the handler runs.
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: PeerAuthInfo{Pid: 3}})
+	err := authorizer.interceptStream(nil, &fakeServerStream{ctx: ctx}, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+	err = authorizer.interceptStream(nil, &fakeServerStream{ctx: context.Background()}, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	authorizer.allowHost = true
+	assert.NoError(t, authorizer.interceptStream(nil, &fakeServerStream{ctx: ctx}, info, handler))
+	assert.True(t, called)
+}
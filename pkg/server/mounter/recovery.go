@@ -0,0 +1,271 @@
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	v1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// deferredEntries The journal entries of the device types not registered at startup by device type,
+// e.g. the device mounter plugins that register after the mounter starts.
+type deferredEntries struct {
+	lock    sync.Mutex
+	entries map[string][]*journal.Entry
+}
+
+func newDeferredEntries() *deferredEntries {
+	return &deferredEntries{entries: make(map[string][]*journal.Entry)}
+}
+
+// RecoverJournal Recover the operations left unfinished by the previous run of the mounter.
+// Incomplete mount operations are rolled back and incomplete unmount operations are replayed.
+// Entries that fail to recover are kept and retried on the next startup.
+// Entries of the device types not registered yet are deferred until the device types register, see RecoverDeferredJournal.
+func (s *DeviceMounterServer) RecoverJournal(ctx context.Context) error {
+	entries, err := s.journal.List()
+	if err != nil {
+		return fmt.Errorf("failed to read operation journal: %v", err)
+	}
+	// The plugins registering meanwhile recover their deferred entries after the lock is released.
+	s.deferred.lock.Lock()
+	defer s.deferred.lock.Unlock()
+	var errs []error
+	for _, entry := range entries {
+		if _, ok := framework.GetDeviceMounter(entry.DeviceType); !ok {
+			klog.Infoln("Defer recovering operation until the device type registers", "id", entry.ID,
+				"deviceType", entry.DeviceType)
+			deviceType := strings.ToUpper(entry.DeviceType)
+			s.deferred.entries[deviceType] = append(s.deferred.entries[deviceType], entry)
+			continue
+		}
+		if err = s.recoverEntry(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// RecoverDeferredJournal Recover the operations deferred at startup when their device type registers,
+// the entries that fail to recover are kept and retried on the next registration.
+func (s *DeviceMounterServer) RecoverDeferredJournal(ctx context.Context, deviceType string) error {
+	deviceType = strings.ToUpper(deviceType)
+	s.deferred.lock.Lock()
+	defer s.deferred.lock.Unlock()
+	var (
+		failed []*journal.Entry
+		errs   []error
+	)
+	for _, entry := range s.deferred.entries[deviceType] {
+		if err := s.recoverEntry(ctx, entry); err != nil {
+			failed = append(failed, entry)
+			errs = append(errs, err)
+		}
+	}
+	if len(failed) > 0 {
+		s.deferred.entries[deviceType] = failed
+	} else {
+		delete(s.deferred.entries, deviceType)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// recoverEntry Recover the operation of the entry and complete it. The deferred entries are recovered
+// while serving the requests, so the operation holds the lock of the container like the requests.
+func (s *DeviceMounterServer) recoverEntry(ctx context.Context, entry *journal.Entry) error {
+	klog.Infoln("Recovering unfinished operation", "id", entry.ID, "operation", entry.Operation,
+		"phase", entry.Phase, "deviceType", entry.DeviceType, "pod", entry.Pod.String(), "container", entry.ContainerName)
+	if entry.Pod.UID != nil {
+		operationType := MountOperationType
+		if entry.Operation == journal.UnMountOperation {
+			operationType = UnMountOperationType
+		}
+		release, err := s.locker.Acquire(ctx, types.UID(*entry.Pod.UID), entry.ContainerName, operationType)
+		if err != nil {
+			return fmt.Errorf("operation %s: %v", entry.ID, err)
+		}
+		defer release()
+	}
+	var err error
+	switch entry.Operation {
+	case journal.MountOperation:
+		err = s.revertMount(ctx, entry)
+	case journal.UnMountOperation:
+		err = s.replayUnMount(ctx, entry)
+	default:
+		err = fmt.Errorf("unknown operation %s", entry.Operation)
+	}
+	if err != nil {
+		klog.ErrorS(err, "Recover operation failed", "id", entry.ID)
+		return fmt.Errorf("operation %s: %v", entry.ID, err)
+	}
+	_ = s.journal.Complete(entry)
+	klog.Infoln("Successfully recovered operation", entry.ID)
+	return nil
+}
+
+// revertMount Roll back a mount operation: revoke the device permissions,
+// remove the device files and delete the slave pods created by the operation.
+func (s *DeviceMounterServer) revertMount(ctx context.Context, entry *journal.Entry) error {
+	if entry.Phase == journal.PhaseStarted {
+		return nil
+	}
+	pod, container, err := s.getRecoverTarget(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if pod != nil && container != nil && len(entry.DeviceInfos) > 0 {
+		deviceInfos := s.excludeMountedDevices(ctx, entry, pod, container)
+		if err = s.revokeDevices(pod, container, deviceInfos); err != nil {
+			return err
+		}
+	}
+	if len(entry.SlavePodIDs) == 0 {
+		return nil
+	}
+	requirement, err := labels.NewRequirement(config.CreatedByLabelKey, selection.In, entry.SlavePodIDs)
+	if err != nil {
+		return err
+	}
+	podList, err := s.kubeClient.CoreV1().Pods(entry.Pod.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.NewSelector().Add(*requirement).String(),
+	})
+	if err != nil {
+		return err
+	}
+	podKeys := make([]api.ObjectKey, len(podList.Items))
+	for i := range podList.Items {
+		podKeys[i] = api.ObjectKeyFromObject(&podList.Items[i])
+	}
+	if failed := GarbageCollectionPods(s.kubeClient, podKeys); len(failed) > 0 {
+		return fmt.Errorf("failed to delete slave pods %v", failed)
+	}
+	return nil
+}
+
+// replayUnMount Complete an unmount operation: revoke the device permissions,
+// remove the device files and delete the pods that need to be cleaned.
+func (s *DeviceMounterServer) replayUnMount(ctx context.Context, entry *journal.Entry) error {
+	if entry.Phase == journal.PhaseStarted {
+		return nil
+	}
+	pod, container, err := s.getRecoverTarget(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if pod != nil && container != nil {
+		if err = s.revokeDevices(pod, container, entry.DeviceInfos); err != nil {
+			return err
+		}
+	}
+	if failed := GarbageCollectionPods(s.kubeClient, entry.CleanupPods); len(failed) > 0 {
+		return fmt.Errorf("failed to delete slave pods %v", failed)
+	}
+	return nil
+}
+
+// getRecoverTarget Get the pod and container recorded in the entry.
+// When the pod has been deleted or the container has been restarted,
+// the devices no longer exist in the container and nil is returned.
+func (s *DeviceMounterServer) getRecoverTarget(ctx context.Context, entry *journal.Entry) (*v1.Pod, *api.Container, error) {
+	pod, err := s.kubeClient.CoreV1().Pods(entry.Pod.Namespace).Get(ctx, entry.Pod.Name, metav1.GetOptions{})
+	if apierror.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if entry.Pod.UID != nil && *entry.Pod.UID != string(pod.UID) {
+		return nil, nil, nil
+	}
+	container, err := CheckPodContainer(pod, &api.Container{Name: entry.ContainerName})
+	if err != nil {
+		return pod, nil, nil
+	}
+	if entry.ContainerID == "" || s.GetContainerID(pod, container) != entry.ContainerID {
+		klog.V(3).Infoln("Target container has been restarted, skip device recovery", "id", entry.ID)
+		return pod, nil, nil
+	}
+	return pod, container, nil
+}
+
+// excludeMountedDevices Exclude devices that are still used by other slave pods of the container,
+// such as the control devices shared between multiple mounted gpus.
+func (s *DeviceMounterServer) excludeMountedDevices(ctx context.Context, entry *journal.Entry, pod *v1.Pod, container *api.Container) []api.DeviceInfo {
+	deviceInfos := entry.DeviceInfos
+	deviceMounter, ok := framework.GetDeviceMounter(entry.DeviceType)
+	if !ok {
+		return deviceInfos
+	}
+	slavePods, err := s.GetSlavePods(entry.DeviceType, pod, container)
+	if err != nil {
+		return deviceInfos
+	}
+	// Keep only the slave pods that were not created by this operation.
+	slavePods = util.DeleteSliceFunc(slavePods, func(slavePod *v1.Pod) bool {
+		return !slices.Contains(entry.SlavePodIDs, slavePod.Labels[config.CreatedByLabelKey])
+	})
+	if len(slavePods) == 0 {
+		return deviceInfos
+	}
+	mounted, err := deviceMounter.GetDeviceInfosToUnmount(ctx, s.kubeClient, pod, container, slavePods)
+	if err != nil {
+		klog.V(4).ErrorS(err, "Get mounted device info error", "id", entry.ID)
+		return deviceInfos
+	}
+	return util.DeleteSliceFunc(slices.Clone(deviceInfos), func(info api.DeviceInfo) bool {
+		return !slices.ContainsFunc(mounted, func(dev api.DeviceInfo) bool {
+			return dev.Type == info.Type && dev.Major == info.Major && dev.Minor == info.Minor
+		})
+	})
+}
+
+// revokeDevices Deny access to the devices and delete the device files in the container.
+func (s *DeviceMounterServer) revokeDevices(pod *v1.Pod, container *api.Container, deviceInfos []api.DeviceInfo) error {
+	if len(deviceInfos) == 0 {
+		return nil
+	}
+	pids, cgroupPath, err := s.GetContainerCGroupPathAndPids(pod, container)
+	if err != nil {
+		return err
+	}
+	devInfos := make([]api.DeviceInfo, len(deviceInfos))
+	res := &configs.Resources{SkipDevices: false}
+	for i, info := range deviceInfos {
+		info.Allow = false
+		devInfos[i] = info
+		res.Devices = append(res.Devices, &devInfos[i].Rule)
+	}
+	closedFd, _, err := s.DeviceRuleSetFunc(cgroupPath, res)
+	if closedFd != nil {
+		defer closedFd()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke access permissions for cgroup devices: %v", err)
+	}
+	_, err = s.DeleteDeviceFiles(&util.Config{Target: pids[0], Mount: true}, devInfos)
+	return err
+}
+
+// recordPhase Record the completed step of the operation.
+// The step has already been executed, so a failure only affects recovery and is not returned.
+func (s *DeviceMounterServer) recordPhase(entry *journal.Entry, phase journal.Phase) {
+	if err := s.journal.Record(entry, phase); err != nil {
+		klog.Warningf("Failed to write operation journal %s: %v", entry.ID, err)
+	}
+}
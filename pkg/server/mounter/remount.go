@@ -0,0 +1,120 @@
+package mounter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	v1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// RemountDevices Re-apply the devices held by the slave pods to the restarted container of the owner pod,
+// and record the new container id on the slave pods.
+func (s *DeviceMounterServer) RemountDevices(ctx context.Context, pod *v1.Pod, containerName, deviceType string, slavePods []*v1.Pod) (err error) {
+	klog.Infoln("Remount devices", "pod", pod.Namespace+"/"+pod.Name, "container", containerName, "deviceType", deviceType)
+	var container *api.Container
+	if container, err = CheckPodContainer(pod, &api.Container{Name: containerName}); err != nil {
+		return err
+	}
+	if err = CheckPodContainerStatus(pod, container); err != nil {
+		return err
+	}
+	deviceMounter, ok := framework.GetDeviceMounter(deviceType)
+	if !ok {
+		return fmt.Errorf("Unsupported device type: %s", deviceType)
+	}
+	release, err := s.locker.Acquire(ctx, pod.UID, container.Name, RemountOperationType)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	deviceInfos, err := deviceMounter.GetDeviceInfosToMount(ctx, s.kubeClient, pod, container, slavePods)
+	if err != nil {
+		return fmt.Errorf("failed to detect mount device info: %v", err)
+	}
+	pids, cgroupPath, err := s.GetContainerCGroupPathAndPids(pod, container)
+	if err != nil {
+		return err
+	}
+	res := &configs.Resources{SkipDevices: false}
+	for i := range deviceInfos {
+		res.Devices = append(res.Devices, &deviceInfos[i].Rule)
+	}
+
+	var (
+		rollbackRules func() error
+		closedFd      func() error
+		rollbackFiles func() error
+	)
+	defer func() {
+		if err != nil {
+			if rollbackFiles != nil {
+				rErr := rollbackFiles()
+				klog.V(4).Infof("Roll back device files: %v", rErr)
+			}
+			if rollbackRules != nil {
+				rErr := rollbackRules()
+				klog.V(4).Infof("Roll back device rules: %v", rErr)
+			}
+		}
+		if closedFd != nil {
+			_ = closedFd()
+		}
+	}()
+
+	closedFd, rollbackRules, err = s.DeviceRuleSetFunc(cgroupPath, res)
+	if err != nil {
+		err = fmt.Errorf("failed to set access permissions for cgroup devices: %v", err)
+		return err
+	}
+	config := &util.Config{Target: pids[0], Mount: true}
+	rollbackFiles, err = s.CreateDeviceFiles(config, deviceInfos)
+	if err != nil {
+		err = fmt.Errorf("failed to create devic files: %v", err)
+		return err
+	}
+	err = deviceMounter.ExecutePostMountActions(ctx, s.kubeClient, *config, pod, container, slavePods)
+	if err != nil {
+		err = fmt.Errorf("failed to execute post mount actions: %v", err)
+		return err
+	}
+
+	if err = s.updateSlavePodsContainerID(ctx, slavePods, s.GetContainerID(pod, container)); err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Successfully remounted %s devices into restarted container %s", deviceType, containerName)
+	s.recorder.Event(pod, v1.EventTypeNormal, "RemountDevice", message)
+	klog.Infoln(message)
+	return nil
+}
+
+// updateSlavePodsContainerID Record the id of the container that the devices are mounted into.
+func (s *DeviceMounterServer) updateSlavePodsContainerID(ctx context.Context, slavePods []*v1.Pod, containerID string) error {
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{config.ContainerIdAnnotationKey: containerID},
+		},
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	for _, slavePod := range slavePods {
+		_, err = s.kubeClient.CoreV1().Pods(slavePod.Namespace).Patch(ctx, slavePod.Name,
+			types.MergePatchType, patchData, metav1.PatchOptions{})
+		if err != nil && !apierror.IsNotFound(err) {
+			return fmt.Errorf("failed to update slave pod %s/%s: %v", slavePod.Namespace, slavePod.Name, err)
+		}
+	}
+	return nil
+}
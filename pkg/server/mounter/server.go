@@ -2,13 +2,20 @@ package mounter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/coldzerofear/device-mounter/pkg/api"
+	"github.com/coldzerofear/device-mounter/pkg/config"
 	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/journal"
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
 	"github.com/coldzerofear/device-mounter/pkg/util"
 	"github.com/opencontainers/runc/libcontainer/configs"
+	"google.golang.org/protobuf/proto"
 	v1 "k8s.io/api/core/v1"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -20,30 +27,54 @@ import (
 )
 
 func NewDeviceMounterServer(
-	nodeName string, kubeClient *kubernetes.Clientset,
+	nodeName string, kubeClient kubernetes.Interface,
 	podLister listerv1.PodLister, nodeLister listerv1.NodeLister,
-	recorder record.EventRecorder) *DeviceMounterServer {
+	calculator util.CapacityCalculator, recorder record.EventRecorder,
+	journal *journal.Journal, lockPolicy ContainerLockPolicy) *DeviceMounterServer {
 	return &DeviceMounterServer{
 		nodeName:   nodeName,
 		kubeClient: kubeClient,
 		recorder:   recorder,
 		nodeLister: nodeLister,
 		podLister:  podLister,
+		calculator: calculator,
+		journal:    journal,
+		operations: newOperationManager(),
+		locker:     newContainerLocker(lockPolicy),
+		requests:   newRequestCache(journal),
+		deferred:   newDeferredEntries(),
+
+		mountedDevices: newMountedDevicesCollector(nodeName),
 	}
 }
 
 type DeviceMounterServer struct {
 	api.UnimplementedDeviceMountServiceServer
 	nodeName   string
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
 	recorder   record.EventRecorder
 	nodeLister listerv1.NodeLister
 	podLister  listerv1.PodLister
+	calculator util.CapacityCalculator
+	journal    *journal.Journal
+	operations *operationManager
+	locker     *containerLocker
+	requests   *requestCache
+	deferred   *deferredEntries
+
+	mountedDevices *mountedDevicesCollector
 }
 
 func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDeviceRequest) (resp *api.DeviceResponse, err error) {
 	klog.V(4).Infoln("MountDevice Called", "Request", req)
 
+	start := time.Now()
+	defer func() {
+		// The accepted asynchronous request is recorded when the operation runs.
+		if len(resp.GetOperationId()) == 0 {
+			metrics.ObserveRequest("MountDevice", strings.ToUpper(req.GetDeviceType()), resp.GetResult(), start)
+		}
+	}()
 	defer func() {
 		if err != nil && resp == nil {
 			mErr, ok := err.(*api.MounterError)
@@ -52,6 +83,11 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 					Result:  mErr.Code,
 					Message: mErr.Message,
 				}
+			} else if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+				resp = &api.DeviceResponse{
+					Result:  api.ResultCode_Timeout,
+					Message: err.Error(),
+				}
 			} else {
 				resp = &api.DeviceResponse{
 					Result:  api.ResultCode_Fail,
@@ -96,6 +132,22 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		return
 	}
 
+	// Run the mount in the background and return the operation id immediately.
+	if req.GetAsync() && !req.GetDryRun() {
+		asyncReq := proto.Clone(req).(*api.MountDeviceRequest)
+		asyncReq.Async = false
+		resp = s.startOperation(&api.Operation{
+			Type:         MountOperationType,
+			PodName:      pod.Name,
+			PodNamespace: pod.Namespace,
+			Container:    container.Name,
+			DeviceType:   deviceType,
+		}, func(ctx context.Context) (*api.DeviceResponse, error) {
+			return s.MountDevice(ctx, asyncReq)
+		})
+		return
+	}
+
 	// Resource name format conversion.
 	resources := make(map[v1.ResourceName]resource.Quantity)
 	var quantity resource.Quantity
@@ -107,6 +159,32 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		resources[v1.ResourceName(key)] = quantity
 	}
 
+	// The direct device mounters mount the devices without slave pods.
+	directMounter, direct := deviceMounter.(framework.DirectDeviceMounter)
+
+	// Serialize the operations that change the devices of the container.
+	if !req.GetDryRun() {
+		var release func()
+		if release, err = s.locker.Acquire(ctx, pod.UID, container.Name, MountOperationType); err != nil {
+			return
+		}
+		defer release()
+
+		// The repeated request returns the result of the devices mounted with the same request id.
+		if len(req.GetRequestId()) > 0 {
+			if direct {
+				resp, err = s.getDirectMountRequestResult(ctx, directMounter, deviceType,
+					req.GetRequestId(), pod, container, resources)
+			} else {
+				resp, err = s.getMountRequestResult(ctx, deviceMounter, deviceType,
+					req.GetRequestId(), requestDigest(req), pod, container)
+			}
+			if resp != nil || err != nil {
+				return
+			}
+		}
+	}
+
 	// get current node
 	node, err := s.nodeLister.Get(s.nodeName)
 	if apierror.IsNotFound(err) {
@@ -119,7 +197,7 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 	}
 
 	node = node.DeepCopy()
-	err = deviceMounter.ValidateMountRequest(ctx, s.kubeClient, node, pod,
+	err = deviceMounter.ValidateMountRequest(ctx, s.kubeClient, s.calculator, node, pod,
 		container, resources, req.GetAnnotations(), req.GetLabels())
 	if err != nil {
 		klog.V(3).ErrorS(err, "validate mount request failed")
@@ -127,21 +205,23 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 	}
 
 	var slavePods []*v1.Pod
-	slavePods, err = s.GetSlavePods(deviceType, pod, container)
-	if apierror.IsNotFound(err) {
-		klog.ErrorS(err, "Not found slave pods")
-		resp = &api.DeviceResponse{Result: api.ResultCode_NotFound, Message: err.Error()}
-		return
-	} else if err != nil {
-		klog.ErrorS(err, "Get slave pods failed")
-		return
-	}
+	if !direct {
+		slavePods, err = s.GetSlavePods(deviceType, pod, container)
+		if apierror.IsNotFound(err) {
+			klog.ErrorS(err, "Not found slave pods")
+			resp = &api.DeviceResponse{Result: api.ResultCode_NotFound, Message: err.Error()}
+			return
+		} else if err != nil {
+			klog.ErrorS(err, "Get slave pods failed")
+			return
+		}
 
-	// Build a list of pod templates to be created.
-	slavePods, err = deviceMounter.BuildSupportPodTemplates(ctx, pod, container, resources, req.GetAnnotations(), req.GetLabels(), slavePods)
-	if err != nil {
-		klog.V(3).ErrorS(err, "Build device slave pods failed")
-		return
+		// Build a list of pod templates to be created.
+		slavePods, err = deviceMounter.BuildSupportPodTemplates(ctx, pod, container, resources, req.GetAnnotations(), req.GetLabels(), slavePods)
+		if err != nil {
+			klog.V(3).ErrorS(err, "Build device slave pods failed")
+			return
+		}
 	}
 
 	for i, slavePod := range slavePods {
@@ -151,9 +231,50 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 			return
 		}
 		s.MutationPodFunc(deviceType, container, pod, targetPod)
+		if len(req.GetRequestId()) > 0 {
+			targetPod.Annotations[config.RequestIdAnnotationKey] = req.GetRequestId()
+			targetPod.Annotations[config.RequestDigestAnnotationKey] = requestDigest(req)
+		}
 		slavePods[i] = targetPod
 	}
 
+	if req.GetDryRun() {
+		var plannedPods []string
+		plannedPods, err = s.DryRunCreateSlavePods(ctx, slavePods)
+		if err != nil {
+			klog.V(3).ErrorS(err, "Dry run create slave pods failed")
+			err = fmt.Errorf("Device slave pod creation failed: %v", err)
+			return
+		}
+		message := fmt.Sprintf("Dry run: %s devices can be mounted", deviceType)
+		klog.V(3).Infoln(message)
+		resp = &api.DeviceResponse{
+			Result:           api.ResultCode_Success,
+			Message:          message,
+			PlannedSlavePods: plannedPods,
+		}
+		return
+	}
+
+	// Record the operation before making any changes, so that it can be rolled back after a crash.
+	var entry *journal.Entry
+	entry, err = s.journal.Begin(journal.MountOperation, deviceType,
+		api.ObjectKeyFromObject(pod), container.Name, s.GetContainerID(pod, container))
+	if err != nil {
+		err = fmt.Errorf("failed to write operation journal: %v", err)
+		return
+	}
+	defer func() {
+		_ = s.journal.Complete(entry)
+	}()
+	for _, slavePod := range slavePods {
+		entry.SlavePodIDs = append(entry.SlavePodIDs, slavePod.Labels[config.CreatedByLabelKey])
+	}
+	if err = s.journal.Record(entry, journal.PhaseSlavePodsPending); err != nil {
+		err = fmt.Errorf("failed to write operation journal: %v", err)
+		return
+	}
+
 	var (
 		slavePodKeys  []api.ObjectKey
 		rollbackRules func() error // 回滚设备规则方法
@@ -195,30 +316,37 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		}
 	}()
 
-	if err != nil || len(slavePodKeys) == 0 {
-		err = fmt.Errorf("Device slave pod creation failed: %v", err)
-		return
-	}
-	klog.Infoln("Successfully created slave pods", slavePodKeys)
 	var (
-		readyPods []*v1.Pod
-		skipPods  []*v1.Pod
+		readyPods   []*v1.Pod
+		skipPods    []*v1.Pod
+		deviceInfos []api.DeviceInfo
 	)
-	// Waiting for the created pods to be ready.
-	readyPods, skipPods, err = WaitSupportPodsReady(ctx, s.podLister, s.kubeClient, deviceMounter, slavePodKeys)
-	if err != nil {
-		klog.V(4).ErrorS(err, "Wait slave pods ready failed")
-		return
-	}
-	// 如果此时没有ready的pod则返回错误
-	if len(readyPods) == 0 {
-		err = fmt.Errorf("waiting for device pod to be ready failed")
-		return
-	}
+	if direct {
+		// Get the devices of the request.
+		deviceInfos, err = directMounter.GetRequestedDeviceInfos(ctx, pod, container, resources)
+	} else {
+		if err != nil || len(slavePodKeys) == 0 {
+			err = fmt.Errorf("Device slave pod creation failed: %v", err)
+			return
+		}
+		klog.Infoln("Successfully created slave pods", slavePodKeys)
+		setOperationStage(ctx, StageSlavePodsCreated)
+		// Waiting for the created pods to be ready.
+		readyPods, skipPods, err = WaitSupportPodsReady(ctx, s.podLister, s.kubeClient, deviceMounter, slavePodKeys)
+		if err != nil {
+			klog.V(4).ErrorS(err, "Wait slave pods ready failed")
+			return
+		}
+		// 如果此时没有ready的pod则返回错误
+		if len(readyPods) == 0 {
+			err = fmt.Errorf("waiting for device pod to be ready failed")
+			return
+		}
+		setOperationStage(ctx, StageSlavePodsReady)
 
-	// Get device mounting list information.
-	var deviceInfos []api.DeviceInfo
-	deviceInfos, err = deviceMounter.GetDeviceInfosToMount(ctx, s.kubeClient, pod, container, readyPods)
+		// Get device mounting list information.
+		deviceInfos, err = deviceMounter.GetDeviceInfosToMount(ctx, s.kubeClient, pod, container, readyPods)
+	}
 	if err != nil {
 		klog.V(4).ErrorS(err, "Get mount device info error")
 		if _, ok = err.(*api.MounterError); !ok {
@@ -245,12 +373,20 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		res.Devices = append(res.Devices, &deviceInfos[i].Rule)
 	}
 
+	entry.DeviceInfos = deviceInfos
+	if err = s.journal.Record(entry, journal.PhaseDeviceRulesPending); err != nil {
+		err = fmt.Errorf("failed to write operation journal: %v", err)
+		return
+	}
+
 	closedFd, rollbackRules, err = s.DeviceRuleSetFunc(cgroupPath, res)
 	if err != nil {
 		klog.V(4).ErrorS(err, "set cgroup device permissions error")
 		err = fmt.Errorf("failed to set access permissions for cgroup devices: %v", err)
 		return
 	}
+	s.recordPhase(entry, journal.PhaseDeviceRulesSet)
+	setOperationStage(ctx, StageDeviceRulesSet)
 
 	config := &util.Config{Target: pids[0], Mount: true}
 	rollbackFiles, err = s.CreateDeviceFiles(config, deviceInfos)
@@ -259,6 +395,8 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		err = fmt.Errorf("failed to create devic files: %v", err)
 		return
 	}
+	s.recordPhase(entry, journal.PhaseDeviceFilesSet)
+	setOperationStage(ctx, StageDeviceFilesSet)
 
 	err = deviceMounter.ExecutePostMountActions(ctx, s.kubeClient, *config, pod, container, readyPods)
 	if err != nil {
@@ -268,6 +406,15 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 		}
 		return
 	}
+	setOperationStage(ctx, StagePostActionsExecuted)
+
+	if direct {
+		if err = directMounter.RecordDevices(ctx, s.kubeClient, pod, container, req.GetRequestId(), deviceInfos, true); err != nil {
+			klog.V(4).ErrorS(err, "Record mounted devices error")
+			err = fmt.Errorf("failed to record mounted devices: %v", err)
+			return
+		}
+	}
 
 	// Delete the previously skipped pod list.
 	skipPodKeys := make([]api.ObjectKey, len(skipPods))
@@ -276,16 +423,32 @@ func (s *DeviceMounterServer) MountDevice(ctx context.Context, req *api.MountDev
 	}
 	_ = GarbageCollectionPods(s.kubeClient, skipPodKeys)
 
+	readyPodKeys := make([]string, len(readyPods))
+	for i, readyPod := range readyPods {
+		readyPodKeys[i] = api.ObjectKeyFromObject(readyPod).String()
+	}
 	message := fmt.Sprintf("Successfully mounted %s devices", deviceType)
 	s.recorder.Event(pod, v1.EventTypeNormal, "MountDevice", message)
 	klog.Infoln(deviceType, "MountDevice Successfully")
-	resp = &api.DeviceResponse{Result: api.ResultCode_Success, Message: message}
+	resp = &api.DeviceResponse{
+		Result:    api.ResultCode_Success,
+		Message:   message,
+		Devices:   NewMountedDevices(deviceInfos),
+		SlavePods: readyPodKeys,
+	}
 	return
 }
 
 func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMountDeviceRequest) (resp *api.DeviceResponse, err error) {
 	klog.V(4).Infoln("UnMountDevice Called", "Request", req)
 
+	start := time.Now()
+	defer func() {
+		// The accepted asynchronous request is recorded when the operation runs.
+		if len(resp.GetOperationId()) == 0 {
+			metrics.ObserveRequest("UnMountDevice", strings.ToUpper(req.GetDeviceType()), resp.GetResult(), start)
+		}
+	}()
 	defer func() {
 		if err != nil && resp == nil {
 			mErr, ok := err.(*api.MounterError)
@@ -294,6 +457,11 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 					Result:  mErr.Code,
 					Message: mErr.Message,
 				}
+			} else if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+				resp = &api.DeviceResponse{
+					Result:  api.ResultCode_Timeout,
+					Message: err.Error(),
+				}
 			} else {
 				resp = &api.DeviceResponse{
 					Result:  api.ResultCode_Fail,
@@ -338,25 +506,105 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 		return
 	}
 
-	// Query the slave pods to which the current container belongs.
-	slavePods, err := s.GetSlavePods(deviceType, pod, container)
-	if apierror.IsNotFound(err) {
-		klog.ErrorS(err, "Not found slave pods")
-		resp = &api.DeviceResponse{Result: api.ResultCode_NotFound, Message: err.Error()}
+	// Serialize the operations that change the devices of the container,
+	// the asynchronous request acquires the lock when the operation runs.
+	if !req.GetAsync() {
+		var release func()
+		if release, err = s.locker.Acquire(ctx, pod.UID, container.Name, UnMountOperationType); err != nil {
+			return
+		}
+		defer release()
+	}
+
+	// The repeated request returns the result of the finished request with the same request id.
+	var requestKey string
+	if len(req.GetRequestId()) > 0 {
+		requestKey = requestCacheKey(UnMountOperationType, pod.UID, container.Name, req.GetRequestId())
+		cached, found, cacheErr := s.requests.Get(requestKey, requestDigest(req))
+		if cacheErr != nil {
+			err = requestConflictError(req.GetRequestId())
+			return
+		} else if found {
+			klog.Infoln("Devices already unmounted by request", "requestId", req.GetRequestId())
+			resp = cached
+			return
+		}
+	}
+
+	// The direct device mounters record the mounted devices without slave pods.
+	directMounter, direct := deviceMounter.(framework.DirectDeviceMounter)
+	if !direct && len(req.GetResources()) > 0 {
+		msg := fmt.Sprintf("Device type %s does not support unmounting by resources", deviceType)
+		err = api.NewMounterError(api.ResultCode_Invalid, msg)
 		return
-	} else if err != nil {
-		klog.ErrorS(err, "Get slave pods failed")
+	}
+	var slavePods []*v1.Pod
+	if !direct {
+		// Query the slave pods to which the current container belongs.
+		slavePods, err = s.GetSlavePods(deviceType, pod, container)
+		if apierror.IsNotFound(err) {
+			klog.ErrorS(err, "Not found slave pods")
+			resp = &api.DeviceResponse{Result: api.ResultCode_NotFound, Message: err.Error()}
+			return
+		} else if err != nil {
+			klog.ErrorS(err, "Get slave pods failed")
+			return
+		}
+		// Only unmount the devices of the slave pods created by the mount request.
+		if len(req.GetMountRequestId()) > 0 {
+			slavePods = filterRequestSlavePods(slavePods, req.GetMountRequestId())
+		}
+
+		if len(slavePods) == 0 {
+			msg := fmt.Sprintf("No device found for uninstallation")
+			err = api.NewMounterError(api.ResultCode_NotFound, msg)
+			return
+		}
+	}
+
+	// Run the unmount in the background and return the operation id immediately.
+	if req.GetAsync() {
+		asyncReq := proto.Clone(req).(*api.UnMountDeviceRequest)
+		asyncReq.Async = false
+		resp = s.startOperation(&api.Operation{
+			Type:         UnMountOperationType,
+			PodName:      pod.Name,
+			PodNamespace: pod.Namespace,
+			Container:    container.Name,
+			DeviceType:   deviceType,
+		}, func(ctx context.Context) (*api.DeviceResponse, error) {
+			return s.UnMountDevice(ctx, asyncReq)
+		})
 		return
 	}
 
-	if len(slavePods) == 0 {
-		msg := fmt.Sprintf("No device found for uninstallation")
-		err = api.NewMounterError(api.ResultCode_NotFound, msg)
+	// Record the operation before making any changes, so that it can be replayed after a crash.
+	var entry *journal.Entry
+	entry, err = s.journal.Begin(journal.UnMountOperation, deviceType,
+		api.ObjectKeyFromObject(pod), container.Name, s.GetContainerID(pod, container))
+	if err != nil {
+		err = fmt.Errorf("failed to write operation journal: %v", err)
 		return
 	}
+	defer func() {
+		_ = s.journal.Complete(entry)
+	}()
 
 	// Retrieve the list of device information to be uninstalled.
-	deviceInfos, err := deviceMounter.GetDeviceInfosToUnmount(ctx, s.kubeClient, pod, container, slavePods)
+	var deviceInfos []api.DeviceInfo
+	if direct && len(req.GetMountRequestId()) > 0 {
+		// Only unmount the devices recorded for the mount request.
+		deviceInfos, err = directMounter.GetRequestMountedDeviceInfos(ctx, s.kubeClient, pod, container, req.GetMountRequestId())
+		for i := range deviceInfos {
+			deviceInfos[i].Rule.Allow = false
+		}
+	} else {
+		deviceInfos, err = deviceMounter.GetDeviceInfosToUnmount(ctx, s.kubeClient, pod, container, slavePods)
+	}
+	if err == nil && direct && len(req.GetResources()) > 0 {
+		// Only unmount the devices of the requested resources, the other devices stay mounted.
+		deviceInfos, err = FilterRequestedDeviceInfos(ctx, directMounter, pod, container, req.GetResources(), deviceInfos)
+	}
 	if err != nil {
 		klog.V(4).ErrorS(err, "Get unmount device info error")
 		if _, ok = err.(*api.MounterError); !ok {
@@ -364,6 +612,11 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 		}
 		return
 	}
+	if direct && len(deviceInfos) == 0 {
+		msg := fmt.Sprintf("No device found for uninstallation")
+		err = api.NewMounterError(api.ResultCode_NotFound, msg)
+		return
+	}
 
 	var (
 		pids       []int
@@ -406,6 +659,15 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 		res.Devices = append(res.Devices, &deviceInfos[i].Rule)
 	}
 
+	// Get the list of pods that need to be cleaned together with the uninstallation device operation.
+	gcPodKeys := deviceMounter.GetPodsToCleanup(ctx, s.kubeClient, pod, container, slavePods)
+	entry.DeviceInfos = deviceInfos
+	entry.CleanupPods = gcPodKeys
+	if err = s.journal.Record(entry, journal.PhaseDeviceRulesPending); err != nil {
+		err = fmt.Errorf("failed to write operation journal: %v", err)
+		return
+	}
+
 	var (
 		rollbackRules func() error
 		closedFd      func() error
@@ -437,12 +699,17 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 		return
 	}
 
+	s.recordPhase(entry, journal.PhaseDeviceRulesSet)
+	setOperationStage(ctx, StageDeviceRulesSet)
+
 	rollbackFiles, err = s.DeleteDeviceFiles(config, deviceInfos)
 	if err != nil {
 		klog.V(4).ErrorS(err, "Delete Device Files error")
 		err = fmt.Errorf("failed to delete devic files: %v", err)
 		return
 	}
+	s.recordPhase(entry, journal.PhaseDeviceFilesSet)
+	setOperationStage(ctx, StageDeviceFilesSet)
 	err = deviceMounter.ExecutePostUnmountActions(ctx, s.kubeClient, *config, pod, container, slavePods)
 	if err != nil {
 		klog.Warningf("execute post unmount actions error: %v", err)
@@ -451,13 +718,222 @@ func (s *DeviceMounterServer) UnMountDevice(ctx context.Context, req *api.UnMoun
 		}
 		return
 	}
-	// Get the list of pods that need to be cleaned together with the uninstallation device operation.
-	gcPodKeys := deviceMounter.GetPodsToCleanup(ctx, s.kubeClient, pod, container, slavePods)
+	setOperationStage(ctx, StagePostActionsExecuted)
+	if direct {
+		if err = directMounter.RecordDevices(ctx, s.kubeClient, pod, container, "", deviceInfos, false); err != nil {
+			klog.V(4).ErrorS(err, "Record unmounted devices error")
+			err = fmt.Errorf("failed to record unmounted devices: %v", err)
+			return
+		}
+	}
 	_ = GarbageCollectionPods(s.kubeClient, gcPodKeys)
+	setOperationStage(ctx, StageSlavePodsCleaned)
 
 	message := fmt.Sprintf("Successfully uninstalled %s devices", deviceType)
 	s.recorder.Event(pod, v1.EventTypeNormal, "UnMountDevice", message)
 	klog.Infoln(deviceType, "UnMountDevice Successfully")
-	resp = &api.DeviceResponse{Result: api.ResultCode_Success, Message: message}
+	gcPodNames := make([]string, len(gcPodKeys))
+	for i, gcPodKey := range gcPodKeys {
+		gcPodNames[i] = gcPodKey.String()
+	}
+	resp = &api.DeviceResponse{
+		Result:    api.ResultCode_Success,
+		Message:   message,
+		Devices:   NewMountedDevices(deviceInfos),
+		SlavePods: gcPodNames,
+	}
+	if len(requestKey) > 0 {
+		s.requests.Put(requestKey, requestDigest(req), resp)
+	}
+	return
+}
+
+func (s *DeviceMounterServer) ListMountedDevices(ctx context.Context, req *api.ListMountedDevicesRequest) (resp *api.ListMountedDevicesResponse, err error) {
+	klog.V(4).Infoln("ListMountedDevices Called", "Request", req)
+
+	defer func() {
+		if err != nil && resp == nil {
+			mErr, ok := err.(*api.MounterError)
+			if ok {
+				resp = &api.ListMountedDevicesResponse{
+					Result:  mErr.Code,
+					Message: mErr.Message,
+				}
+			} else {
+				resp = &api.ListMountedDevicesResponse{
+					Result:  api.ResultCode_Fail,
+					Message: err.Error(),
+				}
+			}
+		}
+		if resp != nil {
+			err = nil
+		}
+	}()
+
+	if err = CheckListMountedDevicesRequest(req); err != nil {
+		klog.V(4).Infoln(err.Error())
+		return
+	}
+
+	var pod *v1.Pod
+	pod, err = s.GetTargetPod(ctx, req.PodName, req.PodNamespace)
+	if apierror.IsNotFound(err) {
+		klog.ErrorS(err, "Not found pod", "name", req.PodName, "namespace", req.PodNamespace)
+		resp = &api.ListMountedDevicesResponse{Result: api.ResultCode_NotFound, Message: err.Error()}
+		return
+	} else if err != nil {
+		klog.ErrorS(err, "Get target Pod failed", "name", req.PodName, "namespace", req.PodNamespace)
+		return
+	}
+
+	// If no container is specified, query all containers of the pod.
+	var containers []*api.Container
+	if req.GetContainer() != nil {
+		var container *api.Container
+		if container, err = CheckPodContainer(pod, req.GetContainer()); err != nil {
+			return
+		}
+		containers = append(containers, container)
+	} else {
+		containers = GetPodContainers(pod)
+	}
+
+	// If no device type is specified, query all registered device types.
+	deviceTypes := framework.GetDeviceMounterTypes()
+	if len(req.GetDeviceType()) > 0 {
+		deviceType := strings.ToUpper(req.GetDeviceType())
+		if _, ok := framework.GetDeviceMounter(deviceType); !ok {
+			err = fmt.Errorf("Unsupported device type: %s", req.GetDeviceType())
+			return
+		}
+		deviceTypes = []string{deviceType}
+	}
+	sort.Strings(deviceTypes)
+
+	var items []*api.ContainerDevices
+	for _, container := range containers {
+		for _, deviceType := range deviceTypes {
+			deviceMounter, _ := framework.GetDeviceMounter(deviceType)
+			_, direct := deviceMounter.(framework.DirectDeviceMounter)
+			var slavePods []*v1.Pod
+			if !direct {
+				slavePods, err = s.GetSlavePods(deviceType, pod, container)
+				if err != nil {
+					klog.ErrorS(err, "Get slave pods failed")
+					return
+				}
+				if len(slavePods) == 0 {
+					continue
+				}
+			}
+			var deviceInfos []api.DeviceInfo
+			deviceInfos, err = deviceMounter.GetDeviceInfosToUnmount(ctx, s.kubeClient, pod, container, slavePods)
+			if err != nil {
+				klog.V(4).ErrorS(err, "Get mounted device info error")
+				if _, ok := err.(*api.MounterError); !ok {
+					err = fmt.Errorf("Failed to detect mounted device info: %v", err)
+				}
+				return
+			}
+			if direct && len(deviceInfos) == 0 {
+				continue
+			}
+			slavePodKeys := make([]string, len(slavePods))
+			for i, slavePod := range slavePods {
+				slavePodKeys[i] = api.ObjectKeyFromObject(slavePod).String()
+			}
+			items = append(items, &api.ContainerDevices{
+				Container:  container,
+				DeviceType: deviceType,
+				Devices:    NewMountedDevices(deviceInfos),
+				SlavePods:  slavePodKeys,
+			})
+		}
+	}
+
+	resp = &api.ListMountedDevicesResponse{Result: api.ResultCode_Success, Items: items}
+	return
+}
+
+func (s *DeviceMounterServer) GetOperation(_ context.Context, req *api.OperationRequest) (resp *api.OperationResponse, err error) {
+	klog.V(4).Infoln("GetOperation Called", "Request", req)
+
+	defer func() {
+		if err != nil && resp == nil {
+			mErr, ok := err.(*api.MounterError)
+			if ok {
+				resp = &api.OperationResponse{
+					Result:  mErr.Code,
+					Message: mErr.Message,
+				}
+			} else {
+				resp = &api.OperationResponse{
+					Result:  api.ResultCode_Fail,
+					Message: err.Error(),
+				}
+			}
+		}
+		if resp != nil {
+			err = nil
+		}
+	}()
+
+	if err = CheckOperationRequest(req); err != nil {
+		klog.V(4).Infoln(err.Error())
+		return
+	}
+
+	var o *operation
+	if o, err = s.getOperation(req); err != nil {
+		return
+	}
+	resp = &api.OperationResponse{Result: api.ResultCode_Success, Operation: o.snapshot()}
+	return
+}
+
+func (s *DeviceMounterServer) CancelOperation(_ context.Context, req *api.OperationRequest) (resp *api.OperationResponse, err error) {
+	klog.V(4).Infoln("CancelOperation Called", "Request", req)
+
+	defer func() {
+		if err != nil && resp == nil {
+			mErr, ok := err.(*api.MounterError)
+			if ok {
+				resp = &api.OperationResponse{
+					Result:  mErr.Code,
+					Message: mErr.Message,
+				}
+			} else {
+				resp = &api.OperationResponse{
+					Result:  api.ResultCode_Fail,
+					Message: err.Error(),
+				}
+			}
+		}
+		if resp != nil {
+			err = nil
+		}
+	}()
+
+	if err = CheckOperationRequest(req); err != nil {
+		klog.V(4).Infoln(err.Error())
+		return
+	}
+
+	var o *operation
+	if o, err = s.getOperation(req); err != nil {
+		return
+	}
+	if o.finished() {
+		err = api.NewMounterError(api.ResultCode_Invalid, fmt.Sprintf("Operation %s has already finished", req.OperationId))
+		return
+	}
+	s.operations.Cancel(req.OperationId)
+	klog.Infoln("Cancel operation", "id", req.OperationId)
+	resp = &api.OperationResponse{
+		Result:    api.ResultCode_Success,
+		Message:   "Operation cancellation requested",
+		Operation: o.snapshot(),
+	}
 	return
 }
@@ -3,13 +3,16 @@ package mounter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/config"
 	"github.com/coldzerofear/device-mounter/pkg/framework"
+	"github.com/coldzerofear/device-mounter/pkg/metrics"
 	"github.com/coldzerofear/device-mounter/pkg/util"
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/google/uuid"
@@ -18,6 +21,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -32,14 +36,16 @@ import (
 
 // CheckPodContainerStatus Check if the specified container status is normal
 func CheckPodContainerStatus(pod *v1.Pod, cont *api.Container) error {
-	for _, containerStatus := range pod.Status.ContainerStatuses {
-		if containerStatus.Name == cont.Name {
-			//if containerStatus.Started != nil && *containerStatus.Started {
-			//	return nil
-			//}
-			if containerStatus.Ready {
-				return nil
-			}
+	if containerStatus, ok := util.GetContainerStatus(pod, cont.Name); ok {
+		//if containerStatus.Started != nil && *containerStatus.Started {
+		//	return nil
+		//}
+		// Ephemeral containers have no probes and never report ready.
+		if cont.GetType() == api.ContainerType_Ephemeral && containerStatus.State.Running != nil {
+			return nil
+		}
+		if containerStatus.Ready {
+			return nil
 		}
 	}
 	return fmt.Errorf("the target container %s is not ready", cont.Name)
@@ -85,10 +91,47 @@ func CheckPodContainer(pod *v1.Pod, cont *api.Container) (*api.Container, error)
 			return ctr, nil
 		}
 	}
+	if len(cont.Name) > 0 {
+		for i, container := range pod.Spec.InitContainers {
+			if container.Name != cont.Name {
+				continue
+			}
+			if !util.IsSidecar(container) {
+				msg := "Target container is an init container, only sidecar containers are supported"
+				return nil, api.NewMounterError(api.ResultCode_Invalid, msg)
+			}
+			return &api.Container{Name: cont.Name, Index: uint32(i), Type: api.ContainerType_Sidecar}, nil
+		}
+		for i, container := range pod.Spec.EphemeralContainers {
+			if container.Name == cont.Name {
+				return &api.Container{Name: cont.Name, Index: uint32(i), Type: api.ContainerType_Ephemeral}, nil
+			}
+		}
+	}
 	msg := fmt.Sprintf("target container %v not found", cont)
 	return nil, api.NewMounterError(api.ResultCode_Invalid, msg)
 }
 
+// GetPodContainers Get all containers of the pod that devices can be mounted into,
+// including sidecar containers and ephemeral containers.
+func GetPodContainers(pod *v1.Pod) []*api.Container {
+	var containers []*api.Container
+	for i, container := range pod.Spec.Containers {
+		containers = append(containers, &api.Container{Name: container.Name, Index: uint32(i)})
+	}
+	for i, container := range pod.Spec.InitContainers {
+		if util.IsSidecar(container) {
+			containers = append(containers, &api.Container{
+				Name: container.Name, Index: uint32(i), Type: api.ContainerType_Sidecar})
+		}
+	}
+	for i, container := range pod.Spec.EphemeralContainers {
+		containers = append(containers, &api.Container{
+			Name: container.Name, Index: uint32(i), Type: api.ContainerType_Ephemeral})
+	}
+	return containers
+}
+
 // CheckMountDeviceRequest Check if the device request is legitimate.
 func CheckMountDeviceRequest(req *api.MountDeviceRequest) error {
 	var paramNames []string
@@ -105,6 +148,9 @@ func CheckMountDeviceRequest(req *api.MountDeviceRequest) error {
 		msg := fmt.Sprintf("parameters [%s] cannot be empty", strings.Join(paramNames, ","))
 		return api.NewMounterError(api.ResultCode_Invalid, msg)
 	}
+	if err := checkRequestID("request_id", req.GetRequestId()); err != nil {
+		return err
+	}
 	// TODO If no container to be mounted is specified, index 0 is selected by default.
 	if req.GetContainer() == nil {
 		req.Container = &api.Container{Index: 0}
@@ -130,6 +176,12 @@ func CheckUnMountDeviceRequest(req *api.UnMountDeviceRequest) error {
 		msg := fmt.Sprintf("parameters [%s] cannot be empty", strings.Join(paramNames, ","))
 		return api.NewMounterError(api.ResultCode_Invalid, msg)
 	}
+	if err := checkRequestID("request_id", req.GetRequestId()); err != nil {
+		return err
+	}
+	if err := checkRequestID("mount_request_id", req.GetMountRequestId()); err != nil {
+		return err
+	}
 	// TODO 没指定要卸载的容器，默认选择index0
 	if req.GetContainer() == nil {
 		req.Container = &api.Container{Index: 0}
@@ -137,8 +189,91 @@ func CheckUnMountDeviceRequest(req *api.UnMountDeviceRequest) error {
 	return nil
 }
 
+func CheckListMountedDevicesRequest(req *api.ListMountedDevicesRequest) error {
+	var paramNames []string
+	if len(req.GetPodName()) == 0 {
+		paramNames = append(paramNames, "'pod_name'")
+	}
+	if len(req.GetPodNamespace()) == 0 {
+		paramNames = append(paramNames, "'pod_namespace'")
+	}
+	if len(paramNames) > 0 {
+		msg := fmt.Sprintf("parameters [%s] cannot be empty", strings.Join(paramNames, ","))
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
+	}
+	return nil
+}
+
+func CheckOperationRequest(req *api.OperationRequest) error {
+	var paramNames []string
+	if len(req.GetPodName()) == 0 {
+		paramNames = append(paramNames, "'pod_name'")
+	}
+	if len(req.GetPodNamespace()) == 0 {
+		paramNames = append(paramNames, "'pod_namespace'")
+	}
+	if len(req.GetOperationId()) == 0 {
+		paramNames = append(paramNames, "'operation_id'")
+	}
+	if len(paramNames) > 0 {
+		msg := fmt.Sprintf("parameters [%s] cannot be empty", strings.Join(paramNames, ","))
+		return api.NewMounterError(api.ResultCode_Invalid, msg)
+	}
+	return nil
+}
+
+// NewMountedDevices Convert device information into the structure returned by the api.
+func NewMountedDevices(deviceInfos []api.DeviceInfo) []*api.MountedDevice {
+	mountedDevices := make([]*api.MountedDevice, len(deviceInfos))
+	for i, info := range deviceInfos {
+		mountedDevices[i] = &api.MountedDevice{
+			DeviceId:       info.DeviceID,
+			DeviceFilePath: info.DeviceFilePath,
+			Type:           string(info.Type),
+			Major:          info.Major,
+			Minor:          info.Minor,
+			Permissions:    string(info.Permissions),
+		}
+	}
+	return mountedDevices
+}
+
+// FilterRequestedDeviceInfos Keep the mounted devices of the requested resources,
+// a requested device that is not mounted into the container is not found.
+func FilterRequestedDeviceInfos(ctx context.Context, directMounter framework.DirectDeviceMounter, pod *v1.Pod,
+	container *api.Container, resources map[string]string, mountedInfos []api.DeviceInfo) ([]api.DeviceInfo, error) {
+
+	request := make(map[v1.ResourceName]resource.Quantity, len(resources))
+	for key, val := range resources {
+		quantity, err := resource.ParseQuantity(val)
+		if err != nil {
+			msg := fmt.Sprintf("Request for resources error: invalid quantity %s of %s", val, key)
+			return nil, api.NewMounterError(api.ResultCode_Invalid, msg)
+		}
+		request[v1.ResourceName(key)] = quantity
+	}
+	requestedInfos, err := directMounter.GetRequestedDeviceInfos(ctx, pod, container, request)
+	if err != nil {
+		return nil, err
+	}
+	mounted := make(map[string]api.DeviceInfo, len(mountedInfos))
+	for _, info := range mountedInfos {
+		mounted[info.DeviceFilePath] = info
+	}
+	deviceInfos := make([]api.DeviceInfo, 0, len(requestedInfos))
+	for _, info := range requestedInfos {
+		mountedInfo, ok := mounted[info.DeviceFilePath]
+		if !ok {
+			msg := fmt.Sprintf("Device %s is not mounted into container %s", info.DeviceFilePath, container.Name)
+			return nil, api.NewMounterError(api.ResultCode_NotFound, msg)
+		}
+		deviceInfos = append(deviceInfos, mountedInfo)
+	}
+	return deviceInfos, nil
+}
+
 // GarbageCollectionPods Batch delete pods
-func GarbageCollectionPods(kubeClient *kubernetes.Clientset, objKeys []api.ObjectKey) []api.ObjectKey {
+func GarbageCollectionPods(kubeClient kubernetes.Interface, objKeys []api.ObjectKey) []api.ObjectKey {
 	var (
 		err          error
 		deleteFailed []api.ObjectKey
@@ -161,6 +296,7 @@ func GarbageCollectionPods(kubeClient *kubernetes.Clientset, objKeys []api.Objec
 		}
 		if err != nil {
 			deleteFailed = append(deleteFailed, objKeys[i])
+			metrics.GarbageCollectionFailures.Inc()
 			klog.Errorf("GC pod %s failed: %v", objKey.String(), err)
 		}
 	}
@@ -169,7 +305,7 @@ func GarbageCollectionPods(kubeClient *kubernetes.Clientset, objKeys []api.Objec
 
 // WaitSupportPodsReady Waiting for the status of the created pods to be ready.
 func WaitSupportPodsReady(ctx context.Context, podLister listerv1.PodLister,
-	kubeClient *kubernetes.Clientset, deviceMounter framework.DeviceMounter,
+	kubeClient kubernetes.Interface, deviceMounter framework.DeviceMounter,
 	slavePodKeys []api.ObjectKey) ([]*v1.Pod, []*v1.Pod, error) {
 
 	readySlavePods := make([]*v1.Pod, 0)
@@ -212,10 +348,13 @@ func WaitSupportPodsReady(ctx context.Context, podLister listerv1.PodLister,
 				skipSlavePods = append(skipSlavePods, slavePod.DeepCopy())
 				continue
 			case api.Unschedulable:
-				if err == nil {
-					err = fmt.Errorf("slave pod <%s> is not schedulable", slaveKey.String())
+				msg := fmt.Sprintf("slave pod <%s> is not schedulable", slaveKey.String())
+				if mErr, ok := err.(*api.MounterError); ok {
+					msg = mErr.Message
+				} else if err != nil {
+					msg = fmt.Sprintf("%s: %v", msg, err)
 				}
-				return true, err
+				return true, api.NewMounterError(api.ResultCode_Unschedulable, msg)
 			case api.Fail:
 				if err == nil {
 					err = fmt.Errorf("failed to verify slave pod <%s> status", slaveKey.String())
@@ -229,7 +368,12 @@ func WaitSupportPodsReady(ctx context.Context, podLister listerv1.PodLister,
 		}
 		return true, nil
 	}
+	start := time.Now()
 	err := wait.PollUntilContextCancel(ctx, 100*time.Millisecond, false, condition)
+	metrics.ObserveSlavePodsWait(strings.ToUpper(deviceMounter.GetDeviceType()), err == nil, start)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = api.NewMounterError(api.ResultCode_Timeout, "Timed out waiting for the slave pods to be ready")
+	}
 	return readySlavePods, skipSlavePods, err
 }
 
@@ -267,6 +411,26 @@ func (s *DeviceMounterServer) GetSlavePods(devType string, ownerPod *v1.Pod, con
 	return slavePods, nil
 }
 
+// DryRunCreateSlavePods Create the slave pods with server-side dry run,
+// returns the json encoded pods that would be created.
+func (s *DeviceMounterServer) DryRunCreateSlavePods(ctx context.Context, slavePods []*v1.Pod) ([]string, error) {
+	plannedPods := make([]string, 0, len(slavePods))
+	for _, slavePod := range slavePods {
+		pod, err := s.kubeClient.CoreV1().Pods(slavePod.Namespace).Create(ctx, slavePod, metav1.CreateOptions{
+			DryRun: []string{metav1.DryRunAll},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(pod)
+		if err != nil {
+			return nil, err
+		}
+		plannedPods = append(plannedPods, string(data))
+	}
+	return plannedPods, nil
+}
+
 func (s *DeviceMounterServer) CreatePodDisruptionBudget(ctx context.Context, ownerPod *v1.Pod) (*policyv1.PodDisruptionBudget, error) {
 	pdb := policyv1.PodDisruptionBudget{}
 	pdb.Name = ownerPod.Name
@@ -329,16 +493,8 @@ func (s *DeviceMounterServer) MutationPodFunc(devType string, container *api.Con
 	mutaPod.DeletionTimestamp = nil
 	mutaPod.Namespace = ownerPod.Namespace
 	//mutaPod.Finalizers = []string{v1alpha1.Group + "/pod-protection"}
-	getContainerId := func() string {
-		for _, status := range ownerPod.Status.ContainerStatuses {
-			if status.Name == container.Name {
-				return status.ContainerID
-			}
-		}
-		return ""
-	}
 	mutaPod.Annotations[config.DeviceTypeAnnotationKey] = devType
-	mutaPod.Annotations[config.ContainerIdAnnotationKey] = getContainerId()
+	mutaPod.Annotations[config.ContainerIdAnnotationKey] = s.GetContainerID(ownerPod, container)
 
 	mutaPod.Spec.NodeSelector[v1.LabelHostname] = s.nodeName
 
@@ -361,15 +517,23 @@ func (s *DeviceMounterServer) MutationPodFunc(devType string, container *api.Con
 	mutaPod.Spec.TerminationGracePeriodSeconds = pointer.Int64(0)
 }
 
+// GetContainerID Get the runtime id of the container, return empty if the container has not been started.
+func (s *DeviceMounterServer) GetContainerID(pod *v1.Pod, container *api.Container) string {
+	if status, ok := util.GetContainerStatus(pod, container.Name); ok {
+		return status.ContainerID
+	}
+	return ""
+}
+
 func (s *DeviceMounterServer) GetCGroupPath(pod *v1.Pod, container *api.Container) (string, error) {
 	var getFullPath func(string) string
 	switch {
-	case cgroups.IsCgroup2UnifiedMode(): // cgroupv2
+	case util.IsCgroup2UnifiedMode(): // cgroupv2
 		getFullPath = util.GetK8sPodCGroupFullPath
-	case cgroups.IsCgroup2HybridMode():
+	case util.IsCgroup2HybridMode():
 		// If the device controller does not exist, use the path of cgroupv2.
 		getFullPath = util.GetK8sPodDeviceCGroupFullPath
-		if util.PathIsNotExist("/sys/fs/cgroup/devices") {
+		if util.PathIsNotExist(filepath.Join(util.CGroupRoot, "devices")) {
 			getFullPath = util.GetK8sPodCGroupFullPath
 		}
 	default: // cgroupv1
@@ -384,12 +548,12 @@ func (s *DeviceMounterServer) DeviceRuleSetFunc(cgroupPath string, r *configs.Re
 	switch {
 	case len(r.Devices) == 0:
 		klog.V(3).Infoln("no device information to be mounted, skipping device permission settings")
-	case cgroups.IsCgroup2UnifiedMode():
+	case util.IsCgroup2UnifiedMode():
 		klog.V(3).Infoln("use cgroupv2 ebpf device controller")
 		closed, rollback, err = util.SetDeviceRulesByCgroupv2(cgroupPath, r)
-	case cgroups.IsCgroup2HybridMode():
+	case util.IsCgroup2HybridMode():
 		// If the device controller does not exist, use cgroupv2.
-		if util.PathIsNotExist("/sys/fs/cgroup/devices") {
+		if util.PathIsNotExist(filepath.Join(util.CGroupRoot, "devices")) {
 			closed, rollback, err = util.SetDeviceRulesByCgroupv2(cgroupPath, r)
 		} else {
 			rollback, err = util.SetDeviceRulesByCgroupv1(cgroupPath, r)
@@ -397,6 +561,9 @@ func (s *DeviceMounterServer) DeviceRuleSetFunc(cgroupPath string, r *configs.Re
 	default:
 		rollback, err = util.SetDeviceRulesByCgroupv1(cgroupPath, r)
 	}
+	if err != nil {
+		metrics.DeviceRuleFailures.Inc()
+	}
 	return
 }
 
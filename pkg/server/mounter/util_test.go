@@ -3,6 +3,7 @@ package mounter
 import (
 	"testing"
 
+	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -76,3 +77,41 @@ func Test_PatchPod(t *testing.T) {
 		})
 	}
 }
+
+func Test_CheckPodContainer(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "init"},
+				{Name: "sidecar", RestartPolicy: &always},
+			},
+			Containers: []v1.Container{{Name: "main"}},
+			EphemeralContainers: []v1.EphemeralContainer{
+				{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debug"}},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		want    *api.Container
+		wantErr bool
+	}{
+		{name: "main", want: &api.Container{Name: "main", Index: 0, Type: api.ContainerType_Regular}},
+		{name: "sidecar", want: &api.Container{Name: "sidecar", Index: 1, Type: api.ContainerType_Sidecar}},
+		{name: "debug", want: &api.Container{Name: "debug", Index: 0, Type: api.ContainerType_Ephemeral}},
+		{name: "init", wantErr: true},
+		{name: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckPodContainer(pod, &api.Container{Name: tt.name})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
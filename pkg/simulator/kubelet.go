@@ -0,0 +1,175 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+// kubelet Bind the pods selecting the node, allocate the simulated devices to them and serve the allocations
+// through the PodResources API. The device ids of each resource are the indexes "0", "1", ...
+type kubelet struct {
+	nodeName   string
+	kubeClient kubernetes.Interface
+
+	mutex       sync.Mutex
+	devices     map[v1.ResourceName][]types.UID // The pod each device is allocated to.
+	podKeys     map[types.UID]types.NamespacedName
+	allocations map[types.NamespacedName]*podresourcesv1.PodResources
+}
+
+var _ podresourcesv1.PodResourcesListerServer = &kubelet{}
+
+func newKubelet(nodeName string, kubeClient kubernetes.Interface, allocatable map[v1.ResourceName]int) *kubelet {
+	k := &kubelet{
+		nodeName:    nodeName,
+		kubeClient:  kubeClient,
+		devices:     make(map[v1.ResourceName][]types.UID, len(allocatable)),
+		podKeys:     make(map[types.UID]types.NamespacedName),
+		allocations: make(map[types.NamespacedName]*podresourcesv1.PodResources),
+	}
+	for name, count := range allocatable {
+		k.devices[name] = make([]types.UID, count)
+	}
+	return k
+}
+
+// allocate Allocate the devices requested by the containers of the pod, returns false if they are insufficient.
+func (k *kubelet) allocate(pod *v1.Pod) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if _, ok := k.podKeys[pod.UID]; ok {
+		return true
+	}
+	podResources := &podresourcesv1.PodResources{Name: pod.Name, Namespace: pod.Namespace}
+	var allocated []*podresourcesv1.ContainerDevices
+	for _, container := range pod.Spec.Containers {
+		containerResources := &podresourcesv1.ContainerResources{Name: container.Name}
+		names := make([]string, 0, len(container.Resources.Limits))
+		for name := range container.Resources.Limits {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			devices, ok := k.devices[v1.ResourceName(name)]
+			if !ok {
+				continue
+			}
+			quantity := container.Resources.Limits[v1.ResourceName(name)]
+			containerDevices := &podresourcesv1.ContainerDevices{ResourceName: name}
+			for i := 0; i < len(devices) && int64(len(containerDevices.DeviceIds)) < quantity.Value(); i++ {
+				if len(devices[i]) == 0 {
+					devices[i] = pod.UID
+					containerDevices.DeviceIds = append(containerDevices.DeviceIds, strconv.Itoa(i))
+				}
+			}
+			allocated = append(allocated, containerDevices)
+			if int64(len(containerDevices.DeviceIds)) < quantity.Value() {
+				k.release(allocated)
+				return false
+			}
+			containerResources.Devices = append(containerResources.Devices, containerDevices)
+		}
+		podResources.Containers = append(podResources.Containers, containerResources)
+	}
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	k.podKeys[pod.UID] = key
+	k.allocations[key] = podResources
+	return true
+}
+
+// release Release the devices allocated to the containers, called with the lock held.
+func (k *kubelet) release(allocated []*podresourcesv1.ContainerDevices) {
+	for _, containerDevices := range allocated {
+		devices := k.devices[v1.ResourceName(containerDevices.ResourceName)]
+		for _, deviceID := range containerDevices.DeviceIds {
+			index, _ := strconv.Atoi(deviceID)
+			devices[index] = ""
+		}
+	}
+}
+
+func (k *kubelet) removePod(pod *v1.Pod) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	key, ok := k.podKeys[pod.UID]
+	if !ok {
+		return
+	}
+	for _, container := range k.allocations[key].Containers {
+		k.release(container.Devices)
+	}
+	delete(k.podKeys, pod.UID)
+	delete(k.allocations, key)
+}
+
+// syncPod Start the pod selecting the node, or mark it unschedulable when the devices are insufficient.
+func (k *kubelet) syncPod(pod *v1.Pod) {
+	if len(pod.Spec.NodeName) > 0 || pod.Spec.NodeSelector[v1.LabelHostname] != k.nodeName ||
+		pod.DeletionTimestamp != nil || len(pod.Status.Phase) > 0 {
+		return
+	}
+	pod = pod.DeepCopy()
+	ctx := context.Background()
+	if !k.allocate(pod) {
+		pod.Status.Phase = v1.PodPending
+		pod.Status.Conditions = []v1.PodCondition{{
+			Type:    v1.PodScheduled,
+			Status:  v1.ConditionFalse,
+			Reason:  v1.PodReasonUnschedulable,
+			Message: "0/1 nodes are available: 1 Insufficient devices.",
+		}}
+		if _, err := k.kubeClient.CoreV1().Pods(pod.Namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Simulated kubelet failed to update pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		return
+	}
+	pod.Spec.NodeName = k.nodeName
+	runningPodStatus(pod)
+	if _, err := k.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("Simulated kubelet failed to start pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+}
+
+func (k *kubelet) List(_ context.Context, _ *podresourcesv1.ListPodResourcesRequest) (*podresourcesv1.ListPodResourcesResponse, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	resp := &podresourcesv1.ListPodResourcesResponse{}
+	for _, podResources := range k.allocations {
+		resp.PodResources = append(resp.PodResources, podResources)
+	}
+	return resp, nil
+}
+
+func (k *kubelet) Get(_ context.Context, req *podresourcesv1.GetPodResourcesRequest) (*podresourcesv1.GetPodResourcesResponse, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	podResources, ok := k.allocations[types.NamespacedName{Namespace: req.PodNamespace, Name: req.PodName}]
+	if !ok {
+		return nil, fmt.Errorf("pod %s not found in %s", req.PodName, req.PodNamespace)
+	}
+	return &podresourcesv1.GetPodResourcesResponse{PodResources: podResources}, nil
+}
+
+func (k *kubelet) GetAllocatableResources(_ context.Context, _ *podresourcesv1.AllocatableResourcesRequest) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	resp := &podresourcesv1.AllocatableResourcesResponse{}
+	for name, devices := range k.devices {
+		containerDevices := &podresourcesv1.ContainerDevices{ResourceName: string(name)}
+		for i := range devices {
+			containerDevices.DeviceIds = append(containerDevices.DeviceIds, strconv.Itoa(i))
+		}
+		resp.Devices = append(resp.Devices, containerDevices)
+	}
+	return resp, nil
+}
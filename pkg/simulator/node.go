@@ -0,0 +1,393 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coldzerofear/device-mounter/pkg/client"
+	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/coldzerofear/device-mounter/pkg/util"
+	"github.com/google/uuid"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	cgroupdevices "github.com/opencontainers/runc/libcontainer/cgroups/devices"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+// defaultDeviceRules The devices cgroup rules of a new container.
+const defaultDeviceRules = `c 1:3 rwm
+c 1:5 rwm
+c 1:7 rwm
+c 1:8 rwm
+c 1:9 rwm
+c 5:0 rwm
+c 5:1 rwm
+c 5:2 rwm
+c 136:* rwm
+`
+
+// Node A simulated node to test the device mounter without hardware or a cluster. It is made of a fake kube
+// clientset, a kubelet that starts the pods selecting the node and allocates the devices to them through an
+// in-process PodResources server, a cgroup v1 hierarchy in a temp dir and a fake /proc of the container processes.
+// The commands executed in the container namespaces are applied to the root directories under the fake /proc.
+//
+// The node replaces the global state of the util, config and client packages until the test finishes,
+// so the tests using it cannot run in parallel.
+type Node struct {
+	Name        string
+	KubeClient  *fake.Clientset
+	PodInformer cache.SharedIndexInformer
+	PodLister   listerv1.PodLister
+	NodeLister  listerv1.NodeLister
+	CGroupRoot  string
+	ProcRoot    string
+
+	kubelet *kubelet
+	mutex   sync.Mutex
+	nextPid int
+	killed  []int
+}
+
+// NewNode Start a simulated node with the number of devices of each resource, the device ids are the indexes.
+func NewNode(t testing.TB, name string, allocatable map[v1.ResourceName]int) *Node {
+	node := &Node{
+		Name:       name,
+		CGroupRoot: t.TempDir(),
+		ProcRoot:   t.TempDir(),
+		nextPid:    1000,
+	}
+	node.replaceGlobals(t)
+
+	resources := v1.ResourceList{}
+	for resourceName, count := range allocatable {
+		resources[resourceName] = *resource.NewQuantity(int64(count), resource.DecimalSI)
+	}
+	node.KubeClient = fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelHostname: name}},
+		Status:     v1.NodeStatus{Capacity: resources, Allocatable: resources},
+	})
+	// The fake clientset does not generate the names and the uids of the created objects.
+	node.KubeClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		object, err := meta.Accessor(action.(ktesting.CreateAction).GetObject())
+		if err != nil {
+			return false, nil, err
+		}
+		if len(object.GetName()) == 0 && len(object.GetGenerateName()) > 0 {
+			object.SetName(object.GetGenerateName() + utilrand.String(5))
+		}
+		if len(object.GetUID()) == 0 {
+			object.SetUID(uuidUID())
+		}
+		return false, nil, nil
+	})
+	node.kubelet = newKubelet(name, node.KubeClient, allocatable)
+
+	factory := informers.NewSharedInformerFactory(node.KubeClient, 0)
+	podInformer := factory.Core().V1().Pods()
+	_, _ = podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			node.kubelet.syncPod(obj.(*v1.Pod))
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			node.kubelet.syncPod(newObj.(*v1.Pod))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod, ok := obj.(*v1.Pod); ok {
+				node.kubelet.removePod(pod)
+			}
+		},
+	})
+	node.PodInformer = podInformer.Informer()
+	node.PodLister = podInformer.Lister()
+	node.NodeLister = factory.Core().V1().Nodes().Lister()
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	t.Cleanup(func() {
+		close(stopCh)
+		factory.Shutdown()
+	})
+	factory.WaitForCacheSync(stopCh)
+
+	node.servePodResources(t)
+	return node
+}
+
+// replaceGlobals Point the cgroup root, /proc and the namespace executor to the simulated node.
+func (n *Node) replaceGlobals(t testing.TB) {
+	oldCGroupRoot, oldProcRoot := util.CGroupRoot, util.ProcRoot
+	oldUnifiedMode, oldHybridMode := util.IsCgroup2UnifiedMode, util.IsCgroup2HybridMode
+	oldExecutor, oldTestMode, oldDriver := util.NamespaceExecutor, cgroups.TestMode, config.CurrentCGroupDriver
+	oldWriteCGroupFile := util.WriteCGroupFile
+	t.Cleanup(func() {
+		util.CGroupRoot, util.ProcRoot = oldCGroupRoot, oldProcRoot
+		util.IsCgroup2UnifiedMode, util.IsCgroup2HybridMode = oldUnifiedMode, oldHybridMode
+		util.NamespaceExecutor, cgroups.TestMode, config.CurrentCGroupDriver = oldExecutor, oldTestMode, oldDriver
+		util.WriteCGroupFile = oldWriteCGroupFile
+	})
+	util.CGroupRoot, util.ProcRoot = n.CGroupRoot, n.ProcRoot
+	util.IsCgroup2UnifiedMode = func() bool { return false }
+	util.IsCgroup2HybridMode = func() bool { return false }
+	util.NamespaceExecutor = n.execute
+	util.WriteCGroupFile = writeCGroupFile
+	// Allow runc to access the cgroup files in the temp dir, which is not a cgroupfs.
+	cgroups.TestMode = true
+	config.CurrentCGroupDriver = config.CGROUPFS
+}
+
+// servePodResources Serve the PodResources API of the kubelet and connect the global client to it.
+func (n *Node) servePodResources(t testing.TB) {
+	// The unix socket path is limited to 108 bytes, the temp dir of the test may be too long.
+	dir, err := os.MkdirTemp("", "simulator")
+	if err != nil {
+		t.Fatalf("Failed to create the socket dir: %v", err)
+	}
+	socketPath := filepath.Join(dir, "kubelet.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen on %s: %v", socketPath, err)
+	}
+	server := grpc.NewServer()
+	podresourcesv1.RegisterPodResourcesListerServer(server, n.kubelet)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	resClient, err := client.NewPodResourcesClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to connect to the simulated kubelet: %v", err)
+	}
+	client.SetPodResourcesClient(resClient)
+	t.Cleanup(func() {
+		_ = resClient.Close()
+		server.Stop()
+		_ = os.RemoveAll(dir)
+	})
+}
+
+// RunPod Create the running pod on the node, each container runs a process in its own cgroup.
+func (n *Node) RunPod(t testing.TB, pod *v1.Pod) *v1.Pod {
+	pod = pod.DeepCopy()
+	if len(pod.Namespace) == 0 {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+	pod.UID = uuidUID()
+	pod.Spec.NodeName = n.Name
+	runningPodStatus(pod)
+	for _, status := range pod.Status.ContainerStatuses {
+		if err := n.startContainer(pod, status.ContainerID); err != nil {
+			t.Fatalf("Failed to start container %s: %v", status.Name, err)
+		}
+	}
+	pod, err := n.KubeClient.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+	return pod
+}
+
+// startContainer Create the cgroup of the container and its process.
+func (n *Node) startContainer(pod *v1.Pod, containerID string) error {
+	n.mutex.Lock()
+	pid := n.nextPid
+	n.nextPid++
+	n.mutex.Unlock()
+
+	cgroupPath := n.containerCGroupPath(pod, containerID)
+	if err := os.MkdirAll(cgroupPath, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(cgroupPath, "devices.list"), []byte(defaultDeviceRules), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(cgroupPath, "cgroup.procs"), []byte(fmt.Sprintf("%d\n", pid)), 0644); err != nil {
+		return err
+	}
+	procDir := filepath.Join(n.ProcRoot, strconv.Itoa(pid))
+	for _, dir := range []string{"fd", "root/dev"} {
+		if err := os.MkdirAll(filepath.Join(procDir, dir), 0755); err != nil {
+			return err
+		}
+	}
+	cgroupName := strings.TrimPrefix(cgroupPath, filepath.Join(n.CGroupRoot, "devices"))
+	return os.WriteFile(filepath.Join(procDir, "cgroup"), []byte("11:devices:"+cgroupName+"\n"), 0644)
+}
+
+func (n *Node) containerCGroupPath(pod *v1.Pod, containerID string) string {
+	_, id, _ := strings.Cut(containerID, "://")
+	return util.GetK8sPodDeviceCGroupFullPath(filepath.Join(util.NewPodCgroupName(pod).ToCgroupfs(), id))
+}
+
+// GetContainerPid Get the process of the container.
+func (n *Node) GetContainerPid(pod *v1.Pod, containerName string) (int, error) {
+	status, ok := util.GetContainerStatus(pod, containerName)
+	if !ok {
+		return 0, fmt.Errorf("container %s not found", containerName)
+	}
+	pids, err := cgroups.GetPids(n.containerCGroupPath(pod, status.ContainerID))
+	if err != nil || len(pids) == 0 {
+		return 0, fmt.Errorf("process of container %s not found: %v", containerName, err)
+	}
+	return pids[0], nil
+}
+
+// writeCGroupFile Write the cgroup file, the rules written to devices.allow and devices.deny
+// are applied to devices.list like the kernel.
+func writeCGroupFile(dir, file, data string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(data), 0644); err != nil {
+		return err
+	}
+	if file != "devices.allow" && file != "devices.deny" {
+		return nil
+	}
+	emulator, err := util.LoadEmulator(dir)
+	if err != nil {
+		return err
+	}
+	written, err := cgroupdevices.EmulatorFromList(strings.NewReader(data))
+	if err != nil {
+		return err
+	}
+	rules, err := written.Rules()
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		rule.Allow = file == "devices.allow"
+		if err = emulator.Apply(*rule); err != nil {
+			return err
+		}
+	}
+	list := "a *:* rwm\n"
+	if !emulator.IsBlacklist() {
+		if rules, err = emulator.Rules(); err != nil {
+			return err
+		}
+		list = ""
+		for _, rule := range rules {
+			list += rule.CgroupString() + "\n"
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, "devices.list"), []byte(list), 0644)
+}
+
+// ReadCGroupFile Read the file in the devices cgroup of the container, e.g. the last rule written to devices.allow.
+func (n *Node) ReadCGroupFile(pod *v1.Pod, containerName, file string) (string, error) {
+	status, ok := util.GetContainerStatus(pod, containerName)
+	if !ok {
+		return "", fmt.Errorf("container %s not found", containerName)
+	}
+	data, err := os.ReadFile(filepath.Join(n.containerCGroupPath(pod, status.ContainerID), file))
+	return string(data), err
+}
+
+// ReadDeviceFile Read the device file in the root of the process, the content is the type, major and minor of the device.
+func (n *Node) ReadDeviceFile(pid int, path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(n.ProcRoot, strconv.Itoa(pid), "root", path))
+	return string(data), err
+}
+
+// OpenFile Make the process hold the file open.
+func (n *Node) OpenFile(pid int, path string) error {
+	fdDir := filepath.Join(n.ProcRoot, strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(path, filepath.Join(fdDir, strconv.Itoa(len(entries))))
+}
+
+// KilledProcesses Get the processes killed by the commands executed in the container namespaces.
+func (n *Node) KilledProcesses() []int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]int(nil), n.killed...)
+}
+
+// execute Apply the commands of the device mounter executed in the namespaces of the target process,
+// only the commands creating and removing device files and killing processes are supported.
+func (n *Node) execute(_ context.Context, c *util.Config, program string, args ...string) (string, string, error) {
+	if c.Target == 0 {
+		return "", "", fmt.Errorf("Target must be specified ")
+	}
+	root := filepath.Join(n.ProcRoot, strconv.Itoa(c.Target), "root")
+	if util.PathIsNotExist(root) {
+		return "", "", fmt.Errorf("process %d not found", c.Target)
+	}
+	if program != "sh" || len(args) != 2 || args[0] != "-c" {
+		return "", "", fmt.Errorf("unsupported command %s %v", program, args)
+	}
+	fields := strings.Fields(args[1])
+	switch {
+	case len(fields) == 7 && fields[0] == "mknod":
+		// mknod -m <mode> <path> <type> <major> <minor>
+		path := filepath.Join(root, fields[3])
+		if !util.PathIsNotExist(path) {
+			return "", "mknod: " + fields[3] + ": File exists", fmt.Errorf("exit status 1")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", "", err
+		}
+		return "", "", os.WriteFile(path, []byte(strings.Join(fields[4:], " ")), 0666)
+	case len(fields) == 2 && fields[0] == "rm":
+		if err := os.Remove(filepath.Join(root, fields[1])); err != nil {
+			return "", "rm: " + err.Error(), fmt.Errorf("exit status 1")
+		}
+		return "", "", nil
+	case len(fields) > 1 && fields[0] == "kill":
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		for _, field := range fields[1:] {
+			pid, err := strconv.Atoi(field)
+			if err != nil {
+				return "", "", err
+			}
+			n.killed = append(n.killed, pid)
+			// The killed process no longer holds any files open.
+			_ = os.RemoveAll(filepath.Join(n.ProcRoot, field, "fd"))
+		}
+		return "", "", nil
+	default:
+		return "", "", fmt.Errorf("unsupported command %s", args[1])
+	}
+}
+
+// runningPodStatus Set the status of the pod to running with all containers ready.
+func runningPodStatus(pod *v1.Pod) {
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.Conditions = []v1.PodCondition{
+		{Type: v1.PodScheduled, Status: v1.ConditionTrue},
+		{Type: v1.PodReady, Status: v1.ConditionTrue},
+	}
+	pod.Status.ContainerStatuses = nil
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:        container.Name,
+			Ready:       true,
+			Started:     func(b bool) *bool { return &b }(true),
+			State:       v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			ContainerID: "containerd://" + strings.ReplaceAll(uuid.New().String(), "-", ""),
+		})
+	}
+}
+
+func uuidUID() types.UID {
+	return types.UID(uuid.New().String())
+}
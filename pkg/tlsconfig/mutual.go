@@ -0,0 +1,180 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/coldzerofear/device-mounter/pkg/filewatch"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+)
+
+// MutualWatch Watch the certificate, key and CA of the mutual TLS channel between the apiserver and the mounters.
+type MutualWatch interface {
+	AddToFilewatch(watch filewatch.Watch) error
+	Reload()
+	// GetServerConfig Get the server config that requires a client certificate issued by the CA,
+	// whose identity is in the allowed list.
+	GetServerConfig() (*tls.Config, error)
+	// GetClientConfig Get the client config that presents the certificate and verifies the server name with the CA.
+	GetClientConfig(serverName string) (*tls.Config, error)
+}
+
+// NewMutualWatch Create a watch of the certificates in the directory. The identity of a client is the common name
+// or one of the DNS names of its certificate, an empty allowed list accepts all clients issued by the CA.
+func NewMutualWatch(certAndKeyDir, certsName, keyName, caName string, allowedIdentities []string) MutualWatch {
+	return &mutualWatch{
+		certAndKeyDir:     certAndKeyDir,
+		certsName:         certsName,
+		keyName:           keyName,
+		caName:            caName,
+		allowedIdentities: sets.New[string](allowedIdentities...),
+		certError:         fmt.Errorf("certificate not loaded"),
+	}
+}
+
+type mutualWatch struct {
+	lock sync.RWMutex
+
+	certAndKeyDir string
+	certsName     string
+	keyName       string
+	caName        string
+
+	allowedIdentities sets.Set[string]
+
+	certificate *tls.Certificate
+	caPool      *x509.CertPool
+	certError   error
+}
+
+func (w *mutualWatch) AddToFilewatch(watch filewatch.Watch) error {
+	return watch.Add(w.certAndKeyDir, w.Reload)
+}
+
+func (w *mutualWatch) Reload() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.certError = nil
+
+	certificate, err := LoadCertificates(
+		filepath.Join(w.certAndKeyDir, w.certsName),
+		filepath.Join(w.certAndKeyDir, w.keyName),
+	)
+	if err != nil {
+		klog.Errorf("Failed to load mutual TLS certificate: %s", err)
+		w.certError = err
+		return
+	}
+	caPool, err := cert.NewPool(filepath.Join(w.certAndKeyDir, w.caName))
+	if err != nil {
+		klog.Errorf("Failed to load mutual TLS CA: %s", err)
+		w.certError = err
+		return
+	}
+
+	klog.Infof("Loaded mutual TLS certificate.")
+	w.certificate = certificate
+	w.caPool = caPool
+}
+
+func (w *mutualWatch) GetServerConfig() (*tls.Config, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.certError != nil {
+		return nil, w.certError
+	}
+	return &tls.Config{
+		MinVersion:            tls.VersionTLS12,
+		NextProtos:            []string{"h2"},
+		Certificates:          []tls.Certificate{*w.certificate},
+		ClientAuth:            tls.RequireAndVerifyClientCert,
+		ClientCAs:             w.caPool,
+		VerifyPeerCertificate: w.verifyClientIdentity,
+	}, nil
+}
+
+func (w *mutualWatch) GetClientConfig(serverName string) (*tls.Config, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.certError != nil {
+		return nil, w.certError
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The long-lived connections present the rotated certificate when they reconnect.
+		GetClientCertificate: w.getClientCertificate,
+		// RootCAs would pin the CA loaded when the connection is created,
+		// the server certificate is verified with the latest CA instead.
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: w.verifyServerCertificate(serverName),
+		ServerName:            serverName,
+	}, nil
+}
+
+func (w *mutualWatch) getClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.certError != nil {
+		return nil, w.certError
+	}
+	return w.certificate, nil
+}
+
+// verifyServerCertificate Verify the server certificate chain and name with the current CA.
+func (w *mutualWatch) verifyServerCertificate(serverName string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("no server certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, rawCert := range rawCerts {
+			certificate, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return fmt.Errorf("failed to parse server certificate: %v", err)
+			}
+			certs[i] = certificate
+		}
+		w.lock.RLock()
+		caPool := w.caPool
+		w.lock.RUnlock()
+
+		intermediates := x509.NewCertPool()
+		for _, certificate := range certs[1:] {
+			intermediates.AddCert(certificate)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         caPool,
+			Intermediates: intermediates,
+			DNSName:       serverName,
+		})
+		return err
+	}
+}
+
+// verifyClientIdentity Check the verified client certificate against the allowed identities.
+func (w *mutualWatch) verifyClientIdentity(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if w.allowedIdentities.Len() == 0 {
+		return nil
+	}
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+		return fmt.Errorf("no verified client certificate")
+	}
+	leaf := verifiedChains[0][0]
+	if w.allowedIdentities.Has(leaf.Subject.CommonName) {
+		return nil
+	}
+	for _, name := range leaf.DNSNames {
+		if w.allowedIdentities.Has(name) {
+			return nil
+		}
+	}
+	return fmt.Errorf("client %q is not allowed", leaf.Subject.CommonName)
+}
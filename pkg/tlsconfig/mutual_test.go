@@ -0,0 +1,132 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/util/cert"
+)
+
+var _ = Describe("MutualTlsConfig", func() {
+	const (
+		certHostName = "device-mounter"
+		certName     = "tls.crt"
+		keyName      = "tls.key"
+		caName       = "ca.crt"
+	)
+
+	var (
+		certAndKeyDir string
+		mutualWatch   MutualWatch
+	)
+
+	BeforeEach(func() {
+		certBytes, keyBytes, err := cert.GenerateSelfSignedCertKey(certHostName, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		certAndKeyDir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, certName), certBytes, 0666)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, keyName), keyBytes, 0666)).To(Succeed())
+		// The generated chain contains the CA that signed the certificate.
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, caName), certBytes, 0666)).To(Succeed())
+
+		mutualWatch = NewMutualWatch(certAndKeyDir, certName, keyName, caName, []string{"device-mounter-apiserver"})
+	})
+
+	It("should fail if certificate was not loaded", func() {
+		_, err := mutualWatch.GetServerConfig()
+		Expect(err).To(MatchError("certificate not loaded"))
+		_, err = mutualWatch.GetClientConfig(certHostName)
+		Expect(err).To(MatchError("certificate not loaded"))
+	})
+
+	It("should load certificate and CA from files", func() {
+		mutualWatch.Reload()
+
+		serverConfig, err := mutualWatch.GetServerConfig()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serverConfig.Certificates).To(HaveLen(1))
+		Expect(serverConfig.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+		Expect(serverConfig.ClientCAs).ToNot(BeNil())
+
+		clientConfig, err := mutualWatch.GetClientConfig(certHostName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clientConfig.ServerName).To(Equal(certHostName))
+		Expect(clientConfig.VerifyPeerCertificate).ToNot(BeNil())
+		certificate, err := clientConfig.GetClientCertificate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Certificate).ToNot(BeEmpty())
+	})
+
+	It("should fail if CA does not exist", func() {
+		Expect(os.Remove(filepath.Join(certAndKeyDir, caName))).To(Succeed())
+		mutualWatch.Reload()
+
+		_, err := mutualWatch.GetServerConfig()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reload certificate on file change", func() {
+		mockWatch := newMockFileWatch()
+		Expect(mutualWatch.AddToFilewatch(mockWatch)).To(Succeed())
+
+		mockWatch.Trigger(certAndKeyDir)
+
+		_, err := mutualWatch.GetServerConfig()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should verify the server certificate with the current CA", func() {
+		rawCerts := func(certBytes []byte) [][]byte {
+			certs, err := cert.ParseCertsPEM(certBytes)
+			Expect(err).ToNot(HaveOccurred())
+			raws := make([][]byte, len(certs))
+			for i, certificate := range certs {
+				raws[i] = certificate.Raw
+			}
+			return raws
+		}
+		oldCert, err := os.ReadFile(filepath.Join(certAndKeyDir, certName))
+		Expect(err).ToNot(HaveOccurred())
+		mutualWatch.Reload()
+		clientConfig, err := mutualWatch.GetClientConfig(certHostName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clientConfig.VerifyPeerCertificate(rawCerts(oldCert), nil)).To(Succeed())
+		otherName, err := mutualWatch.GetClientConfig("other")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherName.VerifyPeerCertificate(rawCerts(oldCert), nil)).ToNot(Succeed())
+
+		// The config created before the rotation trusts the new CA.
+		newCert, newKey, err := cert.GenerateSelfSignedCertKey(certHostName, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clientConfig.VerifyPeerCertificate(rawCerts(newCert), nil)).ToNot(Succeed())
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, certName), newCert, 0666)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, keyName), newKey, 0666)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(certAndKeyDir, caName), newCert, 0666)).To(Succeed())
+		mutualWatch.Reload()
+		Expect(clientConfig.VerifyPeerCertificate(rawCerts(newCert), nil)).To(Succeed())
+		Expect(clientConfig.VerifyPeerCertificate(rawCerts(oldCert), nil)).ToNot(Succeed())
+	})
+
+	It("should only allow clients in the allowed list", func() {
+		mutualWatch.Reload()
+		serverConfig, err := mutualWatch.GetServerConfig()
+		Expect(err).ToNot(HaveOccurred())
+
+		allowed := &x509.Certificate{Subject: pkix.Name{CommonName: "device-mounter-apiserver"}}
+		Expect(serverConfig.VerifyPeerCertificate(nil, [][]*x509.Certificate{{allowed}})).To(Succeed())
+
+		allowedByDNS := &x509.Certificate{DNSNames: []string{"device-mounter-apiserver"}}
+		Expect(serverConfig.VerifyPeerCertificate(nil, [][]*x509.Certificate{{allowedByDNS}})).To(Succeed())
+
+		denied := &x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}}
+		Expect(serverConfig.VerifyPeerCertificate(nil, [][]*x509.Certificate{{denied}})).
+			To(MatchError(`client "intruder" is not allowed`))
+	})
+})
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+)
+
+// CapacityCalculator Compute the resources of the node that are still free.
+type CapacityCalculator interface {
+	FreeResources(node *v1.Node) (v1.ResourceList, error)
+}
+
+type podCapacityCalculator struct {
+	podLister listerv1.PodLister
+}
+
+// NewCapacityCalculator Create a calculator that subtracts the requests of the non-terminated pods on the node
+// from the allocatable resources, the lister should be backed by the informer of the pods on the node.
+func NewCapacityCalculator(podLister listerv1.PodLister) CapacityCalculator {
+	return &podCapacityCalculator{podLister: podLister}
+}
+
+func (c *podCapacityCalculator) FreeResources(node *v1.Node) (v1.ResourceList, error) {
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	free := node.Status.Allocatable.DeepCopy()
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node.Name || isPodTerminated(pod) {
+			continue
+		}
+		requests, limits := resourcehelper.PodRequestsAndLimits(pod)
+		// Extended resources only specified in limits are requested with the same amount.
+		for name, quantity := range limits {
+			if _, ok := requests[name]; !ok {
+				requests[name] = quantity
+			}
+		}
+		for name, quantity := range requests {
+			if allocatable, ok := free[name]; ok {
+				allocatable.Sub(quantity)
+				free[name] = allocatable
+			}
+		}
+	}
+	return free, nil
+}
+
+// isPodTerminated Whether the pod has released its resources.
+func isPodTerminated(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// CheckFreeResourcesInNode Check whether the free resources of the node satisfy the request,
+// only the allocatable resources are checked when no calculator is set.
+func CheckFreeResourcesInNode(calculator CapacityCalculator, node *v1.Node, request map[v1.ResourceName]resource.Quantity) error {
+	if node == nil {
+		return fmt.Errorf("unknown node")
+	}
+	free := node.Status.Allocatable
+	if calculator != nil {
+		var err error
+		if free, err = calculator.FreeResources(node); err != nil {
+			return fmt.Errorf("failed to compute free resources: %v", err)
+		}
+	}
+	var insufficient []string
+	for name, quantity := range request {
+		freeQuantity, ok := free[name]
+		if !ok {
+			insufficient = append(insufficient, fmt.Sprintf("%s (requested %s, free 0)", name, quantity.String()))
+		} else if freeQuantity.Value() < quantity.Value() {
+			insufficient = append(insufficient, fmt.Sprintf("%s (requested %s, free %s)",
+				name, quantity.String(), freeQuantity.String()))
+		}
+	}
+	if len(insufficient) > 0 {
+		sort.Strings(insufficient)
+		return fmt.Errorf("Insufficient node resources: %s", strings.Join(insufficient, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestGPUPod(name string, gpus string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{
+			NodeName: "node",
+			Containers: []v1.Container{{
+				Name: "main",
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"nvidia.com/gpu": resource.MustParse(gpus)},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func Test_CheckFreeResourcesInNode(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+		},
+	}
+	request := map[v1.ResourceName]resource.Quantity{"nvidia.com/gpu": resource.MustParse("4")}
+	assert.NoError(t, CheckFreeResourcesInNode(nil, node, request))
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(newTestGPUPod("running", "6", v1.PodRunning)))
+	assert.NoError(t, indexer.Add(newTestGPUPod("succeeded", "2", v1.PodSucceeded)))
+	calculator := NewCapacityCalculator(listerv1.NewPodLister(indexer))
+
+	free, err := calculator.FreeResources(node)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), free.Name("nvidia.com/gpu", resource.DecimalSI).Value())
+	assert.EqualError(t, CheckFreeResourcesInNode(calculator, node, request),
+		"Insufficient node resources: nvidia.com/gpu (requested 4, free 2)")
+	assert.NoError(t, CheckFreeResourcesInNode(calculator, node, map[v1.ResourceName]resource.Quantity{
+		"nvidia.com/gpu": resource.MustParse("2"),
+	}))
+}
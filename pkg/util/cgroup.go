@@ -15,6 +15,7 @@ import (
 
 	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/config"
+	"github.com/google/uuid"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/runc/libcontainer/cgroups/devices"
 	cgroupsystemd "github.com/opencontainers/runc/libcontainer/cgroups/systemd"
@@ -60,6 +61,17 @@ func (c *Config) Execute(program string, args ...string) (string, string, error)
 // ExecuteContext the given program using the given nsenter configuration and given context
 // and return stdout/stderr or an error if command has failed
 func (c *Config) ExecuteContext(ctx context.Context, program string, args ...string) (string, string, error) {
+	return NamespaceExecutor(ctx, c, program, args...)
+}
+
+// Executor Execute the program in the namespaces of the target process.
+type Executor func(ctx context.Context, c *Config, program string, args ...string) (string, string, error)
+
+// NamespaceExecutor The executor of the commands in the container namespaces,
+// replaced by the simulated node in tests.
+var NamespaceExecutor Executor = nsenterExecute
+
+func nsenterExecute(ctx context.Context, c *Config, program string, args ...string) (string, string, error) {
 	cmd, err := c.buildCommand(ctx)
 	if err != nil {
 		return "", "", fmt.Errorf("Error while building command: %v ", err)
@@ -225,18 +237,34 @@ func KillRunningProcesses(config *Config, processes []int) error {
 	return nil
 }
 
+// CGroupRoot The mount point of the cgroup filesystem.
+var CGroupRoot = "/sys/fs/cgroup"
+
+var (
+	// IsCgroup2UnifiedMode Whether the node is in the cgroup v2 unified mode, replaced by the simulated node in tests.
+	IsCgroup2UnifiedMode = cgroups.IsCgroup2UnifiedMode
+	// IsCgroup2HybridMode Whether the node is in the cgroup v1 and v2 hybrid mode, replaced by the simulated node in tests.
+	IsCgroup2HybridMode = cgroups.IsCgroup2HybridMode
+	// WriteCGroupFile Write the file of the cgroup, replaced by the simulated node in tests to apply the device rules to devices.list.
+	WriteCGroupFile = cgroups.WriteFile
+)
+
 func GetK8sPodDeviceCGroupFullPath(podCGroupPath string) string {
-	return filepath.Join("/sys/fs/cgroup/devices", podCGroupPath)
+	return filepath.Join(CGroupRoot, "devices", podCGroupPath)
 }
 
 func GetK8sPodCGroupFullPath(podCGroupPath string) string {
-	return filepath.Join("/sys/fs/cgroup", podCGroupPath)
+	return filepath.Join(CGroupRoot, podCGroupPath)
 }
 
+// GetContainerStatus Find the status of the container by name, including init containers and ephemeral containers.
 func GetContainerStatus(pod *v1.Pod, containerName string) (*v1.ContainerStatus, bool) {
-	for i, status := range pod.Status.ContainerStatuses {
-		if status.Name == containerName {
-			return &pod.Status.ContainerStatuses[i], true
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.ContainerStatuses,
+		pod.Status.InitContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for i, status := range statuses {
+			if status.Name == containerName {
+				return &statuses[i], true
+			}
 		}
 	}
 	return nil, false
@@ -292,6 +320,62 @@ func (cgroupName CgroupName) ToCgroupfs() string {
 	return "/" + path.Join(cgroupName...)
 }
 
+// podCgroupComponents The last components of the cgroup paths of the pod in the cgroupfs and the systemd formats,
+// e.g. pod1234-abcd and kubepods-burstable-pod1234_abcd.slice.
+func podCgroupComponents(pod *v1.Pod) []string {
+	cgroupName := NewPodCgroupName(pod)
+	return []string{path.Base(cgroupName.ToCgroupfs()), path.Base(cgroupName.ToSystemd())}
+}
+
+// isPodCgroupComponent Whether the component of a cgroup path is the cgroup of a pod, see podCgroupComponents.
+func isPodCgroupComponent(component string) bool {
+	if strings.HasPrefix(component, "kubepods") && strings.HasSuffix(component, ".slice") {
+		return strings.Contains(component, "-pod")
+	}
+	_, err := uuid.Parse(strings.TrimPrefix(component, "pod"))
+	return strings.HasPrefix(component, "pod") && err == nil
+}
+
+// FindPodByCGroup Find the pod that the process belongs to by matching the content of /proc/<pid>/cgroup with the
+// cgroup paths of the pods. The path of a process in another cgroup namespace is relative to the namespace root,
+// e.g. /../../kubepods-burstable-pod1234_abcd.slice/..., so the pods are matched by the components of the paths.
+// Returns false when the process does not belong to a pod, and an error when the pod is not in the list.
+func FindPodByCGroup(content string, pods []*v1.Pod) (*v1.Pod, bool, error) {
+	podComponents := make(map[string]*v1.Pod, 2*len(pods))
+	for _, pod := range pods {
+		for _, component := range podCgroupComponents(pod) {
+			podComponents[component] = pod
+		}
+	}
+	inPod := false
+	for _, line := range strings.Split(content, "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, component := range strings.Split(parts[2], "/") {
+			if pod, ok := podComponents[component]; ok {
+				return pod, true, nil
+			}
+			inPod = inPod || isPodCgroupComponent(component)
+		}
+	}
+	if inPod {
+		return nil, true, fmt.Errorf("the pod of the process is not found on the node")
+	}
+	return nil, false, nil
+}
+
+// GetProcessCGroup Read the content of /proc/<pid>/cgroup.
+func GetProcessCGroup(pid int32) (string, error) {
+	content, err := os.ReadFile(filepath.Join(ProcRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
 func GetK8sPodCGroupPath(pod *v1.Pod, container *api.Container,
 	getFullPath func(string) string) (string, error) {
 	var (
@@ -464,7 +548,7 @@ func SetDeviceRulesByCgroupv1(path string, r *configs.Resources) (func() error,
 		if rule.Allow {
 			file = "devices.allow"
 		}
-		if err := cgroups.WriteFile(path, file, rule.CgroupString()); err != nil {
+		if err := WriteCGroupFile(path, file, rule.CgroupString()); err != nil {
 			return rollback, err
 		}
 	}
@@ -472,10 +556,6 @@ func SetDeviceRulesByCgroupv1(path string, r *configs.Resources) (func() error,
 	// Final safety check -- ensure that the resulting state is what was
 	// requested. This is only really correct for white-lists, but for
 	// black-lists we can at least check that the cgroup is in the right mode.
-	//
-	// This safety-check is skipped for the unit tests because we cannot
-	// currently mock devices.list correctly.
-
 	currentAfter, err := LoadEmulator(path)
 	if err != nil {
 		return rollback, err
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_FindPodByCGroup(t *testing.T) {
+	burstable := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "burstable", UID: "0f5b1a3e-7c1d-4e8a-9b2f-3d4c5e6f7a8b"},
+		Status:     v1.PodStatus{QOSClass: v1.PodQOSBurstable},
+	}
+	guaranteed := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "guaranteed", UID: "1a2b3c4d-7c1d-4e8a-9b2f-3d4c5e6f7a8b"},
+		Status:     v1.PodStatus{QOSClass: v1.PodQOSGuaranteed},
+	}
+	pods := []*v1.Pod{burstable, guaranteed}
+	tests := []struct {
+		name    string
+		content string
+		want    *v1.Pod
+		inPod   bool
+		wantErr bool
+	}{
+		{
+			name:    "cgroupfs v1",
+			content: "12:devices:/kubepods/burstable/pod0f5b1a3e-7c1d-4e8a-9b2f-3d4c5e6f7a8b/4d3c2b1a\n0::/\n",
+			want:    burstable,
+			inPod:   true,
+		},
+		{
+			name:    "systemd v2",
+			content: "0::/kubepods.slice/kubepods-pod1a2b3c4d_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice/cri-containerd-4d3c2b1a.scope\n",
+			want:    guaranteed,
+			inPod:   true,
+		},
+		{
+			name:    "another cgroup namespace",
+			content: "0::/../../kubepods-burstable-pod0f5b1a3e_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice/cri-containerd-4d3c2b1a.scope\n",
+			want:    burstable,
+			inPod:   true,
+		},
+		{
+			name:    "unknown pod in another cgroup namespace",
+			content: "0::/../../pod9f5b1a3e-7c1d-4e8a-9b2f-3d4c5e6f7a8b/4d3c2b1a\n",
+			inPod:   true,
+			wantErr: true,
+		},
+		{
+			name:    "unknown pod",
+			content: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod9f5b1a3e_7c1d_4e8a_9b2f_3d4c5e6f7a8b.slice\n",
+			inPod:   true,
+			wantErr: true,
+		},
+		{
+			name:    "host process",
+			content: "0::/system.slice/sshd.service\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, inPod, err := FindPodByCGroup(tt.content, pods)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.inPod, inPod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// ProcRoot The root of the proc filesystem.
+var ProcRoot = "/proc"
+
+// GetProcessesOpeningFiles Find the processes holding any of the files open, by the open files under /proc.
+func GetProcessesOpeningFiles(pids []int, files sets.String) []int {
+	processes := sets.NewInt()
+	if files.Len() == 0 {
+		return processes.List()
+	}
+	for _, pid := range pids {
+		fdDir := filepath.Join(ProcRoot, strconv.Itoa(pid), "fd")
+		entries, err := os.ReadDir(fdDir)
+		if err != nil {
+			// The process may have exited.
+			klog.V(4).Infof("Failed to read open files of process %d: %v", pid, err)
+			continue
+		}
+		for _, entry := range entries {
+			target, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+			if err == nil && files.Has(target) {
+				processes.Insert(pid)
+				break
+			}
+		}
+	}
+	return processes.List()
+}
@@ -6,6 +6,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/coldzerofear/device-mounter/pkg/api"
 	"github.com/coldzerofear/device-mounter/pkg/config"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	"golang.org/x/sys/unix"
@@ -19,6 +20,27 @@ func IsSidecar(container v1.Container) bool {
 	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
 }
 
+// GetContainerSpec Get the spec of the target container according to its type and index.
+func GetContainerSpec(pod *v1.Pod, container *api.Container) (*v1.Container, bool) {
+	index := int(container.GetIndex())
+	switch container.GetType() {
+	case api.ContainerType_Sidecar:
+		if index < len(pod.Spec.InitContainers) {
+			return &pod.Spec.InitContainers[index], true
+		}
+	case api.ContainerType_Ephemeral:
+		if index < len(pod.Spec.EphemeralContainers) {
+			spec := v1.Container(pod.Spec.EphemeralContainers[index].EphemeralContainerCommon)
+			return &spec, true
+		}
+	default:
+		if index < len(pod.Spec.Containers) {
+			return &pod.Spec.Containers[index], true
+		}
+	}
+	return nil, false
+}
+
 func LoopRetry(retryCount uint, interval time.Duration, conditionFunc wait.ConditionFunc) error {
 	var err error
 	var done bool